@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/statusline"
+	"go-commute/internal/vbb"
+)
+
+// Exit codes of `berrrr check`
+const (
+	checkOK         = 0
+	checkDelayed    = 1
+	checkDisruption = 2
+	checkAPIFailure = 3
+	checkUsage      = 64 // bad flags or template, not the route's health
+)
+
+// runCheck reports the health of a route through its exit code so cron jobs
+// and scripts can branch on it without parsing output
+func runCheck(args []string) int {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	from := fs.String("from", "", "origin station ID, name, alias or LAT,LON (default: last route)")
+	to := fs.String("to", "", "destination station ID, name or alias (default: last route)")
+	threshold := fs.Int("threshold", cfg.Notify.Threshold(), "delay in minutes that counts as delayed")
+	window := fs.Duration("window", 30*time.Minute, "only consider journeys leaving within this window")
+	verbose := fs.Bool("v", false, "print a one-line summary")
+	format := fs.String("format", cfg.Templates.Status, "Go template for the summary, e.g. '{{.Line}} in {{.In}} min'; implies -v")
+	cassette := cassetteFlags(fs)
+	// flag's own exit code would read as a disruption
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return checkOK
+	} else if err != nil {
+		return checkUsage
+	}
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return checkAPIFailure
+	}
+
+	var tmpl *template.Template
+	if *format != "" {
+		var err error
+		if tmpl, err = statusline.Parse("status", *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --format: %v\n", err)
+			return checkUsage
+		}
+		*verbose = true
+	}
+
+	ctx := context.Background()
+	origin, dest := cfg.LastOrigin, cfg.LastDest
+	var err error
+	if *from != "" {
+		if origin, err = resolveStation(ctx, cfg, *from); err != nil {
+			return checkFailed(*verbose, err)
+		}
+	}
+	if *to != "" {
+		if dest, err = resolveStation(ctx, cfg, *to); err != nil {
+			return checkFailed(*verbose, err)
+		}
+	}
+
+	journeys, err := vbb.Default.Journeys(ctx, origin.ID, dest.ID, vbb.JourneyOptions{}.From(origin))
+	if err != nil {
+		return checkFailed(*verbose, err)
+	}
+	journeys, _ = cfg.Avoid.Filter(journeys, origin, dest)
+
+	code, reason := routeHealth(journeys, *threshold, *window)
+	switch {
+	case tmpl != nil:
+		f := statusline.Collect(cfg.Label(origin), cfg.Label(dest), journeys, time.Now())
+		f.Status = reason
+		fmt.Println(statusline.Render(tmpl, f))
+	case *verbose:
+		fmt.Printf("%s: %s\n", model.RouteName(origin, dest), reason)
+	}
+	return code
+}
+
+func checkFailed(verbose bool, err error) int {
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return checkAPIFailure
+}
+
+// routeHealth classifies the upcoming journeys, returning the most severe
+// status found and a short explanation. A cancelled journey counts as a
+// disruption.
+func routeHealth(journeys []model.Journey, threshold int, window time.Duration) (int, string) {
+	now := time.Now()
+	var upcoming []model.Journey
+	for _, j := range journeys {
+		if j.LeaveAt.Before(now) {
+			continue
+		}
+		if j.LeaveAt.Sub(now) <= window || len(upcoming) == 0 {
+			upcoming = append(upcoming, j)
+		}
+	}
+	if len(upcoming) == 0 {
+		return checkDisruption, "no upcoming journeys"
+	}
+
+	code, reason := checkOK, ""
+	for _, j := range upcoming {
+		if reason == "" && !j.Cancelled() {
+			reason = fmt.Sprintf("running normally, next at %s", model.FormatTime(j.LeaveAt))
+		}
+		for _, leg := range j.Legs {
+			if leg.Cancelled {
+				return checkDisruption, fmt.Sprintf("%s %s cancelled", leg.Line, model.FormatTime(leg.Departure))
+			}
+			if len(leg.ServiceStatus) > 0 {
+				return checkDisruption, fmt.Sprintf("%s disrupted: %s", leg.Line, leg.ServiceStatus[0])
+			}
+			if code == checkOK && leg.DepDelay/60 >= threshold {
+				code = checkDelayed
+				reason = fmt.Sprintf("%s %s delayed by %d min", leg.Line, model.FormatTime(leg.Departure), leg.DepDelay/60)
+			}
+		}
+	}
+	return code, reason
+}
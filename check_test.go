@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestRouteHealth(t *testing.T) {
+	now := time.Now()
+	journey := func(in time.Duration, leg model.Leg) model.Journey {
+		leg.Line = "S5"
+		leg.Departure = now.Add(in)
+		return model.Journey{LeaveAt: now.Add(in), ArriveAt: now.Add(in + 20*time.Minute), Legs: []model.Leg{leg}}
+	}
+	onTime := journey(5*time.Minute, model.Leg{})
+	bitLate := journey(10*time.Minute, model.Leg{DepDelay: 120})
+	late := journey(15*time.Minute, model.Leg{DepDelay: 360})
+	lateLater := journey(45*time.Minute, model.Leg{DepDelay: 600})
+	disrupted := journey(20*time.Minute, model.Leg{ServiceStatus: []string{"Signal failure at Ostkreuz"}})
+	cancelled := journey(8*time.Minute, model.Leg{Cancelled: true})
+	gone := journey(-5*time.Minute, model.Leg{Cancelled: true})
+
+	tests := []struct {
+		name     string
+		journeys []model.Journey
+		want     int
+	}{
+		{"nothing found", nil, checkDisruption},
+		{"all gone", []model.Journey{gone}, checkDisruption},
+		{"on time", []model.Journey{gone, onTime, bitLate}, checkOK},
+		{"delayed", []model.Journey{onTime, bitLate, late}, checkDelayed},
+		{"delayed past the window", []model.Journey{onTime, lateLater}, checkOK},
+		{"only one past the window", []model.Journey{lateLater}, checkDelayed},
+		{"disrupted", []model.Journey{onTime, late, disrupted}, checkDisruption},
+		{"cancelled", []model.Journey{onTime, cancelled, bitLate}, checkDisruption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := routeHealth(tt.journeys, 5, 30*time.Minute); got != tt.want {
+				t.Errorf("got %d (%s), want %d", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUsage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"-bogus"}},
+		{"bad template", []string{"--format", "{{.Line"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runCheck(tt.args); got != checkUsage {
+				t.Errorf("got %d, want %d", got, checkUsage)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/logging"
+	"go-commute/internal/model"
+	"go-commute/internal/provider"
+	"go-commute/internal/vbb"
+)
+
+// command is a non-interactive subcommand; it returns the process exit code
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"check":        {"check [--from STATION] [--to STATION] [--threshold MIN] [-v] [--format TEMPLATE] [--record DIR | --replay DIR]", runCheck},
+		"compare":      {"compare [--days 30] <favorite> <favorite>", runCompare},
+		"completion":   {"completion bash|zsh|fish", runCompletion},
+		"daemon":       {"daemon [--interval 2m] [--record DIR | --replay DIR]", runDaemon},
+		"destinations": {"destinations [--from STATION] [--record DIR | --replay DIR] <destination> <destination>...", runDestinations},
+		"digest":       {"digest [--markdown] [--window 1h] [--record DIR | --replay DIR]", runDigest},
+		"export":       {"export [--data history|diary] [--format csv|json] [--since DATE] [--until DATE] [-o FILE]", runExport},
+		"meet":         {"meet --with STATION [--from STATION] [--within 10m] [--record DIR | --replay DIR] [DESTINATION]", runMeet},
+		"nearby":       {"nearby [--radius 500] [--walk 80] [--record DIR | --replay DIR] [LAT,LON]", runNearby},
+		"resolve":      {"resolve <query>", runResolve},
+	}
+}
+
+func commandNames() []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: berrrr [--provider NAME] [--from STATION] [--to STATION] [--no-animations] [--debug] [--record DIR | --replay DIR]\n")
+	for _, name := range commandNames() {
+		fmt.Fprintf(os.Stderr, "       berrrr %s\n", commands[name].usage)
+	}
+}
+
+func runResolve(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: berrrr resolve <query>")
+		return 2
+	}
+	query := strings.Join(args, " ")
+
+	// Aliases come first so shell completion offers them before the API's names
+	cfg := config.Load()
+	aliases := cfg.MatchAliases(query)
+	for _, alias := range aliases {
+		fmt.Printf("%s\t%s\n", alias, cfg.Aliases[alias])
+	}
+
+	stations, err := vbb.Default.SearchStations(context.Background(), query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(stations) == 0 && len(aliases) == 0 {
+		return 1
+	}
+	for _, s := range stations {
+		fmt.Printf("%s\t%s\n", s.ID, s.Name)
+	}
+	return 0
+}
+
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: berrrr completion bash|zsh|fish")
+		return 2
+	}
+	names := strings.Join(commandNames(), " ")
+	providers := strings.Join(provider.Names(), " ")
+
+	switch args[0] {
+	case "bash":
+		fmt.Printf(`_berrrr() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        --from|--to)
+            [ ${#cur} -ge 2 ] && COMPREPLY=($(berrrr resolve "$cur" 2>/dev/null | cut -f1))
+            return ;;
+        completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            return ;;
+        --provider)
+            COMPREPLY=($(compgen -W "%s" -- "$cur"))
+            return ;;
+        --record|--replay)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            return ;;
+    esac
+    if [ $COMP_CWORD -eq 1 ]; then
+        COMPREPLY=($(compgen -W "%s --provider --from --to --no-animations --debug --record --replay" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "--provider --from --to --no-animations --debug --record --replay" -- "$cur"))
+    fi
+}
+complete -F _berrrr berrrr
+`, providers, names)
+	case "zsh":
+		fmt.Printf(`#compdef berrrr
+
+_berrrr_stations() {
+    local -a stations
+    [[ ${#PREFIX} -ge 2 ]] || return
+    stations=(${(f)"$(berrrr resolve "$PREFIX" 2>/dev/null | sed 's/:/\\:/g; s/\t/:/')"})
+    _describe 'station' stations
+}
+
+_berrrr() {
+    _arguments \
+        '--provider[hafas-rest preset]:provider:(%s)' \
+        '--from[origin station]:station:_berrrr_stations' \
+        '--to[destination station]:station:_berrrr_stations' \
+        '--no-animations[update once a second without spinners]' \
+        '--debug[write a debug log]' \
+        '--record[save API responses into a cassette]:directory:_files -/' \
+        '--replay[answer API requests from a cassette]:directory:_files -/' \
+        '1:command:(%s)' \
+        '*::arg:->args'
+    case $words[1] in
+        completion) _values 'shell' bash zsh fish ;;
+    esac
+}
+
+compdef _berrrr berrrr
+`, providers, names)
+	case "fish":
+		fmt.Printf(`complete -c berrrr -f
+complete -c berrrr -n '__fish_use_subcommand' -a '%s'
+complete -c berrrr -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'
+complete -c berrrr -l provider -x -d 'Hafas-rest preset' -a '%s'
+complete -c berrrr -l from -x -d 'Origin station' -a '(berrrr resolve (commandline -ct) 2>/dev/null)'
+complete -c berrrr -l to -x -d 'Destination station' -a '(berrrr resolve (commandline -ct) 2>/dev/null)'
+complete -c berrrr -l no-animations -d 'Update once a second without spinners'
+complete -c berrrr -l debug -d 'Write a debug log'
+complete -c berrrr -l record -x -d 'Save API responses into a cassette' -a '(__fish_complete_directories)'
+complete -c berrrr -l replay -x -d 'Answer API requests from a cassette' -a '(__fish_complete_directories)'
+`, names, providers)
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported shell %q\n", args[0])
+		return 2
+	}
+	return 0
+}
+
+// parseFlags handles the interactive mode's flags and applies --from/--to
+// to the config before the TUI starts. It returns the debug log when
+// --debug opened one, for closing on exit.
+func parseFlags(cfg *config.Config, args []string) (*os.File, error) {
+	fs := flag.NewFlagSet("berrrr", flag.ExitOnError)
+	fs.Usage = usage
+	from := fs.String("from", "", "origin station ID, name, alias or LAT,LON")
+	to := fs.String("to", "", "destination station ID, name or alias")
+	providerName := fs.String("provider", "", "switch to a hafas-rest preset: "+strings.Join(provider.Names(), ", "))
+	noAnimations := fs.Bool("no-animations", false, "no spinners or flashing; update once a second")
+	debug := fs.Bool("debug", false, "write a debug log to "+logging.Path())
+	cassette := cassetteFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		usage()
+		return nil, fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	cfg.ReducedMotion = *noAnimations
+
+	// Switching providers starts over from its default stations, as the
+	// old ones mean nothing to the new API
+	switched := *providerName != "" && *providerName != cfg.Preset().Name
+	if switched {
+		preset, err := provider.Lookup(*providerName)
+		if err != nil {
+			return nil, fmt.Errorf("--provider: %w", err)
+		}
+		cfg.Provider = preset.Name
+		cfg.LastOrigin, cfg.LastDest = preset.Home, preset.Work
+		vbb.Default.BaseURL = preset.BaseURL
+		model.SetDisplayZone(cfg.Zone())
+	}
+
+	var logFile *os.File
+	if *debug {
+		f, err := logging.Open()
+		if err != nil {
+			return nil, fmt.Errorf("--debug: %w", err)
+		}
+		logFile = f
+		slog.Info("starting", "origin", cfg.LastOrigin.Name, "dest", cfg.LastDest.Name)
+	}
+
+	if err := cassette(); err != nil {
+		return logFile, err
+	}
+
+	// Without --from or --to, the favorite scheduled for this time of day
+	// is shown, like the way home in the evening
+	if *from == "" && *to == "" && !switched {
+		if fav, ok := cfg.ScheduledRoute(time.Now()); ok {
+			cfg.LastOrigin, cfg.LastDest = fav.Origin, fav.Dest
+			cfg.RouteChosen = true
+		}
+	}
+
+	if *from != "" {
+		station, err := resolveStation(context.Background(), *cfg, *from)
+		if err != nil {
+			return logFile, fmt.Errorf("--from: %w", err)
+		}
+		cfg.LastOrigin = station
+		cfg.RouteChosen = true
+	}
+	if *to != "" {
+		station, err := resolveStation(context.Background(), *cfg, *to)
+		if err != nil {
+			return logFile, fmt.Errorf("--to: %w", err)
+		}
+		cfg.LastDest = station
+		cfg.RouteChosen = true
+	}
+	return logFile, nil
+}
+
+// cassetteFlags adds --record and --replay to a command's flags. The
+// returned func points the API client at the cassette once they're parsed.
+func cassetteFlags(fs *flag.FlagSet) func() error {
+	record := fs.String("record", "", "save every API response into cassette `DIR`")
+	replay := fs.String("replay", "", "answer API requests from cassette `DIR` instead of the network")
+	return func() error {
+		switch {
+		case *record != "" && *replay != "":
+			return fmt.Errorf("--record and --replay can't be combined")
+		case *record != "":
+			transport, err := vbb.Record(*record, http.DefaultTransport)
+			if err != nil {
+				return fmt.Errorf("--record: %w", err)
+			}
+			vbb.Default.HTTP.Transport = transport
+		case *replay != "":
+			transport, err := vbb.Replay(*replay)
+			if err != nil {
+				return fmt.Errorf("--replay: %w", err)
+			}
+			vbb.Default.HTTP.Transport = transport
+		}
+		return nil
+	}
+}
+
+// resolveStation is ResolveStation that first expands the config's aliases.
+// Coordinates like 52.5219,13.4132 are an address to walk from.
+func resolveStation(ctx context.Context, cfg config.Config, query string) (model.Station, error) {
+	target, ok := cfg.AliasTarget(query)
+	if !ok {
+		target = query
+	}
+	if at, err := model.ParseCoordinates(target); err == nil {
+		return model.AddressAt(query, at), nil
+	}
+	station, err := vbb.Default.ResolveStation(ctx, target)
+	if err != nil && ok {
+		return model.Station{}, fmt.Errorf("alias %q: %w", query, err)
+	}
+	return station, err
+}
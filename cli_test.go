@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-commute/internal/vbb"
+)
+
+func TestRunCompletion(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+		want string // in the script
+	}{
+		{[]string{"bash"}, 0, "complete -F _berrrr berrrr"},
+		{[]string{"zsh"}, 0, "#compdef berrrr"},
+		{[]string{"fish"}, 0, "complete -c berrrr -l from"},
+		{[]string{"powershell"}, 2, ""},
+		{nil, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var code int
+			out := captureStdout(t, func() { code = runCompletion(tt.args) })
+			if code != tt.code {
+				t.Fatalf("exit code %d, want %d", code, tt.code)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("script lacks %q:\n%s", tt.want, out)
+			}
+			if tt.code == 0 && !strings.Contains(out, strings.Join(commandNames(), " ")) {
+				t.Error("script doesn't offer the subcommands")
+			}
+		})
+	}
+}
+
+// captureStdout is what f prints, usage errors on stderr included
+func TestCassetteFlags(t *testing.T) {
+	cassette := t.TempDir()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		swapped bool // whether the client got a new transport
+	}{
+		{"neither", nil, false, false},
+		{"record", []string{"--record", filepath.Join(cassette, "new")}, false, true},
+		{"replay", []string{"--replay", cassette}, false, true},
+		{"replay from nowhere", []string{"--replay", filepath.Join(cassette, "missing")}, true, false},
+		{"both", []string{"--record", cassette, "--replay", cassette}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := vbb.Default.HTTP.Transport
+			defer func() { vbb.Default.HTTP.Transport = transport }()
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			apply := cassetteFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := apply(); (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want an error %v", err, tt.wantErr)
+			}
+			if swapped := vbb.Default.HTTP.Transport != transport; swapped != tt.swapped {
+				t.Errorf("transport swapped %v, want %v", swapped, tt.swapped)
+			}
+		})
+	}
+}
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-done
+}
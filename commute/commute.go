@@ -0,0 +1,39 @@
+// Package commute is the importable core of berrrr: it plans journeys
+// through the VBB API and returns them in the same shape the TUI shows, so
+// other frontends can share it.
+package commute
+
+import (
+	"context"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+type (
+	Station = model.Station
+	Journey = model.Journey
+	Leg     = model.Leg
+)
+
+// SearchStations looks up stops matching a free-text query
+func SearchStations(ctx context.Context, query string) ([]Station, error) {
+	return vbb.Default.SearchStations(ctx, query)
+}
+
+// ResolveStation turns a station ID or a free-text query into a Station
+func ResolveStation(query string) (Station, error) {
+	return vbb.Default.ResolveStation(context.Background(), query)
+}
+
+// Journeys plans journeys between two stops by ID, with every product
+// allowed, sorted by departure
+func Journeys(originID, destID string) ([]Journey, error) {
+	return vbb.Default.Journeys(context.Background(), originID, destID, vbb.JourneyOptions{})
+}
+
+// FormatTime renders t as the TUI does, in the provider's timezone
+func FormatTime(t time.Time) string {
+	return model.FormatTime(t)
+}
@@ -0,0 +1,60 @@
+package commute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-commute/internal/vbb"
+)
+
+func TestCommute(t *testing.T) {
+	leave := time.Date(2026, 10, 16, 8, 2, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/locations":
+			fmt.Fprint(w, `[{"type":"location","name":"Alexanderstr. 1"},
+				{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"}]`)
+		case "/stops/900100003":
+			fmt.Fprint(w, `{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"}`)
+		case "/journeys":
+			fmt.Fprintf(w, `{"journeys":[{"legs":[{
+				"origin":{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"},
+				"destination":{"type":"stop","id":"900023201","name":"S+U Zoologischer Garten (Berlin)"},
+				"departure":%q,"plannedDeparture":%q,"arrival":%q,"plannedArrival":%q,
+				"tripId":"1","line":{"name":"U2","product":"subway"}}]}]}`,
+				leave.Format(time.RFC3339), leave.Format(time.RFC3339),
+				leave.Add(19*time.Minute).Format(time.RFC3339), leave.Add(19*time.Minute).Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	base, client := vbb.Default.BaseURL, vbb.Default.HTTP
+	vbb.Default.BaseURL, vbb.Default.HTTP = srv.URL, srv.Client()
+	defer func() { vbb.Default.BaseURL, vbb.Default.HTTP = base, client }()
+
+	stations, err := SearchStations(context.Background(), "alex")
+	if err != nil || len(stations) != 1 || stations[0].ID != "900100003" {
+		t.Errorf("SearchStations = %+v, %v, want only the stop", stations, err)
+	}
+
+	station, err := ResolveStation("900100003")
+	if err != nil || station.Name != "S+U Alexanderplatz (Berlin)" {
+		t.Errorf("ResolveStation = %+v, %v", station, err)
+	}
+
+	journeys, err := Journeys("900100003", "900023201")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journeys) != 1 || len(journeys[0].Legs) != 1 || journeys[0].Legs[0].Line != "U2" {
+		t.Fatalf("Journeys = %+v, want the one U2", journeys)
+	}
+	if got := FormatTime(journeys[0].LeaveAt); got != "10:02" {
+		t.Errorf("leaves at %s, want 10:02 Berlin time", got)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+)
+
+// findFavorite looks a favorite up by 1-based index or name fragment
+func findFavorite(routes []model.FavoriteRoute, query string) (model.FavoriteRoute, error) {
+	if n, err := strconv.Atoi(query); err == nil {
+		if n < 1 || n > len(routes) {
+			return model.FavoriteRoute{}, fmt.Errorf("no favorite #%d", n)
+		}
+		return routes[n-1], nil
+	}
+	q := strings.ToLower(query)
+	for _, r := range routes {
+		if strings.Contains(strings.ToLower(model.RouteName(r.Origin, r.Dest)), q) {
+			return r, nil
+		}
+	}
+	return model.FavoriteRoute{}, fmt.Errorf("no favorite matches %q", query)
+}
+
+// runCompare prints a head-to-head report of two favorites from history
+func runCompare(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	days := fs.Int("days", 30, "how many days of history to compare")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: berrrr compare [--days 30] <favorite> <favorite>")
+		return 2
+	}
+
+	cfg := config.Load()
+	var routes [2]model.FavoriteRoute
+	for i := range routes {
+		r, err := findFavorite(cfg.Routes, fs.Arg(i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		routes[i] = r
+	}
+
+	threshold := cfg.Notify.Threshold()
+	since := time.Now().AddDate(0, 0, -*days)
+	hist := history.Load()
+	var sums [2]history.RouteSummary
+	for i, r := range routes {
+		sums[i] = history.SummarizeRoute(hist.Journeys, model.RouteName(r.Origin, r.Dest), since, threshold)
+	}
+
+	pct := func(n, total int) string {
+		if total == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d%%", n*100/total)
+	}
+	mins := func(d time.Duration, n int) string {
+		if n == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f min", d.Minutes())
+	}
+
+	a, b := sums[0], sums[1]
+	fmt.Printf("Last %d days\n\n", *days)
+	fmt.Printf("%-22s %-28s %-28s\n", "", a.Route, b.Route)
+	fmt.Printf("%-22s %-28d %-28d\n", "Journeys observed", a.Journeys, b.Journeys)
+	fmt.Printf("%-22s %-28s %-28s\n", "Avg planned duration", mins(a.Planned, a.Journeys-a.Cancelled), mins(b.Planned, b.Journeys-b.Cancelled))
+	fmt.Printf("%-22s %-28s %-28s\n", "Avg realized duration", mins(a.Realized, a.Journeys-a.Cancelled), mins(b.Realized, b.Journeys-b.Cancelled))
+	fmt.Printf("%-22s %-28s %-28s\n", fmt.Sprintf("Delayed (≥%d min)", threshold), pct(a.Delayed, a.Journeys-a.Cancelled), pct(b.Delayed, b.Journeys-b.Cancelled))
+	fmt.Printf("%-22s %-28d %-28d\n", "Cancelled", a.Cancelled, b.Cancelled)
+
+	if a.Journeys-a.Cancelled > 0 && b.Journeys-b.Cancelled > 0 {
+		winner, diff := a.Route, b.Realized-a.Realized
+		if diff < 0 {
+			winner, diff = b.Route, -diff
+		}
+		fmt.Printf("\n%s is faster by %.1f min on average.\n", winner, diff.Minutes())
+	} else {
+		fmt.Println("\nNot enough history yet; keep berrrr or 'berrrr daemon' running on both routes.")
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestFindFavorite(t *testing.T) {
+	routes := []model.FavoriteRoute{
+		{Origin: model.Station{Name: "S Köpenick (Berlin)"}, Dest: model.Station{Name: "U Brunnenstr. (Berlin)"}},
+		{Origin: model.Station{Name: "S+U Warschauer Str. (Berlin)"}, Dest: model.Station{Name: "S+U Zoologischer Garten (Berlin)"}},
+	}
+	tests := []struct {
+		query string
+		want  string // the origin, empty for an error
+	}{
+		{"1", "S Köpenick (Berlin)"},
+		{"2", "S+U Warschauer Str. (Berlin)"},
+		{"3", ""},
+		{"0", ""},
+		{"zoo", "S+U Warschauer Str. (Berlin)"},
+		{"KÖPENICK", "S Köpenick (Berlin)"},
+		{"brunnenstr", "S Köpenick (Berlin)"},
+		{"Hauptbahnhof", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := findFavorite(routes, tt.query)
+			if (err == nil) != (tt.want != "") {
+				t.Fatalf("err = %v, want a favorite %v", err, tt.want != "")
+			}
+			if got.Origin.Name != tt.want {
+				t.Errorf("got %q, want %q", got.Origin.Name, tt.want)
+			}
+		})
+	}
+}
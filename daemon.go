@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/config"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+	"go-commute/internal/mqtt"
+	"go-commute/internal/vbb"
+)
+
+// runDaemon monitors the favorite routes headless, recording delay history
+// and dispatching alerts until interrupted
+func runDaemon(args []string) int {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := fs.Duration("interval", 2*time.Minute, "time between checks")
+	cassette := cassetteFlags(fs)
+	fs.Parse(args)
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "berrrr: ", log.LstdFlags)
+	tracker := alert.NewTracker()
+	hist := history.Load()
+
+	if err := alert.ValidateRules(config.Load().Notify.Rules); err != nil {
+		logger.Printf("ignoring invalid %v", err)
+	}
+
+	logger.Printf("daemon started, checking every %s", *interval)
+	for {
+		// Reload each round so config edits apply without a restart
+		cfg := config.Load()
+		routes := cfg.Routes
+		if len(routes) == 0 {
+			routes = []model.FavoriteRoute{{Origin: cfg.LastOrigin, Dest: cfg.LastDest}}
+		}
+
+		for _, res := range vbb.FetchRoutes(ctx, vbb.Default, routes) {
+			if res.Err != nil {
+				logger.Printf("%s: %v", model.RouteName(res.Route.Origin, res.Route.Dest), res.Err)
+				continue
+			}
+			monitorRoute(cfg, tracker, hist, res.Route, res.Journeys, logger)
+		}
+		for _, line := range cfg.WatchLines {
+			warnings, err := vbb.Default.LineWarnings(ctx, line)
+			if err != nil {
+				logger.Printf("%s: %v", line, err)
+				continue
+			}
+			alerts := tracker.CheckLine(line, warnings)
+			for _, a := range alerts {
+				logger.Printf("%s: %s", a.Title, a.Message)
+			}
+			alert.Dispatch(cfg.Notify, alerts)
+		}
+		if err := hist.Save(); err != nil {
+			logger.Printf("saving history: %v", err)
+		}
+
+		wait := *interval
+		if quiet := cfg.Notify.QuietHours; quiet.Active(time.Now()) && quiet.Interval() > wait {
+			wait = quiet.Interval()
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Printf("daemon stopped")
+			return 0
+		case <-time.After(wait):
+		}
+	}
+}
+
+// monitorRoute runs the per-refresh side effects for a fetched route:
+// history, alerts and MQTT publishing
+func monitorRoute(cfg config.Config, tracker *alert.Tracker, hist *history.History, r model.FavoriteRoute, journeys []model.Journey, logger *log.Logger) {
+	name := model.RouteName(r.Origin, r.Dest)
+	hist.Record(name, journeys)
+	journeys, _ = cfg.Avoid.Filter(journeys, r.Origin, r.Dest)
+
+	alerts := tracker.Check(name, journeys, cfg.Notify)
+	for _, a := range alerts {
+		logger.Printf("%s: %s", a.Title, a.Message)
+	}
+	alert.Dispatch(cfg.Notify, alerts)
+
+	if cfg.MQTT != nil {
+		if err := mqtt.Publish(*cfg.MQTT, mqtt.BuildMessages(*cfg.MQTT, r.Origin, r.Dest, journeys)); err != nil {
+			logger.Printf("mqtt: %v", err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// soonest is the journey arriving first at one candidate destination
+type soonest struct {
+	dest    model.Station
+	journey *model.Journey
+	err     error
+}
+
+// runDestinations fetches the journeys from one origin to several places
+// that would all do, like three supermarkets, and lists them by which one
+// is reached first
+func runDestinations(args []string) int {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("destinations", flag.ExitOnError)
+	from := fs.String("from", "", "origin station ID, name, alias or LAT,LON (default: last origin)")
+	cassette := cassetteFlags(fs)
+	fs.Parse(args)
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: berrrr destinations [--from STATION] <destination> <destination>...")
+		fmt.Fprintln(os.Stderr, "A destination is a station ID, name or alias, or #N for favorite N's destination.")
+		return 2
+	}
+
+	ctx := context.Background()
+	origin := cfg.LastOrigin
+	if *from != "" {
+		var err error
+		if origin, err = resolveStation(ctx, cfg, *from); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --from: %v\n", err)
+			return 2
+		}
+	}
+
+	routes := make([]model.FavoriteRoute, fs.NArg())
+	for i, query := range fs.Args() {
+		dest, err := resolveDestination(ctx, cfg, query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", query, err)
+			return 2
+		}
+		routes[i] = model.FavoriteRoute{Origin: origin, Dest: dest}
+	}
+
+	now := time.Now()
+	results := make([]soonest, len(routes))
+	for i, res := range vbb.FetchRoutes(ctx, vbb.Default, routes) {
+		results[i] = soonest{dest: res.Route.Dest, err: res.Err}
+		journeys, _ := cfg.Avoid.Filter(res.Journeys, origin, res.Route.Dest)
+		for k, j := range journeys {
+			if j.Cancelled() || j.LeaveAt.Before(now) {
+				continue
+			}
+			if results[i].journey == nil || j.ArriveAt.Before(results[i].journey.ArriveAt) {
+				results[i].journey = &journeys[k]
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].journey, results[j].journey
+		if (a == nil) != (b == nil) {
+			return b == nil
+		}
+		return a != nil && a.ArriveAt.Before(b.ArriveAt)
+	})
+
+	fmt.Printf("From %s at %s\n\n", model.CleanStation(origin.Name), model.FormatTime(now))
+	fmt.Printf("   %-28s %-6s %-6s %-9s %s\n", "Destination", "Leave", "Arrive", "Duration", "Changes")
+	for i, r := range results {
+		name := model.CleanStation(r.dest.Name)
+		switch {
+		case r.err != nil:
+			fmt.Printf("%2d %-28s %v\n", i+1, name, r.err)
+		case r.journey == nil:
+			fmt.Printf("%2d %-28s no connection\n", i+1, name)
+		default:
+			j := r.journey
+			fmt.Printf("%2d %-28s %-6s %-6s %-9s %d\n", i+1, name,
+				model.FormatTime(j.LeaveAt), model.FormatTime(j.ArriveAt),
+				fmt.Sprintf("%d min", int(j.Duration.Minutes())), len(j.Legs)-1)
+		}
+	}
+	return 0
+}
+
+// resolveDestination is resolveStation that also takes "#2" for the
+// second favorite's destination
+func resolveDestination(ctx context.Context, cfg config.Config, query string) (model.Station, error) {
+	if n, ok := strings.CutPrefix(query, "#"); ok {
+		i, err := strconv.Atoi(n)
+		if err != nil || i < 1 || i > len(cfg.Routes) {
+			return model.Station{}, fmt.Errorf("no favorite %s", query)
+		}
+		return cfg.Routes[i-1].Dest, nil
+	}
+	station, err := resolveStation(ctx, cfg, query)
+	if err != nil {
+		return model.Station{}, err
+	}
+	if station.IsAddress() {
+		return model.Station{}, fmt.Errorf("journeys can only start at an address, not end there")
+	}
+	return station, nil
+}
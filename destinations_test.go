@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestResolveDestination(t *testing.T) {
+	cfg := config.Config{
+		Routes: []model.FavoriteRoute{
+			{Dest: model.Station{ID: "900100041", Name: "Brunnenstr./Invalidenstr. (Berlin)"}},
+			{Dest: model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}},
+		},
+		Aliases: map[string]string{"park": "52.5145,13.3501"},
+	}
+
+	tests := []struct {
+		query string
+		want  string // station ID, empty for an error
+	}{
+		{"#1", "900100041"},
+		{"#2", "900023201"},
+		{"#3", ""},
+		{"#0", ""},
+		{"#first", ""},
+		{"52.5219,13.4132", ""},
+		{"park", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := resolveDestination(context.Background(), cfg, tt.query)
+			if (err == nil) != (tt.want != "") {
+				t.Fatalf("err = %v, want ok %v", err, tt.want != "")
+			}
+			if got.ID != tt.want {
+				t.Errorf("got %s, want %s", got.ID, tt.want)
+			}
+		})
+	}
+}
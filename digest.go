@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// runDigest prints a summary of the next hour on the favorite routes,
+// current disruptions and yesterday's delays, as text or markdown
+func runDigest(args []string) int {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	markdown := fs.Bool("markdown", false, "format the digest as markdown")
+	window := fs.Duration("window", time.Hour, "how far ahead to list departures")
+	cassette := cassetteFlags(fs)
+	fs.Parse(args)
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	cfg := config.Load()
+	routes := cfg.Routes
+	if len(routes) == 0 {
+		routes = []model.FavoriteRoute{{Origin: cfg.LastOrigin, Dest: cfg.LastDest}}
+	}
+
+	var sb strings.Builder
+	heading := func(s string) {
+		if *markdown {
+			sb.WriteString("## " + s + "\n\n")
+		} else {
+			sb.WriteString(s + "\n" + strings.Repeat("=", len([]rune(s))) + "\n\n")
+		}
+	}
+	bullet := func(format string, args ...interface{}) {
+		prefix := "  "
+		if *markdown {
+			prefix = "- "
+		}
+		sb.WriteString(prefix + fmt.Sprintf(format, args...) + "\n")
+	}
+
+	now := time.Now().In(model.DisplayZone)
+	stamp := now.Format("Mon 02.01.2006 15:04")
+	if zone := model.ZoneLabel(now); zone != "" {
+		stamp += " " + zone
+	}
+	if *markdown {
+		sb.WriteString(fmt.Sprintf("# Commute digest, %s\n\n", stamp))
+	} else {
+		sb.WriteString(fmt.Sprintf("Commute digest, %s\n\n", stamp))
+	}
+
+	failed := 0
+	disruptions := make(map[string][]string)
+	var lines []string
+	for _, res := range vbb.FetchRoutes(context.Background(), vbb.Default, routes) {
+		r, journeys, err := res.Route, res.Journeys, res.Err
+		heading(model.RouteName(r.Origin, r.Dest))
+
+		if err != nil {
+			bullet("could not fetch journeys: %v", err)
+			sb.WriteString("\n")
+			failed++
+			continue
+		}
+
+		listed := 0
+		for _, j := range journeys {
+			if j.LeaveAt.Before(now) || j.LeaveAt.Sub(now) > *window {
+				continue
+			}
+			var chain []string
+			delay := 0
+			for _, leg := range j.Legs {
+				chain = append(chain, leg.Line)
+				if leg.DepDelay > delay {
+					delay = leg.DepDelay
+				}
+			}
+			line := fmt.Sprintf("%s → %s  %s  (%d min)", model.FormatTime(j.LeaveAt), model.FormatTime(j.ArriveAt),
+				strings.Join(chain, " › "), int(j.Duration.Minutes()))
+			if delay >= 60 {
+				line += fmt.Sprintf("  +%d min", delay/60)
+			}
+			bullet("%s", line)
+			listed++
+		}
+		if listed == 0 {
+			bullet("no departures in the next %d min", int(window.Minutes()))
+		}
+		sb.WriteString("\n")
+
+		for _, j := range journeys {
+			for _, leg := range j.Legs {
+				if !slices.Contains(lines, leg.Line) {
+					lines = append(lines, leg.Line)
+				}
+				for _, w := range leg.ServiceStatus {
+					if !slices.Contains(disruptions[leg.Line], w) {
+						disruptions[leg.Line] = append(disruptions[leg.Line], w)
+					}
+				}
+			}
+		}
+	}
+
+	for _, line := range cfg.WatchLines {
+		if warnings, err := vbb.Default.LineWarnings(context.Background(), line); err == nil {
+			for _, w := range warnings {
+				if !slices.Contains(disruptions[line], w) {
+					disruptions[line] = append(disruptions[line], w)
+				}
+			}
+		}
+	}
+
+	heading("Disruptions")
+	if len(disruptions) == 0 {
+		bullet("none")
+	}
+	var disrupted []string
+	for line := range disruptions {
+		disrupted = append(disrupted, line)
+	}
+	sort.Strings(disrupted)
+	for _, line := range disrupted {
+		for _, w := range disruptions[line] {
+			bullet("%s: %s", line, w)
+		}
+	}
+	sb.WriteString("\n")
+
+	heading("Yesterday's delays")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	threshold := cfg.Notify.Threshold()
+	stats := history.ComputeLineStats(history.Load().SamplesBetween(today.AddDate(0, 0, -1), today), threshold)
+	shown := 0
+	for _, st := range stats {
+		if len(lines) > 0 && !slices.Contains(lines, st.Line) && !slices.Contains(cfg.WatchLines, st.Line) {
+			continue
+		}
+		bullet("%s: %d departures, avg %.1f min, worst %d min, %d late (≥%d min)",
+			st.Line, st.Count, st.Mean, st.Worst, st.Late, threshold)
+		shown++
+	}
+	if shown == 0 {
+		bullet("no data recorded")
+	}
+
+	fmt.Print(sb.String())
+	if failed == len(routes) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// fakeAPI points the default client at handler for the test
+func fakeAPI(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	base, client := vbb.Default.BaseURL, vbb.Default.HTTP
+	vbb.Default.BaseURL, vbb.Default.HTTP = srv.URL, srv.Client()
+	t.Cleanup(func() {
+		vbb.Default.BaseURL, vbb.Default.HTTP = base, client
+		srv.Close()
+	})
+}
+
+func TestRunDigest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := os.WriteFile(config.Path(), []byte(`{"watch_lines":["U2"],"routes":[
+		{"origin":{"id":"900120004","name":"S+U Warschauer Str. (Berlin)"},"dest":{"id":"900023201","name":"S+U Zoologischer Garten (Berlin)"}},
+		{"origin":{"id":"900100003","name":"S+U Alexanderplatz (Berlin)"},"dest":{"id":"900000001","name":"Nowhere"}}]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leave := time.Now().Add(10 * time.Minute).Truncate(time.Minute)
+	at := func(d time.Duration) string { return leave.Add(d).Format(time.RFC3339) }
+	fakeAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/journeys" && r.URL.Query().Get("to") == "900000001":
+			http.Error(w, "no route", http.StatusInternalServerError)
+		case r.URL.Path == "/journeys":
+			fmt.Fprintf(w, `{"journeys":[{"legs":[{
+				"origin":{"type":"stop","id":"900120004","name":"S+U Warschauer Str. (Berlin)"},
+				"destination":{"type":"stop","id":"900023201","name":"S+U Zoologischer Garten (Berlin)"},
+				"departure":%q,"plannedDeparture":%q,"departureDelay":180,
+				"arrival":%q,"plannedArrival":%q,"arrivalDelay":180,
+				"tripId":"1","line":{"name":"S5","product":"suburban"},
+				"remarks":[{"type":"warning","text":"Construction work at Ostkreuz"}]}]}]}`,
+				at(3*time.Minute), at(0), at(22*time.Minute), at(19*time.Minute))
+		case r.URL.Path == "/trips":
+			fmt.Fprint(w, `{"trips":[{"id":"2","line":{"name":"U2"},"remarks":[{"type":"warning","text":"Elevator out at Zoo"}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	wantRide := fmt.Sprintf("%s → %s  S5  (19 min)  +3 min", model.FormatTime(leave.Add(3*time.Minute)), model.FormatTime(leave.Add(22*time.Minute)))
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"text", nil, []string{
+			"Warschauer Str. → Zoologischer Garten\n=====================================\n\n  " + wantRide + "\n",
+			"  could not fetch journeys",
+			"Disruptions\n===========\n\n  S5: Construction work at Ostkreuz\n  U2: Elevator out at Zoo\n",
+			"Yesterday's delays\n==================\n\n  no data recorded\n",
+		}},
+		{"markdown", []string{"--markdown"}, []string{
+			"# Commute digest, ",
+			"## Warschauer Str. → Zoologischer Garten\n\n- " + wantRide + "\n",
+			"- S5: Construction work at Ostkreuz\n",
+		}},
+		{"nothing in the window", []string{"--window", "5m"}, []string{"  no departures in the next 5 min\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var code int
+			out := captureStdout(t, func() { code = runDigest(tt.args) })
+			if code != 0 {
+				t.Errorf("exit code %d with one route still answering", code)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("missing %q in\n%s", want, out)
+				}
+			}
+		})
+	}
+}
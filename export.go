@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/diary"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+)
+
+// runExport dumps the delay history or commute diary as CSV or JSON
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	data := fs.String("data", "history", "what to export: history or diary")
+	format := fs.String("format", "csv", "output format: csv or json")
+	since := fs.String("since", "", "only include entries from this date on (YYYY-MM-DD)")
+	until := fs.String("until", "", "only include entries before the end of this date (YYYY-MM-DD)")
+	output := fs.String("o", "", "write to a file instead of stdout")
+	fs.Parse(args)
+
+	from, to, err := parseDateRange(*since, *until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch *data {
+	case "history":
+		err = exportHistory(out, *format, history.Load().SamplesBetween(from, to))
+	case "diary":
+		var entries []diary.Entry
+		for _, e := range diary.Load().Entries {
+			if !e.PlannedDeparture.Before(from) && e.PlannedDeparture.Before(to) {
+				entries = append(entries, e)
+			}
+		}
+		err = exportDiary(out, *format, entries)
+	default:
+		err = fmt.Errorf("unknown data %q, use history or diary", *data)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseDateRange turns optional YYYY-MM-DD bounds into [from, to)
+func parseDateRange(since, until string) (time.Time, time.Time, error) {
+	from := time.Time{}
+	to := time.Now().AddDate(100, 0, 0)
+	if since != "" {
+		t, err := time.ParseInLocation("2006-01-02", since, model.DisplayZone)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --since: %w", err)
+		}
+		from = t
+	}
+	if until != "" {
+		t, err := time.ParseInLocation("2006-01-02", until, model.DisplayZone)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --until: %w", err)
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+func exportHistory(w io.Writer, format string, samples []history.Sample) error {
+	if format == "json" {
+		if samples == nil {
+			samples = []history.Sample{}
+		}
+		return writeJSON(w, samples)
+	}
+	if format != "csv" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"planned", "line", "product", "stop", "route", "delay_s", "trip_id", "seen"})
+	for _, s := range samples {
+		cw.Write([]string{
+			s.Planned.Format(time.RFC3339), s.Line, s.Product, s.Stop, s.Route,
+			strconv.Itoa(s.Delay), s.TripID, s.Seen.Format(time.RFC3339),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func exportDiary(w io.Writer, format string, entries []diary.Entry) error {
+	if format == "json" {
+		if entries == nil {
+			entries = []diary.Entry{}
+		}
+		return writeJSON(w, entries)
+	}
+	if format != "csv" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"route", "lines", "planned_departure", "planned_arrival",
+		"actual_departure", "actual_arrival", "planned_min", "actual_min"})
+	for _, e := range entries {
+		cw.Write([]string{
+			e.Route, strings.Join(e.Lines, " "),
+			e.PlannedDeparture.Format(time.RFC3339), e.PlannedArrival.Format(time.RFC3339),
+			e.ActualDeparture.Format(time.RFC3339), e.ActualArrival.Format(time.RFC3339),
+			strconv.Itoa(int(e.PlannedDuration().Minutes())), strconv.Itoa(int(e.ActualDuration().Minutes())),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
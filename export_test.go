@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/diary"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+)
+
+func TestParseDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, model.DisplayZone) }
+	tests := []struct {
+		since, until string
+		from, to     time.Time // to zero for open-ended
+		ok           bool
+	}{
+		{"", "", time.Time{}, time.Time{}, true},
+		{"2026-10-01", "", day(1), time.Time{}, true},
+		{"2026-10-01", "2026-10-15", day(1), day(16), true},
+		{"2026-10-15", "2026-10-15", day(15), day(16), true},
+		{"01.10.2026", "", time.Time{}, time.Time{}, false},
+		{"", "tomorrow", time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.since+"-"+tt.until, func(t *testing.T) {
+			from, to, err := parseDateRange(tt.since, tt.until)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if !tt.ok {
+				return
+			}
+			if !from.Equal(tt.from) {
+				t.Errorf("from %s, want %s", from, tt.from)
+			}
+			if tt.to.IsZero() && to.Before(time.Now().AddDate(50, 0, 0)) {
+				t.Errorf("open range ends %s", to)
+			} else if !tt.to.IsZero() && !to.Equal(tt.to) {
+				t.Errorf("to %s, want %s", to, tt.to)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	planned := time.Date(2026, 10, 16, 8, 2, 0, 0, time.UTC)
+	samples := []history.Sample{{Planned: planned, Line: "S5", Product: "suburban", Stop: "Warschauer Str.",
+		Route: "home → work", Delay: 120, TripID: "1|S5", Seen: planned.Add(time.Minute)}}
+	entries := []diary.Entry{{Route: "home → work", Lines: []string{"S5", "U2"},
+		PlannedDeparture: planned, PlannedArrival: planned.Add(29 * time.Minute),
+		ActualDeparture: planned.Add(2 * time.Minute), ActualArrival: planned.Add(33 * time.Minute)}}
+
+	tests := []struct {
+		name   string
+		export func(w *bytes.Buffer) error
+		want   string // the output for CSV, a field for JSON
+	}{
+		{"history csv", func(w *bytes.Buffer) error { return exportHistory(w, "csv", samples) },
+			"planned,line,product,stop,route,delay_s,trip_id,seen\n" +
+				"2026-10-16T08:02:00Z,S5,suburban,Warschauer Str.,home → work,120,1|S5,2026-10-16T08:03:00Z\n"},
+		{"diary csv", func(w *bytes.Buffer) error { return exportDiary(w, "csv", entries) },
+			"route,lines,planned_departure,planned_arrival,actual_departure,actual_arrival,planned_min,actual_min\n" +
+				"home → work,S5 U2,2026-10-16T08:02:00Z,2026-10-16T08:31:00Z,2026-10-16T08:04:00Z,2026-10-16T08:35:00Z,29,33\n"},
+		{"history json", func(w *bytes.Buffer) error { return exportHistory(w, "json", samples) }, `"delay": 120`},
+		{"diary json", func(w *bytes.Buffer) error { return exportDiary(w, "json", entries) }, `"route": "home → work"`},
+		{"nothing as json", func(w *bytes.Buffer) error { return exportHistory(w, "json", nil) }, "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.export(&buf); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			if strings.HasSuffix(tt.name, "csv") && got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			if strings.HasSuffix(tt.name, "json") && (!json.Valid(buf.Bytes()) || !strings.Contains(got, tt.want)) {
+				t.Errorf("missing %s in\n%s", tt.want, got)
+			}
+		})
+	}
+
+	if err := exportDiary(&bytes.Buffer{}, "xml", entries); err == nil {
+		t.Error("no error for an unknown format")
+	}
+}
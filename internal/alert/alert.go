@@ -0,0 +1,263 @@
+// Package alert detects delays, disruptions and rule matches and delivers
+// them to desktop, webhook, Telegram and Pushover channels.
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// Alert is a notable event worth telling the user about
+type Alert struct {
+	Kind    string    `json:"kind"` // "delay", "warning", "platform", "leave", "risk", "cancelled", "rule", "better"
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Line    string    `json:"line,omitempty"`
+	Route   string    `json:"route,omitempty"`
+	TripID  string    `json:"trip_id,omitempty"`
+	Time    time.Time `json:"time"`
+
+	// Channels restricts delivery, e.g. ["desktop"]; empty means all configured
+	Channels []string `json:"-"`
+}
+
+// wants reports whether the alert should go out on a channel that is
+// enabled by default according to enabled
+func (a Alert) wants(channel string, enabled bool) bool {
+	if len(a.Channels) == 0 {
+		return enabled
+	}
+	return slices.Contains(a.Channels, channel)
+}
+
+// Tracker remembers what has already been reported so each
+// disruption or delay only fires once
+type Tracker struct {
+	mu        sync.Mutex
+	warnings  map[string]time.Time
+	delays    map[string]time.Time
+	platforms map[string]time.Time // by trip, when last seen
+	rules     map[string]time.Time
+
+	lastPlatform map[string]string // by trip, its departure platform last time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		warnings:  make(map[string]time.Time),
+		delays:    make(map[string]time.Time),
+		platforms: make(map[string]time.Time),
+		rules:     make(map[string]time.Time),
+
+		lastPlatform: make(map[string]string),
+	}
+}
+
+// Check compares a route's journeys against what was seen before and
+// returns alerts for new warning remarks, delays above the threshold,
+// departure platform changes and matching user rules
+func (t *Tracker) Check(route string, journeys []model.Journey, cfg config.Notify) []Alert {
+	alerts := t.checkRules(route, journeys, cfg.Rules)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	threshold := cfg.Threshold()
+
+	for _, j := range journeys {
+		for _, leg := range j.Legs {
+			for _, status := range leg.ServiceStatus {
+				key := leg.Line + "|" + status
+				if _, seen := t.warnings[key]; seen {
+					continue
+				}
+				t.warnings[key] = now
+				alerts = append(alerts, Alert{
+					Kind:    "warning",
+					Title:   fmt.Sprintf("%s disruption", leg.Line),
+					Message: status,
+					Line:    leg.Line,
+					Route:   route,
+					Time:    now,
+				})
+			}
+
+			if leg.TripID == "" {
+				continue
+			}
+
+			// Every change counts, from the planned platform or the last one seen
+			if leg.DepPlatform != "" {
+				was, seen := t.lastPlatform[leg.TripID]
+				if !seen {
+					was = leg.PlannedDepPlatform
+				}
+				t.lastPlatform[leg.TripID] = leg.DepPlatform
+				t.platforms[leg.TripID] = now
+				if was != "" && was != leg.DepPlatform {
+					alerts = append(alerts, Alert{
+						Kind:  "platform",
+						Title: fmt.Sprintf("%s platform changed", leg.Line),
+						Message: fmt.Sprintf("%s %s from %s: platform %s → %s",
+							leg.Line, model.FormatTime(leg.Departure), model.CleanStation(leg.From),
+							was, leg.DepPlatform),
+						Line:   leg.Line,
+						Route:  route,
+						TripID: leg.TripID,
+						Time:   now,
+					})
+				}
+			}
+
+			if leg.DepDelay/60 < threshold {
+				continue
+			}
+			if _, seen := t.delays[leg.TripID]; seen {
+				continue
+			}
+			t.delays[leg.TripID] = now
+			alerts = append(alerts, Alert{
+				Kind:  "delay",
+				Title: fmt.Sprintf("%s delayed by %d min", leg.Line, leg.DepDelay/60),
+				Message: fmt.Sprintf("%s %s from %s now departs %s",
+					leg.Line, model.FormatTime(leg.Departure.Add(-time.Duration(leg.DepDelay)*time.Second)),
+					model.CleanStation(leg.From), model.FormatTime(leg.Departure)),
+				Line:  leg.Line,
+				Route: route,
+				Time:  now,
+			})
+		}
+	}
+
+	// Forget entries after a day so recurring disruptions are reported again
+	for _, seen := range []map[string]time.Time{t.warnings, t.delays, t.platforms, t.rules} {
+		for k, at := range seen {
+			if now.Sub(at) > 24*time.Hour {
+				delete(seen, k)
+			}
+		}
+	}
+	for trip := range t.lastPlatform {
+		if _, ok := t.platforms[trip]; !ok {
+			delete(t.lastPlatform, trip)
+		}
+	}
+
+	return alerts
+}
+
+// CheckLine returns alerts for warnings of a watched line not reported yet.
+// It shares bookkeeping with route checks so a warning fires only once.
+func (t *Tracker) CheckLine(line string, warnings []string) []Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var alerts []Alert
+	for _, w := range warnings {
+		key := line + "|" + w
+		if _, seen := t.warnings[key]; seen {
+			continue
+		}
+		t.warnings[key] = now
+		alerts = append(alerts, Alert{
+			Kind:    "warning",
+			Title:   fmt.Sprintf("%s disruption", line),
+			Message: w,
+			Line:    line,
+			Time:    now,
+		})
+	}
+	return alerts
+}
+
+// Dispatch sends alerts to every configured channel, unless it's quiet
+// hours. Alerts raised then are dropped: by the morning they're stale.
+func Dispatch(cfg config.Notify, alerts []Alert) {
+	if cfg.QuietHours.Active(time.Now()) {
+		return
+	}
+	for _, alert := range alerts {
+		if alert.wants("desktop", cfg.Desktop) {
+			if err := SendDesktop(alert.Title, alert.Message); err != nil {
+				slog.Warn("desktop notification failed", "kind", alert.Kind, "err", err)
+			}
+		}
+		if alert.wants("webhook", true) {
+			for _, hook := range cfg.Webhooks {
+				if err := sendWebhook(hook, alert); err != nil {
+					slog.Warn("webhook failed", "kind", alert.Kind, "err", err)
+				}
+			}
+		}
+		if cfg.Telegram != nil && alert.wants("telegram", true) {
+			if err := sendTelegram(*cfg.Telegram, alert); err != nil {
+				slog.Warn("telegram failed", "kind", alert.Kind, "err", err)
+			}
+		}
+		if cfg.Pushover != nil && alert.wants("pushover", true) {
+			if err := sendPushover(*cfg.Pushover, alert); err != nil {
+				slog.Warn("pushover failed", "kind", alert.Kind, "err", err)
+			}
+		}
+	}
+}
+
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+func sendTelegram(cfg config.Telegram, alert Alert) error {
+	return postJSON(fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", cfg.Token), map[string]string{
+		"chat_id": cfg.ChatID,
+		"text":    fmt.Sprintf("%s\n%s", alert.Title, alert.Message),
+	})
+}
+
+func sendPushover(cfg config.Pushover, alert Alert) error {
+	return postJSON("https://api.pushover.net/1/messages.json", map[string]interface{}{
+		"token":    cfg.Token,
+		"user":     cfg.User,
+		"title":    alert.Title,
+		"message":  alert.Message,
+		"priority": cfg.Priority,
+	})
+}
+
+func sendWebhook(hook config.Webhook, alert Alert) error {
+	var payload interface{}
+	text := fmt.Sprintf("%s\n%s", alert.Title, alert.Message)
+	switch hook.Format {
+	case "slack":
+		payload = map[string]string{"text": fmt.Sprintf("*%s*\n%s", alert.Title, alert.Message)}
+	case "discord":
+		payload = map[string]string{"content": text}
+	default:
+		payload = alert
+	}
+	return postJSON(hook.URL, payload)
+}
@@ -0,0 +1,240 @@
+package alert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestCheck(t *testing.T) {
+	dep := time.Now().Add(10 * time.Minute)
+	s5 := func(trip string, delay int, status ...string) model.Journey {
+		return model.Journey{Legs: []model.Leg{{
+			Line: "S5", TripID: trip, From: "S+U Warschauer Str. (Berlin)",
+			Departure: dep, DepDelay: delay, ServiceStatus: status,
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		refreshes [][]model.Journey
+		want      []string // the titles each refresh alerts with, joined
+	}{
+		{"on time", 0, [][]model.Journey{{s5("1|S5", 0)}}, []string{""}},
+		{"delay under the default threshold", 0, [][]model.Journey{{s5("1|S5", 4*60)}}, []string{""}},
+		{"delay once", 0, [][]model.Journey{{s5("1|S5", 5*60)}, {s5("1|S5", 7*60)}},
+			[]string{"S5 delayed by 5 min", ""}},
+		{"own threshold", 2, [][]model.Journey{{s5("1|S5", 3*60), s5("2|S5", 60)}},
+			[]string{"S5 delayed by 3 min"}},
+		{"delays without a trip", 0, [][]model.Journey{{s5("", 10*60)}}, []string{""}},
+		{"disruption once across trips", 0, [][]model.Journey{
+			{s5("1|S5", 0, "Construction work"), s5("2|S5", 0, "Construction work")},
+			{s5("3|S5", 0, "Construction work", "Signal failure")},
+		}, []string{"S5 disruption", "S5 disruption"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker()
+			for i, journeys := range tt.refreshes {
+				var titles []string
+				for _, a := range tr.Check("home", journeys, config.Notify{DelayThreshold: tt.threshold}) {
+					if a.Route != "home" {
+						t.Errorf("alert for route %q", a.Route)
+					}
+					titles = append(titles, a.Title)
+				}
+				if got := strings.Join(titles, ", "); got != tt.want[i] {
+					t.Errorf("refresh %d alerts %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckLine(t *testing.T) {
+	tr := NewTracker()
+	if got := tr.CheckLine("S5", []string{"Construction work", "Signal failure"}); len(got) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(got))
+	}
+	if got := tr.CheckLine("S5", []string{"Construction work", "Bus replacement"}); len(got) != 1 || got[0].Message != "Bus replacement" {
+		t.Errorf("got %v, want only the new warning", got)
+	}
+	if got := tr.CheckLine("U2", []string{"Construction work"}); len(got) != 1 {
+		t.Error("a warning seen on the S5 doesn't alert for the U2")
+	}
+
+	// Watching a line and riding it share what's been said
+	leg := model.Leg{Line: "S5", ServiceStatus: []string{"Signal failure"}}
+	if got := tr.Check("home", []model.Journey{{Legs: []model.Leg{leg}}}, config.Notify{}); len(got) != 0 {
+		t.Errorf("the route alerts %q again", got[0].Message)
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	alert := Alert{Kind: "delay", Title: "S5 delayed by 5 min", Message: "S5 08:02 from Warschauer Str. now departs 08:07", Line: "S5"}
+	tests := []struct {
+		format string
+		want   map[string]string
+	}{
+		{"", map[string]string{"kind": "delay", "title": alert.Title, "message": alert.Message, "line": "S5"}},
+		{"slack", map[string]string{"text": "*" + alert.Title + "*\n" + alert.Message}},
+		{"discord", map[string]string{"content": alert.Title + "\n" + alert.Message}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("content type %q", ct)
+				}
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer srv.Close()
+
+			if err := sendWebhook(config.Webhook{URL: srv.URL, Format: tt.format}, alert); err != nil {
+				t.Fatal(err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("%v in %s", err, body)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer srv.Close()
+	if err := sendWebhook(config.Webhook{URL: srv.URL}, alert); err == nil {
+		t.Error("no error for a 410")
+	}
+}
+
+func TestWants(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels []string
+		channel  string
+		enabled  bool
+		want     bool
+	}{
+		{"everywhere enabled", nil, "telegram", true, true},
+		{"everywhere but off", nil, "desktop", false, false},
+		{"restricted to it", []string{"desktop"}, "desktop", false, true},
+		{"restricted elsewhere", []string{"desktop"}, "telegram", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Alert{Channels: tt.channels}).wants(tt.channel, tt.enabled); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// roundTripFunc stands in for the network
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestSendTelegramPushover(t *testing.T) {
+	var url string
+	var body map[string]interface{}
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		url = r.URL.String()
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: r}, nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	alert := Alert{Title: "S5 delayed by 5 min", Message: "S5 08:02 from Warschauer Str. now departs 08:07"}
+	tests := []struct {
+		name string
+		send func() error
+		url  string
+		want map[string]interface{}
+	}{
+		{"telegram", func() error { return sendTelegram(config.Telegram{Token: "123:abc", ChatID: "42"}, alert) },
+			"https://api.telegram.org/bot123:abc/sendMessage",
+			map[string]interface{}{"chat_id": "42", "text": alert.Title + "\n" + alert.Message}},
+		{"pushover", func() error { return sendPushover(config.Pushover{Token: "app", User: "me", Priority: 1}, alert) },
+			"https://api.pushover.net/1/messages.json",
+			map[string]interface{}{"token": "app", "user": "me", "title": alert.Title, "message": alert.Message, "priority": 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.send(); err != nil {
+				t.Fatal(err)
+			}
+			if url != tt.url {
+				t.Errorf("posted to %s, want %s", url, tt.url)
+			}
+			if !reflect.DeepEqual(body, tt.want) {
+				t.Errorf("posted %v, want %v", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPlatform(t *testing.T) {
+	type seen struct{ planned, now string }
+	tests := []struct {
+		name  string
+		steps []seen
+		want  []string // the change each refresh alerts about, empty for none
+	}{
+		{"as planned", []seen{{"1", "1"}, {"1", "1"}}, []string{"", ""}},
+		{"changed once", []seen{{"1", "2"}, {"1", "2"}}, []string{"1 → 2", ""}},
+		{"changed twice", []seen{{"1", "2"}, {"1", "3"}}, []string{"1 → 2", "2 → 3"}},
+		{"changed back", []seen{{"1", "2"}, {"1", "1"}}, []string{"1 → 2", "2 → 1"}},
+		{"changed after the first look", []seen{{"1", "1"}, {"1", "4"}}, []string{"", "1 → 4"}},
+		{"nothing planned", []seen{{"", "2"}, {"", "3"}}, []string{"", "2 → 3"}},
+		{"platform goes missing", []seen{{"1", "2"}, {"1", ""}, {"1", "2"}}, []string{"1 → 2", "", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker()
+			dep := time.Now().Add(10 * time.Minute)
+			for i, s := range tt.steps {
+				leg := model.Leg{
+					Line: "S5", TripID: "1|S5", From: "S+U Warschauer Str. (Berlin)", Departure: dep,
+					PlannedDepPlatform: s.planned, DepPlatform: s.now,
+				}
+				var got []Alert
+				for _, a := range tr.Check("home", []model.Journey{{Legs: []model.Leg{leg}}}, config.Notify{}) {
+					if a.Kind == "platform" {
+						got = append(got, a)
+					}
+				}
+				switch {
+				case tt.want[i] == "" && len(got) > 0:
+					t.Errorf("refresh %d alerts %q", i, got[0].Message)
+				case tt.want[i] != "" && len(got) != 1:
+					t.Errorf("refresh %d has %d platform alerts, want one", i, len(got))
+				case tt.want[i] != "" && !strings.HasSuffix(got[0].Message, "platform "+tt.want[i]):
+					t.Errorf("refresh %d alerts %q, want %s", i, got[0].Message, tt.want[i])
+				case tt.want[i] != "" && got[0].TripID != "1|S5":
+					t.Errorf("refresh %d alert is for trip %q", i, got[0].TripID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package alert
+
+import "testing"
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in          string
+		apple, posh string
+	}{
+		{"S5 delayed by 5 min", `"S5 delayed by 5 min"`, `'S5 delayed by 5 min'`},
+		{`platform "2"`, `"platform \"2\""`, `'platform "2"'`},
+		{`C:\berrrr`, `"C:\\berrrr"`, `'C:\berrrr'`},
+		{"Zoo's S5", `"Zoo's S5"`, `'Zoo''s S5'`},
+		{"", `""`, `''`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := appleScriptQuote(tt.in); got != tt.apple {
+				t.Errorf("appleScriptQuote = %s, want %s", got, tt.apple)
+			}
+			if got := powerShellQuote(tt.in); got != tt.posh {
+				t.Errorf("powerShellQuote = %s, want %s", got, tt.posh)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// channels are the names a rule can send its alerts to
+var channels = []string{"desktop", "webhook", "telegram", "pushover"}
+
+// ValidateRules reports the first rule that fails to parse or names a
+// channel that doesn't exist
+func ValidateRules(rules []config.AlertRule) error {
+	for i, r := range rules {
+		if _, err := parseRule(r.When); err != nil {
+			return fmt.Errorf("rule %d (%s): %w", i+1, r.Name, err)
+		}
+		for _, c := range r.Notify {
+			if !slices.Contains(channels, c) {
+				return fmt.Errorf("rule %d (%s): unknown channel %q (available: %v)", i+1, r.Name, c, channels)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRules evaluates the rules against every leg, firing each rule at
+// most once per trip
+func (t *Tracker) checkRules(route string, journeys []model.Journey, rules []config.AlertRule) []Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var alerts []Alert
+	for i, rule := range rules {
+		expr, err := parseRule(rule.When)
+		if err != nil {
+			continue
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("Rule %d", i+1)
+		}
+
+		for _, j := range journeys {
+			for _, leg := range j.Legs {
+				if !truthy(expr.eval(ruleEnv(route, leg, now))) {
+					continue
+				}
+				key := fmt.Sprintf("%s|%s|%s", rule.When, leg.TripID, leg.From)
+				if _, seen := t.rules[key]; seen {
+					continue
+				}
+				t.rules[key] = now
+
+				msg := fmt.Sprintf("%s %s from %s", leg.Line, model.FormatTime(leg.Departure), model.CleanStation(leg.From))
+				if leg.DepDelay > 0 {
+					msg += fmt.Sprintf(" (+%d min)", leg.DepDelay/60)
+				}
+				if leg.Cancelled {
+					msg += " is cancelled"
+				}
+				alerts = append(alerts, Alert{
+					Kind:     "rule",
+					Title:    name,
+					Message:  msg,
+					Line:     leg.Line,
+					Route:    route,
+					Time:     now,
+					Channels: rule.Notify,
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+func ruleEnv(route string, leg model.Leg, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"line":            leg.Line,
+		"product":         leg.Product,
+		"from":            model.CleanStation(leg.From),
+		"to":              model.CleanStation(leg.To),
+		"route":           route,
+		"depDelay":        float64(leg.DepDelay),
+		"arrDelay":        float64(leg.ArrDelay),
+		"leavesIn":        float64(int(leg.Departure.Sub(now).Minutes())),
+		"cancelled":       leg.Cancelled,
+		"occupancy":       leg.Occupancy,
+		"platform":        leg.DepPlatform,
+		"plannedPlatform": leg.PlannedDepPlatform,
+		"platformChanged": leg.PlannedDepPlatform != "" && leg.DepPlatform != leg.PlannedDepPlatform,
+		"warning":         len(leg.ServiceStatus) > 0,
+		"warnings":        strings.Join(leg.ServiceStatus, "\n"),
+	}
+}
+
+// Rule expressions
+//
+//	expr    = and { ("or" | "||") and }
+//	and     = not { ("and" | "&&") not }
+//	not     = ("not" | "!") not | compare
+//	compare = primary [ ("==" | "!=" | "<" | "<=" | ">" | ">=" | "contains") primary ]
+//	primary = number | string | "true" | "false" | field | "(" expr ")"
+
+type ruleExpr interface {
+	eval(env map[string]interface{}) interface{}
+}
+
+type ruleLiteral struct{ value interface{} }
+
+type ruleField struct{ name string }
+
+type ruleNot struct{ x ruleExpr }
+
+type ruleBinary struct {
+	op   string
+	l, r ruleExpr
+}
+
+func (e ruleLiteral) eval(map[string]interface{}) interface{} { return e.value }
+
+func (e ruleField) eval(env map[string]interface{}) interface{} {
+	return env[e.name]
+}
+
+func (e ruleNot) eval(env map[string]interface{}) interface{} { return !truthy(e.x.eval(env)) }
+
+func (e ruleBinary) eval(env map[string]interface{}) interface{} {
+	switch e.op {
+	case "and":
+		return truthy(e.l.eval(env)) && truthy(e.r.eval(env))
+	case "or":
+		return truthy(e.l.eval(env)) || truthy(e.r.eval(env))
+	}
+
+	l, r := e.l.eval(env), e.r.eval(env)
+	if e.op == "contains" {
+		ls, _ := l.(string)
+		rs, _ := r.(string)
+		return strings.Contains(strings.ToLower(ls), strings.ToLower(rs))
+	}
+
+	if lf, ok := l.(float64); ok {
+		rf, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		switch e.op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+		return false
+	}
+
+	switch e.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	}
+	ls, lok := l.(string)
+	rs, rok := r.(string)
+	if !lok || !rok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+	return false
+}
+
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return false
+}
+
+type ruleParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseRule(src string) (ruleExpr, error) {
+	tokens, err := tokenizeRule(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	p := &ruleParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+func tokenizeRule(src string) ([]string, error) {
+	var tokens []string
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != c {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, `"`+string(runes[i+1:j]))
+			i = j + 1
+		case strings.ContainsRune("=!<>&|", c):
+			j := i + 1
+			if j < len(runes) && strings.ContainsRune("=&|", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			// A negative number; there's no subtraction
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *ruleParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *ruleParser) parseOr() (ruleExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" || p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = ruleBinary{op: "or", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" || p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = ruleBinary{op: "and", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (ruleExpr, error) {
+	if p.peek() == "not" || p.peek() == "!" {
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return ruleNot{x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *ruleParser) parseCompare() (ruleExpr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=", "contains":
+		p.pos++
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return ruleBinary{op: op, l: left, r: right}, nil
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parsePrimary() (ruleExpr, error) {
+	tok := p.peek()
+	if tok == "" {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	p.pos++
+
+	switch {
+	case tok == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return expr, nil
+	case strings.HasPrefix(tok, `"`):
+		return ruleLiteral{tok[1:]}, nil
+	case tok == "true" || tok == "false":
+		return ruleLiteral{tok == "true"}, nil
+	}
+
+	if n, err := strconv.ParseFloat(tok, 64); err == nil {
+		return ruleLiteral{n}, nil
+	}
+	if _, ok := ruleEnv("", model.Leg{}, time.Time{})[tok]; !ok {
+		return nil, fmt.Errorf("unknown field %q", tok)
+	}
+	return ruleField{tok}, nil
+}
@@ -0,0 +1,90 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestRuleEval(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	leg := model.Leg{
+		Line: "S5", Product: "suburban",
+		From: "S+U Warschauer Str. (Berlin)", To: "S+U Zoologischer Garten (Berlin)",
+		Departure: now.Add(12 * time.Minute), DepDelay: 300,
+		DepPlatform: "2", PlannedDepPlatform: "1",
+		ServiceStatus: []string{"Construction work at Ostkreuz"},
+	}
+
+	tests := []struct {
+		when string
+		want bool
+	}{
+		{`line == "S5"`, true},
+		{`line == 'U2'`, false},
+		{`depDelay >= 300`, true},
+		{`depDelay > 300`, false},
+		{`leavesIn <= 15 and not cancelled`, true},
+		{`cancelled || platformChanged`, true},
+		{`!(line == "S5" && depDelay > 60)`, false},
+		{`warnings contains "ostkreuz"`, true},
+		{`from contains "Zoo" or to contains "Warschauer"`, false},
+		{`arrDelay > -1`, true},
+		{`line >= "S"`, true},
+		{`line > 5`, false},
+		{`warning`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			expr, err := parseRule(tt.when)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := truthy(expr.eval(ruleEnv("home", leg, now))); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`depDelay >`,
+		`(line == "S5"`,
+		`line == "S5`,
+		`delay > 5`,
+		`dep-delay > 5`,
+		`line == "S5" "U2"`,
+		`line ~ "S5"`,
+	}
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := parseRule(src); err == nil {
+				t.Error("parsed, want an error")
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.AlertRule
+		ok    bool
+	}{
+		{"none", nil, true},
+		{"valid", []config.AlertRule{{Name: "late", When: "depDelay > 300", Notify: []string{"desktop"}}}, true},
+		{"bad condition", []config.AlertRule{{Name: "late", When: "depDelay >"}}, false},
+		{"unknown channel", []config.AlertRule{{Name: "late", When: "depDelay > 300", Notify: []string{"sms"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRules(tt.rules); (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Package calendar reads upcoming appointments from an iCalendar file, a
+// published feed or a CalDAV collection that answers GET with iCalendar.
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one appointment with a start time. Recurring events start at
+// their first occurrence; Next finds the one that's due.
+type Event struct {
+	Summary  string
+	Location string
+	Start    time.Time
+
+	uid          string
+	rule         *rule
+	exdates      []time.Time
+	recurrenceID time.Time // set on a single occurrence that was moved or edited
+}
+
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// Fetch reads the events of a calendar. The source is a file path or an
+// http(s)/webcal URL; user:password in the URL is sent as basic auth.
+// Floating times are read in zone.
+func Fetch(ctx context.Context, source string, zone *time.Location) ([]Event, error) {
+	if strings.HasPrefix(source, "webcal://") {
+		source = "https://" + strings.TrimPrefix(source, "webcal://")
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f, zone)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar returned %s", resp.Status)
+	}
+	return Parse(resp.Body, zone)
+}
+
+// Parse reads the VEVENTs of an iCalendar document. All-day events are
+// skipped. Daily and weekly recurrences are followed with their BYDAY,
+// INTERVAL, UNTIL and COUNT, leaving out EXDATEs and occurrences that were
+// moved; other recurrences only count with their first occurrence.
+func Parse(r io.Reader, zone *time.Location) ([]Event, error) {
+	var events []Event
+	var ev *Event
+	for _, line := range unfold(r) {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			ev = &Event{}
+		case name == "END" && value == "VEVENT":
+			if ev != nil && !ev.Start.IsZero() {
+				events = append(events, *ev)
+			}
+			ev = nil
+		case ev == nil:
+		case name == "SUMMARY":
+			ev.Summary = unescape(value)
+		case name == "LOCATION":
+			ev.Location = unescape(value)
+		case name == "DTSTART":
+			ev.Start = parseTime(value, params, zone)
+		case name == "UID":
+			ev.uid = value
+		case name == "RRULE":
+			ev.rule = parseRule(value, zone)
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if t := parseTime(v, params, zone); !t.IsZero() {
+					ev.exdates = append(ev.exdates, t)
+				}
+			}
+		case name == "RECURRENCE-ID":
+			ev.recurrenceID = parseTime(value, params, zone)
+		}
+	}
+
+	// A moved occurrence comes as an event of its own with the same UID,
+	// standing in for the one the rule would give
+	for _, moved := range events {
+		if moved.recurrenceID.IsZero() {
+			continue
+		}
+		for i := range events {
+			if events[i].uid == moved.uid && events[i].rule != nil {
+				events[i].exdates = append(events[i].exdates, moved.recurrenceID)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
+
+// Next is the first event after now that says where it is, recurring ones
+// at their next occurrence
+func Next(events []Event, now time.Time) (Event, bool) {
+	var next Event
+	found := false
+	for _, ev := range events {
+		if ev.Location == "" {
+			continue
+		}
+		start, ok := ev.after(now)
+		if ok && (!found || start.Before(next.Start)) {
+			next, found = ev, true
+			next.Start = start
+		}
+	}
+	return next, found
+}
+
+// after is the event's first occurrence after now
+func (ev Event) after(now time.Time) (time.Time, bool) {
+	if ev.rule == nil {
+		return ev.Start, ev.Start.After(now)
+	}
+	var next time.Time
+	found := false
+	ev.rule.each(ev.Start, func(t time.Time) bool {
+		if !t.After(now) || slices.ContainsFunc(ev.exdates, t.Equal) {
+			return true
+		}
+		next, found = t, true
+		return false
+	})
+	return next, found
+}
+
+// rule is the part of an RRULE that's followed
+type rule struct {
+	freq     string // DAILY or WEEKLY
+	interval int
+	byDay    []time.Weekday
+	until    time.Time
+	count    int
+}
+
+// maxOccurrences stops rules without an end after about ten years of days
+const maxOccurrences = 4000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// parseRule reads "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20261231T230000Z". Rules
+// other than daily and weekly give nil.
+func parseRule(value string, zone *time.Location) *rule {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				r.interval = n
+			}
+		case "COUNT":
+			r.count, _ = strconv.Atoi(v)
+		case "UNTIL":
+			r.until = parseTime(v, nil, zone)
+			if r.until.IsZero() {
+				// A date only ends the series with that whole day
+				if d, err := time.ParseInLocation("20060102", v, zone); err == nil {
+					r.until = d.AddDate(0, 0, 1).Add(-time.Second)
+				}
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(v, ",") {
+				// Weekly rules have no use for an ordinal like 1MO
+				day = strings.TrimLeft(strings.ToUpper(day), "+-0123456789")
+				if wd, ok := weekdays[day]; ok {
+					r.byDay = append(r.byDay, wd)
+				}
+			}
+		}
+	}
+	if r.freq != "DAILY" && r.freq != "WEEKLY" {
+		return nil
+	}
+	return r
+}
+
+// each calls f with the occurrences from start in order until f returns
+// false or the rule ends
+func (r *rule) each(start time.Time, f func(time.Time) bool) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if (!r.until.IsZero() && t.After(r.until)) || (r.count > 0 && n >= r.count) || n >= maxOccurrences {
+			return false
+		}
+		n++
+		return f(t)
+	}
+	at := func(days int) time.Time {
+		return time.Date(start.Year(), start.Month(), start.Day()+days, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	if r.freq == "DAILY" {
+		// BYDAY only filters the days, and may never match one
+		for i := 0; i < maxOccurrences; i++ {
+			t := at(i * r.interval)
+			if len(r.byDay) > 0 && !slices.Contains(r.byDay, t.Weekday()) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+		return
+	}
+
+	days := r.byDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	// Weeks start on Monday, and the days go in their order within one
+	offsets := make([]int, 0, len(days))
+	for _, d := range days {
+		offsets = append(offsets, (int(d)+6)%7)
+	}
+	sort.Ints(offsets)
+	monday := -((int(start.Weekday()) + 6) % 7)
+	for week := 0; ; week += r.interval {
+		for _, off := range offsets {
+			if !emit(at(monday + week*7 + off)) {
+				return
+			}
+		}
+	}
+}
+
+// unfold joins continuation lines, which start with a space or tab
+func unfold(r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitProperty splits "DTSTART;TZID=Europe/Berlin:20261016T090000"
+func splitProperty(line string) (name string, params map[string]string, value string) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, ""
+	}
+	parts := strings.Split(head, ";")
+	params = make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseTime(value string, params map[string]string, zone *time.Location) time.Time {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		return time.Time{} // all day, nothing to be on time for
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, _ := time.Parse("20060102T150405Z", value)
+		return t
+	}
+	loc := zone
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, _ := time.ParseInLocation("20060102T150405", value, loc)
+	return t
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, ", ", `\N`, ", ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
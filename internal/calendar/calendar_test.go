@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const feed = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:dentist
+SUMMARY:Dentist
+LOCATION:Praxis\, Kastanienallee 12
+DTSTART:20261020T090000
+END:VEVENT
+BEGIN:VEVENT
+UID:focus
+SUMMARY:Focus time
+DTSTART:20261016T100000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+SUMMARY:Holiday
+LOCATION:Usedom
+DTSTART;VALUE=DATE:20261019
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Stand
+ up
+LOCATION:Office
+DTSTART;TZID=Europe/Berlin:20261005T093000
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE
+EXDATE;TZID=Europe/Berlin:20261021T093000
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Berlin:20261026T093000
+SUMMARY:Standup (moved)
+LOCATION:Office
+DTSTART;TZID=Europe/Berlin:20261026T113000
+END:VEVENT
+BEGIN:VEVENT
+UID:course
+SUMMARY:Course
+LOCATION:VHS
+DTSTART;TZID=Europe/Berlin:20261101T070000
+RRULE:FREQ=DAILY;COUNT=2
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	events, err := Parse(strings.NewReader(strings.ReplaceAll(feed, "\n", "\r\n")), berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		summary string
+		start   time.Time
+	}{
+		{"weekly", at(10, 16, 12, 0), "Standup", at(10, 19, 9, 30)},
+		{"one-off", at(10, 19, 10, 0), "Dentist", at(10, 20, 9, 0)},
+		{"skipped and moved", at(10, 20, 10, 0), "Standup (moved)", at(10, 26, 11, 30)},
+		{"after the moved one", at(10, 26, 12, 0), "Standup", at(10, 28, 9, 30)},
+		{"daily", at(11, 1, 19, 0), "Course", at(11, 2, 7, 0)},
+		{"daily count used up", at(11, 2, 10, 0), "Standup", at(11, 4, 9, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, ok := Next(events, tt.now)
+			if !ok {
+				t.Fatal("nothing found")
+			}
+			if ev.Summary != tt.summary || !ev.Start.Equal(tt.start) {
+				t.Errorf("got %s at %s, want %s at %s", ev.Summary, ev.Start, tt.summary, tt.start)
+			}
+		})
+	}
+
+	for _, ev := range events {
+		if ev.Summary == "Dentist" && ev.Location != "Praxis, Kastanienallee 12" {
+			t.Errorf("location %q isn't unescaped", ev.Location)
+		}
+		if ev.Summary == "Holiday" {
+			t.Error("all-day event kept")
+		}
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	start := time.Date(2026, 10, 14, 8, 0, 0, 0, berlin) // a Wednesday
+	tests := []struct {
+		rule string
+		want []string // the first occurrences, at most four
+	}{
+		{"FREQ=DAILY;COUNT=3", []string{"Wed 14", "Thu 15", "Fri 16"}},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=20261020", []string{"Wed 14", "Fri 16", "Sun 18", "Tue 20"}},
+		{"FREQ=DAILY;BYDAY=SA,SU", []string{"Sat 17", "Sun 18", "Sat 24", "Sun 25"}},
+		{"FREQ=WEEKLY", []string{"Wed 14", "Wed 21", "Wed 28", "Wed 4"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", []string{"Fri 16", "Mon 26", "Fri 30", "Mon 9"}},
+		{"FREQ=WEEKLY;BYDAY=1TU;UNTIL=20261021T000000Z", []string{"Tue 20"}},
+		{"FREQ=MONTHLY", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r := parseRule(tt.rule, berlin)
+			if r == nil {
+				if tt.want != nil {
+					t.Fatal("rule not followed")
+				}
+				return
+			}
+			var got []string
+			r.each(start, func(t time.Time) bool {
+				got = append(got, t.Format("Mon 2"))
+				return len(got) < 4
+			})
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
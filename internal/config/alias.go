@@ -0,0 +1,54 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"go-commute/internal/model"
+)
+
+// AliasTarget looks up a station alias like "home" or "oma", ignoring
+// case. The target is a station ID or a name to search for.
+func (c Config) AliasTarget(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for alias, target := range c.Aliases {
+		if strings.EqualFold(alias, name) {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// AliasOf is the alias pointing at a station ID, if any
+func (c Config) AliasOf(id string) string {
+	var found string
+	for alias, target := range c.Aliases {
+		// Several aliases may point at the same stop, pick one stably
+		if target == id && (found == "" || alias < found) {
+			found = alias
+		}
+	}
+	return found
+}
+
+// MatchAliases lists the aliases starting with prefix, sorted
+func (c Config) MatchAliases(prefix string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var names []string
+	for alias := range c.Aliases {
+		if strings.HasPrefix(strings.ToLower(alias), prefix) {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Label is how a station is shown: its alias, or its name without the
+// city suffix
+func (c Config) Label(s model.Station) string {
+	if alias := c.AliasOf(s.ID); alias != "" {
+		return alias
+	}
+	return model.CleanStation(s.Name)
+}
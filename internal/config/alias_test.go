@@ -0,0 +1,62 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestAliases(t *testing.T) {
+	c := Config{Aliases: map[string]string{
+		"home":   "900120004",
+		"Work":   "900023201",
+		"office": "900023201",
+		"oma":    "Rathaus Spandau",
+	}}
+
+	targets := []struct {
+		name, want string
+		ok         bool
+	}{
+		{"home", "900120004", true},
+		{" WORK ", "900023201", true},
+		{"Oma", "Rathaus Spandau", true},
+		{"ho", "", false},
+	}
+	for _, tt := range targets {
+		if got, ok := c.AliasTarget(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("AliasTarget(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	matches := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"Work", "home", "office", "oma"}},
+		{"o", []string{"office", "oma"}},
+		{"W", []string{"Work"}},
+		{"x", nil},
+	}
+	for _, tt := range matches {
+		if got := c.MatchAliases(tt.prefix); !slices.Equal(got, tt.want) {
+			t.Errorf("MatchAliases(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	labels := []struct {
+		station model.Station
+		want    string
+	}{
+		{model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}, "home"},
+		// Two aliases for the same stop settle on the same one every time
+		{model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}, "Work"},
+		{model.Station{ID: "900100003", Name: "S+U Alexanderplatz (Berlin)"}, "Alexanderplatz"},
+	}
+	for _, tt := range labels {
+		if got := c.Label(tt.station); got != tt.want {
+			t.Errorf("Label(%s) = %q, want %q", tt.station.Name, got, tt.want)
+		}
+	}
+}
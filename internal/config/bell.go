@@ -0,0 +1,37 @@
+package config
+
+// Bell modes
+const (
+	BellOff     = "off"
+	BellAudible = "audible"
+	BellVisual  = "visual"
+)
+
+// Bell selects a bell mode ("audible", "visual" or "off") per event
+type Bell struct {
+	Leave       string `json:"leave,omitempty"`        // time-to-leave alarm
+	Departure   string `json:"departure,omitempty"`    // tracked journey leaves in under 2 minutes
+	Risk        string `json:"risk,omitempty"`         // connection at risk
+	RefreshFail string `json:"refresh_fail,omitempty"` // refresh started failing
+	GetOff      string `json:"get_off,omitempty"`      // arrival or transfer stop coming up
+	Better      string `json:"better,omitempty"`       // a journey leaving later and arriving sooner showed up
+}
+
+func (c Bell) Mode(event string) string {
+	modes := map[string][2]string{
+		"leave":        {c.Leave, BellAudible},
+		"departure":    {c.Departure, BellOff},
+		"risk":         {c.Risk, BellAudible},
+		"refresh_fail": {c.RefreshFail, BellOff},
+		"get_off":      {c.GetOff, BellAudible},
+		"better":       {c.Better, BellOff},
+	}
+	m, ok := modes[event]
+	if !ok {
+		return BellOff
+	}
+	if m[0] == "" {
+		return m[1]
+	}
+	return m[0]
+}
@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestBellMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		bell  Bell
+		event string
+		want  string
+	}{
+		{"leave rings by default", Bell{}, "leave", BellAudible},
+		{"departure is quiet by default", Bell{}, "departure", BellOff},
+		{"risk rings by default", Bell{}, "risk", BellAudible},
+		{"refresh failures are quiet by default", Bell{}, "refresh_fail", BellOff},
+		{"own mode", Bell{Leave: BellVisual}, "leave", BellVisual},
+		{"turned off", Bell{Risk: BellOff}, "risk", BellOff},
+		{"turned on", Bell{Departure: BellAudible}, "departure", BellAudible},
+		{"only its own event", Bell{Leave: BellVisual}, "risk", BellAudible},
+		{"unknown event", Bell{}, "lunch", BellOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bell.Mode(tt.event); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// Bookmark is one journey kept across restarts for the day it runs, like
+// the 08:12 S3 taken every morning. Journey is the last state seen of it,
+// its refresh token fetches the latest.
+type Bookmark struct {
+	ID      string        `json:"id"`
+	Route   string        `json:"route"`
+	Journey model.Journey `json:"journey"`
+}
+
+// Bookmarked reports whether the journey with this ID is bookmarked
+func (c Config) Bookmarked(id string) bool {
+	for _, b := range c.Bookmarks {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// DropOldBookmarks forgets the bookmarks of journeys that arrived before
+// today began
+func (c *Config) DropOldBookmarks(now time.Time) {
+	local := now.In(model.DisplayZone)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, model.DisplayZone)
+	kept := c.Bookmarks[:0]
+	for _, b := range c.Bookmarks {
+		if !b.Journey.ArriveAt.Before(today) {
+			kept = append(kept, b)
+		}
+	}
+	c.Bookmarks = kept
+}
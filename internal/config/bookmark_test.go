@@ -0,0 +1,42 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestDropOldBookmarks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	arriving := func(id string, at time.Time) Bookmark {
+		return Bookmark{ID: id, Journey: model.Journey{ArriveAt: at}}
+	}
+
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		want      []string
+	}{
+		{"none", nil, nil},
+		{"today's", []Bookmark{arriving("early", now.Add(-7*time.Hour)), arriving("later", now.Add(time.Hour))}, []string{"early", "later"}},
+		{"yesterday's", []Bookmark{arriving("late night", now.Add(-8*time.Hour-time.Minute)), arriving("later", now.Add(time.Hour))}, []string{"later"}},
+		{"tomorrow's", []Bookmark{arriving("tomorrow", now.Add(24*time.Hour))}, []string{"tomorrow"}},
+		// Midnight in Berlin is still the day before in UTC
+		{"after midnight", []Bookmark{arriving("just after", time.Date(2026, 10, 15, 22, 30, 0, 0, time.UTC))}, []string{"just after"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Bookmarks: tt.bookmarks}
+			c.DropOldBookmarks(now)
+			var got []string
+			for _, b := range c.Bookmarks {
+				got = append(got, b.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
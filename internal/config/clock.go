@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// twelveHourLocales are the locales whose clocks usually read 3:04 PM
+var twelveHourLocales = []string{
+	"en_US", "en_CA", "en_AU", "en_NZ", "en_IN", "en_PH", "es_US",
+	"hi_IN", "bn_BD", "ur_PK", "ar_EG", "ar_SA", "ko_KR",
+}
+
+// TwelveHour reports whether times are shown with AM and PM: as
+// configured, else as the locale has it
+func (c Config) TwelveHour() bool {
+	switch strings.ToLower(c.Clock) {
+	case "12h", "12":
+		return true
+	case "24h", "24":
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			for _, l := range twelveHourLocales {
+				if strings.HasPrefix(v, l) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestTwelveHour(t *testing.T) {
+	tests := []struct {
+		name                string
+		clock               string
+		lcAll, lcTime, lang string
+		want                bool
+	}{
+		{"nothing set", "", "", "", "", false},
+		{"configured 12h", "12h", "", "", "de_DE.UTF-8", true},
+		{"configured 24", "24", "", "", "en_US.UTF-8", false},
+		{"US locale", "", "", "", "en_US.UTF-8", true},
+		{"German locale", "", "", "", "de_DE.UTF-8", false},
+		{"LC_TIME over LANG", "", "", "en_GB.UTF-8", "en_US.UTF-8", false},
+		{"LC_ALL over LC_TIME", "", "en_AU.UTF-8", "de_DE.UTF-8", "", true},
+		{"unknown setting falls back", "sundial", "", "", "en_US.UTF-8", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_TIME", tt.lcTime)
+			t.Setenv("LANG", tt.lang)
+			if got := (Config{Clock: tt.clock}).TwelveHour(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
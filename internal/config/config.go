@@ -0,0 +1,398 @@
+// Package config loads and saves the user preferences in ~/.commute_favorites.json.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/provider"
+)
+
+const fileName = ".commute_favorites.json"
+
+// Config stores user preferences
+type Config struct {
+	Routes     []model.FavoriteRoute `json:"routes"`
+	LastOrigin model.Station         `json:"last_origin"`
+	LastDest   model.Station         `json:"last_dest"`
+	MQTT       *MQTT                 `json:"mqtt,omitempty"`
+	Notify     Notify                `json:"notify"`
+	LeaveAlarm LeaveAlarm            `json:"leave_alarm"`
+	GetOff     GetOff                `json:"get_off"`
+
+	TransferBuffer int      `json:"transfer_buffer_min,omitempty"`
+	WatchLines     []string `json:"watch_lines,omitempty"`
+
+	Bell Bell `json:"bell"`
+
+	NoAnimations  bool `json:"no_animations,omitempty"`
+	ReducedMotion bool `json:"-"` // set by --no-animations
+
+	Timezone string `json:"timezone,omitempty"` // display timezone, the provider's by default
+	Clock    string `json:"clock,omitempty"`    // "12h" or "24h", by the locale otherwise
+	Provider string `json:"provider,omitempty"` // hafas-rest preset, vbb by default
+
+	Location *model.Coordinates `json:"location,omitempty"` // where "nearby" looks by default
+	Home     *model.Station     `json:"home,omitempty"`     // where 'H' plans to, the provider's default otherwise
+
+	RoundTripStay string `json:"round_trip_stay,omitempty"` // what 'T' suggests, e.g. "2h30m"
+
+	Avoid model.Avoidance `json:"avoid"`
+
+	Ticket string `json:"ticket,omitempty"` // AB, BC, ABC or deutschlandticket
+
+	NoWeather bool `json:"no_weather,omitempty"` // hide the Open-Meteo weather in the header
+
+	Footprint bool `json:"footprint,omitempty"` // estimate CO2 against driving in the journey details
+
+	Aliases map[string]string `json:"aliases,omitempty"` // "home" → station ID or name, for --from, search and favorites
+
+	Calendar *Calendar `json:"calendar,omitempty"`
+
+	LineWatch *LineWatch `json:"line_watch,omitempty"` // pinned above the journeys with 'L'
+
+	HideCancelled bool   `json:"hide_cancelled,omitempty"` // drop cancelled journeys instead of listing them last
+	Departed      string `json:"departed,omitempty"`       // "hide" or "collapse" journeys that have left, 'd' shows them
+
+	WalkMin         int  `json:"walk_min,omitempty"`         // minutes from the door to the origin stop
+	HideUncatchable bool `json:"hide_uncatchable,omitempty"` // put away journeys leaving before the stop can be reached
+
+	WarmStart int `json:"warm_start,omitempty"` // favorites fetched at launch along with the last route
+
+	JourneyLinks string `json:"journey_links,omitempty"` // what 'O' opens: "bvg" for BVG Fahrinfo, Google Maps otherwise
+
+	Hooks map[string][]string `json:"hooks,omitempty"` // event → shell commands, fed the event as JSON on stdin
+
+	Macros map[string][]string `json:"macros,omitempty"` // key → commands run one after the other, e.g. "g": ["favorite Gym", "transfers 1"]
+
+	Templates Templates `json:"templates"`
+
+	Columns []string `json:"columns,omitempty"` // journey row layout, e.g. ["leave", "countdown", "lines"]
+	Density string   `json:"density,omitempty"` // low, medium or high, switched with 'z'
+
+	Palette  string                  `json:"palette,omitempty"`  // "colorblind" for the Okabe-Ito colors, or "gruvbox"
+	Products map[string]ProductStyle `json:"products,omitempty"` // product ID → color and icon replacing the provider's
+	Icons    string                  `json:"icons,omitempty"`    // "emoji" or "nerd" (Nerd Font) instead of the [S] tags
+	ASCII    bool                    `json:"-"`                  // set when the terminal can't show those icons
+
+	AutoReverse *AutoReverse `json:"auto_reverse,omitempty"`
+	LastUsed    time.Time    `json:"last_used,omitempty"` // when the last route last refreshed
+
+	Recent    []model.FavoriteRoute `json:"recent,omitempty"`    // routes refreshed lately, newest first, for Ctrl-P
+	Bookmarks []Bookmark            `json:"bookmarks,omitempty"` // journeys bookmarked with 'B', kept for the day
+
+	RouteChosen bool `json:"-"` // set when flags or a favorite's schedule picked the route
+}
+
+// Path is where the config lives
+func Path() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, fileName)
+}
+
+// Load reads the config, falling back to defaults, and applies its
+// display timezone and clock
+func Load() Config {
+	var config Config
+	if data, err := os.ReadFile(Path()); err == nil {
+		json.Unmarshal(data, &config)
+	}
+
+	preset := config.Preset()
+	if config.LastOrigin.ID == "" {
+		config.LastOrigin = preset.Home
+	}
+	if config.LastDest.ID == "" {
+		config.LastDest = preset.Work
+	}
+	model.SetDisplayZone(config.Zone())
+	model.SetTwelveHour(config.TwelveHour())
+	return config
+}
+
+// Save writes the config back to disk
+func Save(config Config) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(Path(), data, 0644)
+}
+
+// Preset is the configured provider preset, or the default one if the
+// name is unknown, with the configured product styles applied
+func (c Config) Preset() provider.Preset {
+	p, err := provider.Lookup(c.Provider)
+	if err != nil {
+		p, _ = provider.Lookup(provider.Default)
+	}
+	palette, icons := palettes[c.Palette], iconSets[c.Icons]
+	if c.ASCII {
+		icons = nil
+	}
+	if len(c.Products) > 0 || palette != nil || icons != nil {
+		p.Products = slices.Clone(p.Products)
+		for i, prod := range p.Products {
+			style := c.Products[prod.ID]
+			if color, ok := palette[prod.ID]; ok && style.Color == "" {
+				style.Color = color
+			}
+			if icon, ok := icons[prod.ID]; ok && style.Icon == "" {
+				style.Icon = icon
+			}
+			if style.Color != "" {
+				p.Products[i].Color = style.Color
+			}
+			if style.Icon != "" {
+				p.Products[i].Icon = style.Icon
+			}
+		}
+	}
+	return p
+}
+
+// palettes recolor the products as a set. Okabe-Ito's eight colors stay
+// apart for every kind of color blindness.
+var palettes = map[string]map[string]string{
+	"colorblind": {
+		"suburban":        "#009E73",
+		"subway":          "#0072B2",
+		"tram":            "#D55E00",
+		"bus":             "#CC79A7",
+		"ferry":           "#56B4E9",
+		"regional":        "#F0E442",
+		"regionalExpress": "#F0E442",
+		"express":         "#E69F00",
+		"national":        "#E69F00",
+		"nationalExpress": "#E69F00",
+	},
+	"gruvbox": {
+		"suburban":        "#b8bb26",
+		"subway":          "#83a598",
+		"tram":            "#fb4934",
+		"bus":             "#d3869b",
+		"ferry":           "#8ec07c",
+		"regional":        "#fabd2f",
+		"regionalExpress": "#fabd2f",
+		"express":         "#fe8019",
+		"national":        "#fe8019",
+		"nationalExpress": "#fe8019",
+	},
+}
+
+// PaletteNames lists the palettes, sorted
+func PaletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// iconSets replace the provider's bracket tags. The Nerd Font glyphs are
+// Font Awesome's, which every patched font carries.
+var iconSets = map[string]map[string]string{
+	"emoji": {
+		"suburban":        "🚆",
+		"subway":          "🚇",
+		"tram":            "🚋",
+		"bus":             "🚌",
+		"ferry":           "⛴",
+		"regional":        "🚆",
+		"regionalExpress": "🚆",
+		"express":         "🚄",
+		"national":        "🚄",
+		"nationalExpress": "🚄",
+	},
+	"nerd": {
+		"suburban":        "\uf238",
+		"subway":          "\uf239",
+		"tram":            "\uf238",
+		"bus":             "\uf207",
+		"ferry":           "\uf21a",
+		"regional":        "\uf238",
+		"regionalExpress": "\uf238",
+		"express":         "\uf238",
+		"national":        "\uf238",
+		"nationalExpress": "\uf238",
+	},
+}
+
+// ProductStyle is how one mode of transport looks in the journeys. Empty
+// fields keep the provider's.
+type ProductStyle struct {
+	Color string `json:"color,omitempty"` // tview color name or #rrggbb
+	Icon  string `json:"icon,omitempty"`  // e.g. "[S]" or "🚆"
+}
+
+// ValidateProducts rejects unknown palettes, icon sets and styles for products the
+// provider doesn't have
+func (c Config) ValidateProducts() error {
+	if _, ok := palettes[c.Palette]; c.Palette != "" && !ok {
+		return fmt.Errorf("unknown palette %q in config, use %s", c.Palette, strings.Join(PaletteNames(), " or "))
+	}
+	if _, ok := iconSets[c.Icons]; c.Icons != "" && !ok {
+		return fmt.Errorf("unknown icons %q in config, use emoji or nerd", c.Icons)
+	}
+	p := c.Preset()
+	for id := range c.Products {
+		if _, ok := p.Product(id); !ok {
+			var ids []string
+			for _, prod := range p.Products {
+				ids = append(ids, prod.ID)
+			}
+			return fmt.Errorf("unknown product %q in config, %s has %s", id, p.Name, strings.Join(ids, ", "))
+		}
+	}
+	return nil
+}
+
+// maxRecent is how many recent routes are remembered
+const maxRecent = 8
+
+// RememberRoute puts a route first among the recent ones
+func (c *Config) RememberRoute(origin, dest model.Station) {
+	recent := []model.FavoriteRoute{{Origin: origin, Dest: dest}}
+	for _, r := range c.Recent {
+		if len(recent) == maxRecent {
+			break
+		}
+		if !sameStation(r.Origin, origin) || !sameStation(r.Dest, dest) {
+			recent = append(recent, r)
+		}
+	}
+	c.Recent = recent
+}
+
+// sameStation compares stops by ID and addresses, which have none, by name
+func sameStation(a, b model.Station) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name == b.Name
+}
+
+// HomeStation is the configured home, else the provider's default one
+func (c Config) HomeStation() model.Station {
+	if c.Home != nil && c.Home.ID != "" {
+		return *c.Home
+	}
+	return c.Preset().Home
+}
+
+// Zone is the display timezone: the configured one, else the provider's
+func (c Config) Zone() string {
+	if c.Timezone != "" {
+		return c.Timezone
+	}
+	return c.Preset().Timezone
+}
+
+// WalkTime is how long it takes to get to the origin stop. Journeys from
+// an address include the walk already.
+func (c Config) WalkTime() time.Duration {
+	if c.LastOrigin.IsAddress() || c.WalkMin <= 0 {
+		return 0
+	}
+	return time.Duration(c.WalkMin) * time.Minute
+}
+
+// TransferMargin is the time needed to change between legs
+func (c Config) TransferMargin() time.Duration {
+	if c.TransferBuffer <= 0 {
+		return 2 * time.Minute
+	}
+	return time.Duration(c.TransferBuffer) * time.Minute
+}
+
+// LeaveAlarm controls the "time to leave" alarm for the tracked journey
+type LeaveAlarm struct {
+	Minutes int  `json:"minutes,omitempty"` // minutes before departure, 0 disables
+	Desktop bool `json:"desktop,omitempty"`
+}
+
+// Calendar is where 'N' finds the next appointment to plan for
+type Calendar struct {
+	Source   string `json:"source"`              // .ics file, feed or CalDAV collection URL
+	EarlyMin int    `json:"early_min,omitempty"` // arrive this many minutes early, 10 by default
+}
+
+// Early is how long before the appointment to arrive
+func (c Calendar) Early() time.Duration {
+	if c.EarlyMin <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.EarlyMin) * time.Minute
+}
+
+// AutoReverse offers the way back at startup when the route was last used
+// in the morning and it's now later in the day
+type AutoReverse struct {
+	AfterHour int  `json:"after_hour,omitempty"` // from this hour on, 14 by default
+	Switch    bool `json:"switch,omitempty"`     // reverse right away, 'u' undoes it
+}
+
+// Hour is when the morning's route turns into the evening's
+func (r AutoReverse) Hour() int {
+	if r.AfterHour <= 0 || r.AfterHour > 23 {
+		return 14
+	}
+	return r.AfterHour
+}
+
+// Templates are Go templates replacing the built-in formats, filled with
+// statusline.Fields like {{.Line}} {{.Next}} +{{.Delay}}
+type Templates struct {
+	Header string `json:"header,omitempty"` // the route in the TUI header
+	Status string `json:"status,omitempty"` // what `check -v` prints, e.g. for a status bar
+}
+
+// GetOff warns before the tracked journey's next arrival or transfer stop
+type GetOff struct {
+	Minutes int  `json:"minutes,omitempty"` // minutes before arriving, 0 disables
+	Stops   int  `json:"stops,omitempty"`   // stops before arriving, 0 disables
+	Desktop bool `json:"desktop,omitempty"`
+}
+
+// LineWatch follows one line's departures in one direction at one stop
+type LineWatch struct {
+	Stop      model.Station `json:"stop"`
+	Line      string        `json:"line"`
+	Direction string        `json:"direction,omitempty"` // part of the headsign, any direction if empty
+}
+
+// Matches reports whether a departure is the watched line going the
+// watched way
+func (w LineWatch) Matches(d model.Departure) bool {
+	if !strings.EqualFold(d.Line, w.Line) {
+		return false
+	}
+	return w.Direction == "" || strings.Contains(strings.ToLower(d.Direction), strings.ToLower(w.Direction))
+}
+
+// Label reads "S3 → Erkner at Köpenick"
+func (w LineWatch) Label() string {
+	label := w.Line
+	if w.Direction != "" {
+		label += " → " + w.Direction
+	}
+	return label + " at " + model.CleanStation(w.Stop.Name)
+}
+
+// MQTT configures publishing of departures to an MQTT broker
+type MQTT struct {
+	Broker   string `json:"broker"`
+	Topic    string `json:"topic,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Retain   bool   `json:"retain,omitempty"`
+}
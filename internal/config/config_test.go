@@ -0,0 +1,127 @@
+package config
+
+import (
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestHomeStation(t *testing.T) {
+	mine := model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	tests := []struct {
+		name string
+		c    Config
+		want string
+	}{
+		{"configured", Config{Home: &mine}, "900120004"},
+		{"default provider's", Config{}, "900180001"},
+		{"without an ID", Config{Home: &model.Station{Name: "Home"}}, "900180001"},
+		{"other provider's", Config{Provider: "db"}, "8011160"},
+		{"unknown provider", Config{Provider: "mvg"}, "900180001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.HomeStation(); got.ID != tt.want {
+				t.Errorf("got %s, want %s", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineWatch(t *testing.T) {
+	koepenick := model.Station{ID: "900180001", Name: "S Köpenick (Berlin)"}
+	tests := []struct {
+		name  string
+		watch LineWatch
+		dep   model.Departure
+		match bool
+		label string
+	}{
+		{"any direction", LineWatch{Stop: koepenick, Line: "S3"}, model.Departure{Line: "S3", Direction: "S Spandau"}, true, "S3 at Köpenick"},
+		{"other line", LineWatch{Stop: koepenick, Line: "S3"}, model.Departure{Line: "S5", Direction: "S Spandau"}, false, "S3 at Köpenick"},
+		{"line in other case", LineWatch{Stop: koepenick, Line: "x69"}, model.Departure{Line: "X69", Direction: "Müggelheim"}, true, "x69 at Köpenick"},
+		{"part of the headsign", LineWatch{Stop: koepenick, Line: "S3", Direction: "erkner"}, model.Departure{Line: "S3", Direction: "S Erkner Bhf"}, true, "S3 → erkner at Köpenick"},
+		{"the other way", LineWatch{Stop: koepenick, Line: "S3", Direction: "Erkner"}, model.Departure{Line: "S3", Direction: "S Spandau"}, false, "S3 → Erkner at Köpenick"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.watch.Matches(tt.dep); got != tt.match {
+				t.Errorf("Matches = %v, want %v", got, tt.match)
+			}
+			if got := tt.watch.Label(); got != tt.label {
+				t.Errorf("Label = %q, want %q", got, tt.label)
+			}
+		})
+	}
+}
+
+func TestAutoReverseHour(t *testing.T) {
+	tests := []struct{ after, want int }{
+		{0, 14},
+		{16, 16},
+		{23, 23},
+		{24, 14},
+		{-1, 14},
+	}
+	for _, tt := range tests {
+		if got := (AutoReverse{AfterHour: tt.after}).Hour(); got != tt.want {
+			t.Errorf("Hour() with after_hour %d = %d, want %d", tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestPresetStyles(t *testing.T) {
+	tests := []struct {
+		name        string
+		c           Config
+		color, icon string // the suburban train's
+	}{
+		{"provider's", Config{}, "green", "[S]"},
+		{"palette", Config{Palette: "colorblind"}, "#009E73", "[S]"},
+		{"own style", Config{Products: map[string]ProductStyle{"suburban": {Color: "lime", Icon: "(S)"}}}, "lime", "(S)"},
+		{"own color over the palette", Config{Palette: "colorblind", Products: map[string]ProductStyle{"suburban": {Color: "lime"}}}, "lime", "[S]"},
+		{"other product styled", Config{Products: map[string]ProductStyle{"bus": {Color: "pink"}}}, "green", "[S]"},
+		{"emoji", Config{Icons: "emoji"}, "green", "🚆"},
+		{"Nerd Font", Config{Icons: "nerd", Palette: "colorblind"}, "#009E73", "\uf238"},
+		{"icons without a unicode terminal", Config{Icons: "emoji", ASCII: true}, "green", "[S]"},
+		{"own icon over the set", Config{Icons: "emoji", Products: map[string]ProductStyle{"suburban": {Icon: "S"}}}, "green", "S"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prod, ok := tt.c.Preset().Product("suburban")
+			if !ok {
+				t.Fatal("no suburban product")
+			}
+			if prod.Color != tt.color || prod.Icon != tt.icon {
+				t.Errorf("got %s %s, want %s %s", prod.Color, prod.Icon, tt.color, tt.icon)
+			}
+		})
+	}
+	if prod, _ := (Config{}).Preset().Product("suburban"); prod.Color != "green" {
+		t.Errorf("styling changed the provider's own products to %s", prod.Color)
+	}
+}
+
+func TestValidateProducts(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Config
+		ok   bool
+	}{
+		{"nothing set", Config{}, true},
+		{"palette", Config{Palette: "colorblind"}, true},
+		{"unknown palette", Config{Palette: "sepia"}, false},
+		{"icons", Config{Icons: "nerd"}, true},
+		{"unknown icons", Config{Icons: "wingdings"}, false},
+		{"styled product", Config{Products: map[string]ProductStyle{"tram": {Color: "red"}}}, true},
+		{"unknown product", Config{Products: map[string]ProductStyle{"zeppelin": {Color: "red"}}}, false},
+		{"other provider's product", Config{Provider: "db", Products: map[string]ProductStyle{"express": {Color: "red"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.ValidateProducts(); (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+// Notify configures when and where alerts are sent
+type Notify struct {
+	DelayThreshold int       `json:"delay_threshold_min,omitempty"`
+	Desktop        bool      `json:"desktop,omitempty"`
+	Webhooks       []Webhook `json:"webhooks,omitempty"`
+	Telegram       *Telegram `json:"telegram,omitempty"`
+	Pushover       *Pushover `json:"pushover,omitempty"`
+
+	QuietHours QuietHours  `json:"quiet_hours"`
+	Rules      []AlertRule `json:"rules,omitempty"`
+
+	BetterJourney bool `json:"better_journey,omitempty"` // notify when a later journey arrives sooner than the pinned one
+}
+
+func (c Notify) Threshold() int {
+	if c.DelayThreshold <= 0 {
+		return 5
+	}
+	return c.DelayThreshold
+}
+
+// Webhook is a URL receiving alerts as JSON POSTs. Format selects
+// the payload shape: "json" (default), "slack" or "discord".
+type Webhook struct {
+	URL    string `json:"url"`
+	Format string `json:"format,omitempty"`
+}
+
+// Telegram sends alerts through a Telegram bot to a chat
+type Telegram struct {
+	Token  string `json:"token"`
+	ChatID string `json:"chat_id"`
+}
+
+// Pushover sends alerts through the Pushover service
+type Pushover struct {
+	Token    string `json:"token"`
+	User     string `json:"user"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// AlertRule is a user-defined alert condition evaluated against every leg
+// on each refresh, e.g.
+//
+//	{"name": "S3 late", "when": "line == \"S3\" and depDelay > 300", "notify": ["desktop"]}
+//
+// Available fields: line, product, from, to, route, depDelay, arrDelay
+// (seconds), leavesIn (minutes), cancelled, occupancy, platform,
+// plannedPlatform, platformChanged, warning and warnings.
+type AlertRule struct {
+	Name   string   `json:"name,omitempty"`
+	When   string   `json:"when"`
+	Notify []string `json:"notify,omitempty"` // channels, default: all configured
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// QuietHours suppresses notifications and slows down auto-refresh
+// during the given daily ranges (e.g. "22:00-06:30") and, optionally,
+// all weekend long
+type QuietHours struct {
+	Ranges          []string `json:"ranges,omitempty"`
+	Weekends        bool     `json:"weekends,omitempty"`
+	RefreshInterval int      `json:"refresh_interval_min,omitempty"`
+}
+
+// Active reports whether t falls into quiet hours, going by the clock the
+// times are shown in rather than the host's
+func (q QuietHours) Active(t time.Time) bool {
+	t = t.In(model.DisplayZone)
+	if q.Weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true
+	}
+
+	for _, r := range q.Ranges {
+		if inClockRange(r, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// inClockRange reports whether t's time of day falls into "HH:MM-HH:MM";
+// ranges may wrap past midnight
+func inClockRange(r string, t time.Time) bool {
+	start, end, ok := parseClockRange(r)
+	if !ok {
+		return false
+	}
+	mins := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return mins >= start && mins < end
+	}
+	return mins >= start || mins < end
+}
+
+// Interval returns how often to refresh while quiet hours are active
+func (q QuietHours) Interval() time.Duration {
+	if q.RefreshInterval <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(q.RefreshInterval) * time.Minute
+}
+
+// parseClockRange parses "HH:MM-HH:MM" into minutes since midnight
+func parseClockRange(r string) (int, int, bool) {
+	parts := strings.Split(r, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(parts[0]))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), true
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	// Friday 16 October 2026
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, model.DisplayZone)
+	}
+	night := QuietHours{Ranges: []string{"22:00-06:30"}}
+	lunch := QuietHours{Ranges: []string{"bogus", " 12:00 - 13:00 "}}
+	weekends := QuietHours{Weekends: true}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{"none set", QuietHours{}, at(16, 23, 0), false},
+		{"before midnight", night, at(16, 23, 0), true},
+		{"after midnight", night, at(17, 3, 0), true},
+		{"at the start", night, at(16, 22, 0), true},
+		{"at the end", night, at(17, 6, 30), false},
+		{"daytime", night, at(16, 12, 0), false},
+		{"same-day range", lunch, at(16, 12, 30), true},
+		{"after a same-day range", lunch, at(16, 13, 0), false},
+		{"saturday", weekends, at(17, 12, 0), true},
+		{"friday", weekends, at(16, 12, 0), false},
+		// 21:30 UTC is 23:30 in Berlin
+		{"display timezone", night, time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Active(tt.t); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
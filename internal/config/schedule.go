@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// ScheduledRoute is the first favorite whose schedule covers t, so the
+// app opens on Home→Work in the morning and Work→Home in the evening.
+// Schedules are comma-separated "HH:MM-HH:MM" ranges in the display
+// timezone and may wrap past midnight.
+func (c Config) ScheduledRoute(t time.Time) (model.FavoriteRoute, bool) {
+	t = t.In(model.DisplayZone)
+	for _, fav := range c.Routes {
+		for _, r := range strings.Split(fav.Schedule, ",") {
+			if inClockRange(r, t) {
+				return fav, true
+			}
+		}
+	}
+	return model.FavoriteRoute{}, false
+}
+
+// ValidateSchedule checks a favorite's schedule, "" being none
+func ValidateSchedule(schedule string) error {
+	if strings.TrimSpace(schedule) == "" {
+		return nil
+	}
+	for _, r := range strings.Split(schedule, ",") {
+		if _, _, ok := parseClockRange(r); !ok {
+			return fmt.Errorf("%q isn't a time range like 05:00-12:00", strings.TrimSpace(r))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestScheduledRoute(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, model.DisplayZone)
+	}
+	route := func(dest, schedule string) model.FavoriteRoute {
+		return model.FavoriteRoute{Dest: model.Station{Name: dest}, Schedule: schedule}
+	}
+	c := Config{Routes: []model.FavoriteRoute{
+		route("Gym", ""),
+		route("Work", "05:00-12:00"),
+		route("Home", "bogus, 15:00-20:00"),
+		route("Bar", "22:00-02:00"),
+		route("Work late", "16:00-18:00"),
+	}}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string // empty for none
+	}{
+		{"morning", at(16, 8, 0), "Work"},
+		{"at the start", at(16, 5, 0), "Work"},
+		{"at the end", at(16, 12, 0), ""},
+		{"first listed wins", at(16, 17, 0), "Home"},
+		{"before midnight", at(16, 23, 30), "Bar"},
+		{"after midnight", at(17, 1, 0), "Bar"},
+		{"nothing scheduled", at(16, 13, 0), ""},
+		{"other timezone", at(16, 8, 0).In(time.UTC), "Work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fav, ok := c.ScheduledRoute(tt.t)
+			if ok != (tt.want != "") || fav.Dest.Name != tt.want {
+				t.Errorf("got %q, %v, want %q", fav.Dest.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		ok       bool
+	}{
+		{"", true},
+		{"  ", true},
+		{"05:00-12:00", true},
+		{"05:00-09:00, 16:00-19:00", true},
+		{"22:00-02:00", true},
+		{"mornings", false},
+		{"05:00", false},
+		{"05:00-12:00,", false},
+		{"25:00-26:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			if err := ValidateSchedule(tt.schedule); (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
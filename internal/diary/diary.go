@@ -0,0 +1,133 @@
+// Package diary keeps the log of journeys the user actually took.
+package diary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+const diaryFile = ".commute_diary.json"
+
+// Entry is a journey the user marked as actually taken
+type Entry struct {
+	Route            string    `json:"route"`
+	Lines            []string  `json:"lines"`
+	TripIDs          []string  `json:"trip_ids"`
+	PlannedDeparture time.Time `json:"planned_departure"`
+	PlannedArrival   time.Time `json:"planned_arrival"`
+	ActualDeparture  time.Time `json:"actual_departure"`
+	ActualArrival    time.Time `json:"actual_arrival"`
+	Marked           time.Time `json:"marked"`
+}
+
+func (e Entry) PlannedDuration() time.Duration {
+	return e.PlannedArrival.Sub(e.PlannedDeparture)
+}
+
+// ActualDuration measures from the planned departure, since that's when
+// the user had to be at the stop, to the realtime arrival
+func (e Entry) ActualDuration() time.Duration {
+	return e.ActualArrival.Sub(e.PlannedDeparture)
+}
+
+// Diary is the persisted log of journeys taken
+type Diary struct {
+	mu      sync.Mutex
+	Entries []Entry `json:"entries"`
+}
+
+func filePath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, diaryFile)
+}
+
+func Load() *Diary {
+	d := &Diary{}
+	if data, err := os.ReadFile(filePath()); err == nil {
+		json.Unmarshal(data, d)
+	}
+	return d
+}
+
+// Snapshot returns a copy of the entries that is safe to read while the
+// diary keeps updating
+func (d *Diary) Snapshot() []Entry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Entry(nil), d.Entries...)
+}
+
+func (d *Diary) Save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath(), data, 0644)
+}
+
+func NewEntry(route string, j model.Journey) Entry {
+	first, last := j.Legs[0], j.Legs[len(j.Legs)-1]
+	e := Entry{
+		Route:            route,
+		PlannedDeparture: first.Departure.Add(-time.Duration(first.DepDelay) * time.Second),
+		PlannedArrival:   last.Arrival.Add(-time.Duration(last.ArrDelay) * time.Second),
+		ActualDeparture:  first.Departure,
+		ActualArrival:    last.Arrival,
+		Marked:           time.Now(),
+	}
+	for _, leg := range j.Legs {
+		e.Lines = append(e.Lines, leg.Line)
+		e.TripIDs = append(e.TripIDs, leg.TripID)
+	}
+	return e
+}
+
+// Add records a journey, replacing an earlier mark of the same journey
+func (d *Diary) Add(e Entry) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, old := range d.Entries {
+		if strings.Join(old.TripIDs, "|") == strings.Join(e.TripIDs, "|") {
+			d.Entries[i] = e
+			return false
+		}
+	}
+	d.Entries = append(d.Entries, e)
+	return true
+}
+
+// Update refreshes the actual times of recent entries from newly fetched
+// journeys, so the log reflects the realtime data until arrival
+func (d *Diary) Update(journeys []model.Journey) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	changed := false
+	cutoff := time.Now().Add(-30 * time.Minute)
+	for i := range d.Entries {
+		e := &d.Entries[i]
+		if e.ActualArrival.Before(cutoff) {
+			continue
+		}
+		for _, j := range journeys {
+			if len(j.Legs) != len(e.TripIDs) || j.Legs[0].TripID != e.TripIDs[0] ||
+				j.Legs[len(j.Legs)-1].TripID != e.TripIDs[len(e.TripIDs)-1] {
+				continue
+			}
+			if !j.Legs[0].Departure.Equal(e.ActualDeparture) || !j.ArriveAt.Equal(e.ActualArrival) {
+				e.ActualDeparture = j.Legs[0].Departure
+				e.ActualArrival = j.ArriveAt
+				changed = true
+			}
+		}
+	}
+	return changed
+}
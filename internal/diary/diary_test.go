@@ -0,0 +1,100 @@
+package diary
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestNewEntry(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2026, 10, 16, 8, minute, 0, 0, time.UTC) }
+	j := model.Journey{Legs: []model.Leg{
+		{Line: "S5", TripID: "1|S5", Departure: at(4), DepDelay: 120, Arrival: at(10)},
+		{Line: "U2", TripID: "1|U2", Departure: at(14), Arrival: at(36), ArrDelay: 300},
+	}}
+	e := NewEntry("home → work", j)
+
+	if !reflect.DeepEqual(e.Lines, []string{"S5", "U2"}) || !reflect.DeepEqual(e.TripIDs, []string{"1|S5", "1|U2"}) {
+		t.Errorf("lines %v, trips %v", e.Lines, e.TripIDs)
+	}
+	if !e.PlannedDeparture.Equal(at(2)) || !e.PlannedArrival.Equal(at(31)) {
+		t.Errorf("planned %s to %s, want 08:02 to 08:31", e.PlannedDeparture, e.PlannedArrival)
+	}
+	if e.PlannedDuration() != 29*time.Minute || e.ActualDuration() != 34*time.Minute {
+		t.Errorf("took %s of %s planned, want 34m of 29m", e.ActualDuration(), e.PlannedDuration())
+	}
+}
+
+func TestAdd(t *testing.T) {
+	d := &Diary{}
+	if !d.Add(Entry{TripIDs: []string{"1|S5"}, Route: "first"}) {
+		t.Error("a new journey isn't new")
+	}
+	if !d.Add(Entry{TripIDs: []string{"1|S5", "1|U2"}}) {
+		t.Error("a journey sharing its first trip isn't new")
+	}
+	if d.Add(Entry{TripIDs: []string{"1|S5"}, Route: "again"}) {
+		t.Error("marking a journey again adds it twice")
+	}
+	if len(d.Entries) != 2 || d.Entries[0].Route != "again" {
+		t.Errorf("entries %+v, want the second mark in place of the first", d.Entries)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	entry := Entry{
+		TripIDs:         []string{"1|S5", "1|U2"},
+		ActualDeparture: now.Add(-10 * time.Minute),
+		ActualArrival:   now.Add(20 * time.Minute),
+	}
+	journey := func(trips []string, dep, arr time.Duration) model.Journey {
+		j := model.Journey{ArriveAt: now.Add(arr)}
+		for _, id := range trips {
+			j.Legs = append(j.Legs, model.Leg{TripID: id, Departure: now.Add(dep)})
+		}
+		return j
+	}
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		journeys []model.Journey
+		changed  bool
+		arrive   time.Duration
+	}{
+		{"running late", entry, []model.Journey{journey([]string{"1|S5", "1|U2"}, -10*time.Minute, 24*time.Minute)}, true, 24 * time.Minute},
+		{"unchanged", entry, []model.Journey{journey([]string{"1|S5", "1|U2"}, -10*time.Minute, 20*time.Minute)}, false, 20 * time.Minute},
+		{"other journey", entry, []model.Journey{journey([]string{"1|S5", "2|U2"}, -10*time.Minute, 24*time.Minute)}, false, 20 * time.Minute},
+		{"long arrived", Entry{TripIDs: []string{"1|S5"}, ActualArrival: now.Add(-time.Hour)},
+			[]model.Journey{journey([]string{"1|S5"}, -90*time.Minute, 5*time.Minute)}, false, -time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Diary{Entries: []Entry{tt.entry}}
+			if got := d.Update(tt.journeys); got != tt.changed {
+				t.Errorf("changed = %v, want %v", got, tt.changed)
+			}
+			if got := d.Entries[0].ActualArrival; !got.Equal(now.Add(tt.arrive)) {
+				t.Errorf("arrives %s, want %s", got, now.Add(tt.arrive))
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	d := Load()
+	if len(d.Entries) != 0 {
+		t.Fatalf("a fresh diary has %d entries", len(d.Entries))
+	}
+	d.Add(Entry{Route: "home → work", Lines: []string{"S5"}, TripIDs: []string{"1|S5"}})
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := Load().Snapshot(); len(got) != 1 || got[0].Route != "home → work" {
+		t.Errorf("loaded %+v", got)
+	}
+}
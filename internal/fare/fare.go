@@ -0,0 +1,146 @@
+// Package fare works out which VBB fare zones a journey touches and
+// whether a ticket covers it. Zones come from where the legs start and
+// end, so a train passing through zone A without stopping isn't noticed:
+// the result is a good hint, not a ticket inspector's verdict.
+package fare
+
+import (
+	"strings"
+
+	"go-commute/internal/model"
+)
+
+// Zones is a set of VBB fare zones, written like "AB"
+type Zones string
+
+// ringbahn traces the S-Bahn ring, which bounds zone A, station by station
+var ringbahn = []model.Coordinates{
+	{Latitude: 52.501147, Longitude: 13.283036}, // Westkreuz
+	{Latitude: 52.518220, Longitude: 13.284958}, // Westend
+	{Latitude: 52.530276, Longitude: 13.299437}, // Jungfernheide
+	{Latitude: 52.534337, Longitude: 13.328816}, // Beusselstraße
+	{Latitude: 52.536179, Longitude: 13.343839}, // Westhafen
+	{Latitude: 52.542732, Longitude: 13.366061}, // Wedding
+	{Latitude: 52.548637, Longitude: 13.388372}, // Gesundbrunnen
+	{Latitude: 52.549336, Longitude: 13.415138}, // Schönhauser Allee
+	{Latitude: 52.544829, Longitude: 13.427430}, // Prenzlauer Allee
+	{Latitude: 52.540724, Longitude: 13.438748}, // Greifswalder Str.
+	{Latitude: 52.529380, Longitude: 13.455607}, // Landsberger Allee
+	{Latitude: 52.523916, Longitude: 13.464606}, // Storkower Str.
+	{Latitude: 52.513616, Longitude: 13.475298}, // Frankfurter Allee
+	{Latitude: 52.503037, Longitude: 13.469051}, // Ostkreuz
+	{Latitude: 52.493426, Longitude: 13.461380}, // Treptower Park
+	{Latitude: 52.472823, Longitude: 13.455360}, // Sonnenallee
+	{Latitude: 52.469323, Longitude: 13.443055}, // Neukölln
+	{Latitude: 52.467138, Longitude: 13.431733}, // Hermannstr.
+	{Latitude: 52.470694, Longitude: 13.385754}, // Tempelhof
+	{Latitude: 52.475465, Longitude: 13.365575}, // Südkreuz
+	{Latitude: 52.479308, Longitude: 13.352113}, // Schöneberg
+	{Latitude: 52.478058, Longitude: 13.342867}, // Innsbrucker Platz
+	{Latitude: 52.477546, Longitude: 13.328862}, // Bundesplatz
+	{Latitude: 52.479987, Longitude: 13.312289}, // Heidelberger Platz
+	{Latitude: 52.488670, Longitude: 13.299910}, // Hohenzollerndamm
+	{Latitude: 52.496344, Longitude: 13.290537}, // Halensee
+}
+
+// ringMargin lets the ring stations themselves count as zone A
+const ringMargin = 300
+
+// zoneOf places a stop in A (inside the ring), B (rest of Berlin) or C.
+// Without coordinates the name decides between B and C.
+func zoneOf(name string, at model.Coordinates) byte {
+	if at != (model.Coordinates{}) && insideRing(at) {
+		return 'A'
+	}
+	if strings.Contains(name, "(Berlin)") || strings.HasPrefix(name, "Berlin ") || strings.Contains(name, " Berlin ") {
+		return 'B'
+	}
+	return 'C'
+}
+
+func insideRing(p model.Coordinates) bool {
+	for _, v := range ringbahn {
+		if model.Distance(p, v) <= ringMargin {
+			return true
+		}
+	}
+	// Even-odd ray casting; the ring is small enough to treat degrees as flat
+	inside := false
+	for i, j := 0, len(ringbahn)-1; i < len(ringbahn); j, i = i, i+1 {
+		a, b := ringbahn[i], ringbahn[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// JourneyZones is the ticket area a journey needs: AB, BC or ABC. B is
+// always included since tickets are only sold that way.
+func JourneyZones(j model.Journey) Zones {
+	var a, c bool
+	for _, leg := range j.Legs {
+		for _, z := range []byte{zoneOf(leg.From, leg.FromCoords), zoneOf(leg.To, leg.ToCoords)} {
+			switch z {
+			case 'A':
+				a = true
+			case 'C':
+				c = true
+			}
+		}
+	}
+	switch {
+	case a && c:
+		return "ABC"
+	case c:
+		return "BC"
+	default:
+		return "AB"
+	}
+}
+
+// longDistance are the products regional tickets and the Deutschlandticket
+// don't cover
+var longDistance = map[string]bool{
+	"express":         true,
+	"national":        true,
+	"nationalExpress": true,
+}
+
+// Tickets lists the values the "ticket" config setting understands
+var Tickets = []string{"AB", "BC", "ABC", "deutschlandticket"}
+
+// Known reports whether ticket is one of Tickets
+func Known(ticket string) bool {
+	for _, t := range Tickets {
+		if strings.EqualFold(t, ticket) {
+			return true
+		}
+	}
+	return false
+}
+
+// Uncovered explains why ticket doesn't cover j, or returns "" when it
+// does. An empty or unknown ticket covers everything.
+func Uncovered(ticket string, j model.Journey) string {
+	if !Known(ticket) {
+		return ""
+	}
+	for _, leg := range j.Legs {
+		if longDistance[leg.Product] {
+			return leg.Line + " is long-distance"
+		}
+	}
+	if strings.EqualFold(ticket, "deutschlandticket") {
+		return ""
+	}
+	ticket = strings.ToUpper(ticket)
+	needed := JourneyZones(j)
+	for _, z := range needed {
+		if !strings.ContainsRune(ticket, z) {
+			return "needs " + string(needed)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package fare
+
+import (
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+type stop struct {
+	name string
+	at   model.Coordinates
+}
+
+var (
+	alex      = stop{"S+U Alexanderplatz (Berlin)", model.Coordinates{Latitude: 52.521508, Longitude: 13.411267}}
+	westkreuz = stop{"S Westkreuz (Berlin)", model.Coordinates{Latitude: 52.501147, Longitude: 13.283036}}
+	spandau   = stop{"S+U Rathaus Spandau (Berlin)", model.Coordinates{Latitude: 52.535499, Longitude: 13.199819}}
+	potsdam   = stop{"S Potsdam Hauptbahnhof", model.Coordinates{Latitude: 52.391659, Longitude: 13.066700}}
+	wannsee   = stop{name: "S Wannsee (Berlin)"} // no coordinates
+)
+
+func ride(from, to stop, product string) model.Leg {
+	return model.Leg{
+		Line: "S7", Product: product,
+		From: from.name, FromCoords: from.at,
+		To: to.name, ToCoords: to.at,
+	}
+}
+
+func TestJourneyZones(t *testing.T) {
+	tests := []struct {
+		name string
+		legs []model.Leg
+		want Zones
+	}{
+		{"inside the ring", []model.Leg{ride(alex, westkreuz, "suburban")}, "AB"},
+		{"outer Berlin", []model.Leg{ride(spandau, wannsee, "suburban")}, "AB"},
+		{"out to Potsdam", []model.Leg{ride(spandau, potsdam, "suburban")}, "BC"},
+		{"across all three", []model.Leg{ride(alex, westkreuz, "suburban"), ride(westkreuz, potsdam, "suburban")}, "ABC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JourneyZones(model.Journey{Legs: tt.legs}); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUncovered(t *testing.T) {
+	city := model.Journey{Legs: []model.Leg{ride(alex, westkreuz, "suburban")}}
+	potsdamFromAlex := model.Journey{Legs: []model.Leg{ride(alex, potsdam, "regional")}}
+	ice := model.Journey{Legs: []model.Leg{ride(alex, potsdam, "express")}}
+
+	tests := []struct {
+		name   string
+		ticket string
+		j      model.Journey
+		want   string
+	}{
+		{"no ticket", "", ice, ""},
+		{"unknown ticket", "monthly", ice, ""},
+		{"AB in the city", "AB", city, ""},
+		{"lower case", "ab", city, ""},
+		{"AB to Potsdam", "AB", potsdamFromAlex, "needs ABC"},
+		{"BC in the city", "BC", city, "needs AB"},
+		{"ABC to Potsdam", "ABC", potsdamFromAlex, ""},
+		{"Deutschlandticket", "deutschlandticket", potsdamFromAlex, ""},
+		{"Deutschlandticket on the ICE", "Deutschlandticket", ice, "S7 is long-distance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Uncovered(tt.ticket, tt.j); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
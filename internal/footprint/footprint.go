@@ -0,0 +1,67 @@
+// Package footprint estimates a journey's CO2 next to making the same trip
+// by car. Distances are as the crow flies between the stops, stretched by
+// a detour factor, times average emissions per passenger-kilometer, so the
+// numbers are ballpark figures for comparison, not an audit.
+package footprint
+
+import (
+	"go-commute/internal/model"
+)
+
+// detour stretches straight lines to roughly the distance on rails and roads
+const detour = 1.25
+
+// carGramsPerKm is an average car per passenger-kilometer, at its usual
+// occupancy
+const carGramsPerKm = 166
+
+// gramsPerKm is CO2 equivalents per passenger-kilometer by product,
+// roughly the German Environment Agency's averages
+var gramsPerKm = map[string]float64{
+	"suburban":        46,
+	"subway":          46,
+	"tram":            46,
+	"bus":             83,
+	"ferry":           100,
+	"regional":        46,
+	"regionalExpress": 46,
+	"express":         29,
+	"national":        29,
+	"nationalExpress": 29,
+}
+
+// Estimate is a journey's footprint
+type Estimate struct {
+	Grams    int // by transit
+	CarGrams int // driving the same distance
+	Meters   int // ridden in total, walks left out
+}
+
+// Saved is how much less than driving the journey emits
+func (e Estimate) Saved() int {
+	return e.CarGrams - e.Grams
+}
+
+// Journey estimates j, ok false when no leg has both stops' coordinates
+func Journey(j model.Journey) (Estimate, bool) {
+	var e Estimate
+	var grams, car float64
+	for _, leg := range j.Legs {
+		if leg.Cancelled || leg.FromCoords == (model.Coordinates{}) || leg.ToCoords == (model.Coordinates{}) {
+			continue
+		}
+		km := float64(model.Distance(leg.FromCoords, leg.ToCoords)) / 1000 * detour
+		factor, ok := gramsPerKm[leg.Product]
+		if !ok {
+			factor = gramsPerKm["bus"]
+		}
+		grams += km * factor
+		car += km * carGramsPerKm
+		e.Meters += int(km * 1000)
+	}
+	if e.Meters == 0 {
+		return Estimate{}, false
+	}
+	e.Grams, e.CarGrams = int(grams), int(car)
+	return e, true
+}
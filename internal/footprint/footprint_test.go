@@ -0,0 +1,46 @@
+package footprint
+
+import (
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestJourney(t *testing.T) {
+	// about 5.56 km apart, so 6.95 km ridden
+	alex := model.Coordinates{Latitude: 52.521508, Longitude: 13.411267}
+	zoo := model.Coordinates{Latitude: 52.506921, Longitude: 13.332707}
+	ride := func(product string, from, to model.Coordinates) model.Leg {
+		return model.Leg{Product: product, FromCoords: from, ToCoords: to}
+	}
+
+	tests := []struct {
+		name string
+		legs []model.Leg
+		want Estimate // within 2%
+		ok   bool
+	}{
+		{"subway", []model.Leg{ride("subway", alex, zoo)}, Estimate{Grams: 320, CarGrams: 1153, Meters: 6949}, true},
+		{"express", []model.Leg{ride("express", alex, zoo)}, Estimate{Grams: 201, CarGrams: 1153, Meters: 6949}, true},
+		{"unknown product as a bus", []model.Leg{ride("cablecar", alex, zoo)}, Estimate{Grams: 577, CarGrams: 1153, Meters: 6949}, true},
+		{"there and back", []model.Leg{ride("subway", alex, zoo), ride("bus", zoo, alex)}, Estimate{Grams: 897, CarGrams: 2307, Meters: 13898}, true},
+		{"leg without coordinates", []model.Leg{ride("subway", alex, zoo), ride("bus", zoo, model.Coordinates{})}, Estimate{Grams: 320, CarGrams: 1153, Meters: 6949}, true},
+		{"cancelled leg", []model.Leg{ride("subway", alex, zoo), {Product: "bus", FromCoords: zoo, ToCoords: alex, Cancelled: true}}, Estimate{Grams: 320, CarGrams: 1153, Meters: 6949}, true},
+		{"no coordinates at all", []model.Leg{ride("subway", model.Coordinates{}, model.Coordinates{})}, Estimate{}, false},
+	}
+	near := func(got, want int) bool {
+		d := got - want
+		return d*50 <= want && -d*50 <= want
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Journey(model.Journey{Legs: tt.legs})
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !near(got.Grams, tt.want.Grams) || !near(got.CarGrams, tt.want.CarGrams) || !near(got.Meters, tt.want.Meters) {
+				t.Errorf("got %+v, want about %+v", got, tt.want)
+			}
+		})
+	}
+}
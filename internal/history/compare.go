@@ -0,0 +1,40 @@
+package history
+
+import "time"
+
+// RouteSummary aggregates the recorded journeys of one route
+type RouteSummary struct {
+	Route     string
+	Journeys  int
+	Realized  time.Duration // average planned departure to actual arrival
+	Planned   time.Duration
+	Delayed   int
+	Cancelled int
+}
+
+func SummarizeRoute(samples []JourneySample, route string, since time.Time, threshold int) RouteSummary {
+	sum := RouteSummary{Route: route}
+	var realized, planned time.Duration
+	now := time.Now()
+	for _, s := range samples {
+		// Only journeys that have finished count as realized
+		if s.Route != route || s.PlannedDeparture.Before(since) || s.Arrival.After(now) {
+			continue
+		}
+		sum.Journeys++
+		if s.Cancelled {
+			sum.Cancelled++
+			continue
+		}
+		realized += s.Arrival.Sub(s.PlannedDeparture)
+		planned += s.PlannedArrival.Sub(s.PlannedDeparture)
+		if s.Arrival.Sub(s.PlannedArrival) >= time.Duration(threshold)*time.Minute {
+			sum.Delayed++
+		}
+	}
+	if n := sum.Journeys - sum.Cancelled; n > 0 {
+		sum.Realized = realized / time.Duration(n)
+		sum.Planned = planned / time.Duration(n)
+	}
+	return sum
+}
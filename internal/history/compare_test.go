@@ -0,0 +1,46 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeRoute(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	journey := func(route string, ago time.Duration, planned, late time.Duration) JourneySample {
+		dep := now.Add(-ago)
+		return JourneySample{Route: route, PlannedDeparture: dep, PlannedArrival: dep.Add(planned), Arrival: dep.Add(planned + late)}
+	}
+	cancelled := journey("home", 2*time.Hour, 20*time.Minute, 0)
+	cancelled.Cancelled = true
+	samples := []JourneySample{
+		journey("home", 3*time.Hour, 20*time.Minute, 0),
+		journey("home", 2*time.Hour, 20*time.Minute, 10*time.Minute),
+		cancelled,
+		journey("home", 40*24*time.Hour, 20*time.Minute, time.Hour), // too long ago
+		journey("home", 5*time.Minute, 20*time.Minute, 0),           // still under way
+		journey("gym", time.Hour, 40*time.Minute, 0),
+	}
+
+	tests := []struct {
+		name      string
+		route     string
+		threshold int
+		want      RouteSummary
+	}{
+		{"mixed", "home", 5, RouteSummary{Route: "home", Journeys: 3, Cancelled: 1, Delayed: 1,
+			Planned: 20 * time.Minute, Realized: 25 * time.Minute}},
+		{"higher threshold", "home", 15, RouteSummary{Route: "home", Journeys: 3, Cancelled: 1,
+			Planned: 20 * time.Minute, Realized: 25 * time.Minute}},
+		{"other route", "gym", 5, RouteSummary{Route: "gym", Journeys: 1, Planned: 40 * time.Minute, Realized: 40 * time.Minute}},
+		{"never seen", "work", 5, RouteSummary{Route: "work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SummarizeRoute(samples, tt.route, now.AddDate(0, 0, -30), tt.threshold)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
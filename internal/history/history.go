@@ -0,0 +1,204 @@
+// Package history persists observed delays and derives statistics and
+// reliability scores from them.
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+const historyFile = ".commute_history.json"
+
+// Retention bounds how long samples are kept on disk
+const Retention = 90 * 24 * time.Hour
+
+// Sample is the last observed departure delay of a trip at a stop
+type Sample struct {
+	Planned time.Time `json:"planned"`
+	Line    string    `json:"line"`
+	Product string    `json:"product,omitempty"`
+	TripID  string    `json:"trip_id"`
+	Stop    string    `json:"stop,omitempty"`
+	Route   string    `json:"route,omitempty"`
+	Delay   int       `json:"delay"` // seconds
+	Seen    time.Time `json:"seen"`
+}
+
+// JourneySample is the last observed state of a journey on a route
+type JourneySample struct {
+	Route            string    `json:"route"`
+	Key              string    `json:"key"`
+	PlannedDeparture time.Time `json:"planned_departure"`
+	PlannedArrival   time.Time `json:"planned_arrival"`
+	Arrival          time.Time `json:"arrival"`
+	Cancelled        bool      `json:"cancelled,omitempty"`
+}
+
+// History is the persisted delay history shared by the TUI and daemon
+type History struct {
+	mu       sync.Mutex
+	Samples  []Sample        `json:"samples"`
+	Journeys []JourneySample `json:"journeys"`
+	index    map[string]int
+	jindex   map[string]int
+}
+
+func filePath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, historyFile)
+}
+
+func Load() *History {
+	h := &History{}
+	if data, err := os.ReadFile(filePath()); err == nil {
+		json.Unmarshal(data, h)
+	}
+	h.reindex()
+	return h
+}
+
+func (h *History) reindex() {
+	h.index = make(map[string]int, len(h.Samples))
+	for i, s := range h.Samples {
+		h.index[s.TripID+"|"+s.Stop] = i
+	}
+	h.jindex = make(map[string]int, len(h.Journeys))
+	for i, j := range h.Journeys {
+		h.jindex[j.Key] = i
+	}
+}
+
+// Record stores the delay of every leg, replacing earlier observations of
+// the same trip so each departure counts once with its latest delay
+func (h *History) Record(route string, journeys []model.Journey) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for _, j := range journeys {
+		h.recordJourney(route, j)
+
+		for _, leg := range j.Legs {
+			if leg.TripID == "" {
+				continue
+			}
+			sample := Sample{
+				Planned: leg.Departure.Add(-time.Duration(leg.DepDelay) * time.Second),
+				Line:    leg.Line,
+				Product: leg.Product,
+				TripID:  leg.TripID,
+				Stop:    leg.From,
+				Route:   route,
+				Delay:   leg.DepDelay,
+				Seen:    now,
+			}
+			key := sample.TripID + "|" + sample.Stop
+			if i, ok := h.index[key]; ok {
+				h.Samples[i] = sample
+			} else {
+				h.index[key] = len(h.Samples)
+				h.Samples = append(h.Samples, sample)
+			}
+		}
+	}
+}
+
+func (h *History) recordJourney(route string, j model.Journey) {
+	first, last := j.Legs[0], j.Legs[len(j.Legs)-1]
+	sample := JourneySample{
+		Route:            route,
+		PlannedDeparture: first.Departure.Add(-time.Duration(first.DepDelay) * time.Second),
+		PlannedArrival:   last.Arrival.Add(-time.Duration(last.ArrDelay) * time.Second),
+		Arrival:          last.Arrival,
+	}
+	var ids []string
+	for _, leg := range j.Legs {
+		ids = append(ids, leg.TripID)
+		sample.Cancelled = sample.Cancelled || leg.Cancelled
+	}
+	sample.Key = route + "|" + strings.Join(ids, "|")
+
+	if i, ok := h.jindex[sample.Key]; ok {
+		h.Journeys[i] = sample
+	} else {
+		h.jindex[sample.Key] = len(h.Journeys)
+		h.Journeys = append(h.Journeys, sample)
+	}
+}
+
+// Save prunes old samples and writes the history to disk. The daemon and
+// the TUI may run side by side, so what the other one saved meanwhile is
+// merged in first and the file is replaced in one go.
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	path := filePath()
+	if data, err := os.ReadFile(path); err == nil {
+		var saved History
+		if json.Unmarshal(data, &saved) == nil {
+			h.merge(&saved)
+		}
+	}
+
+	cutoff := time.Now().Add(-Retention)
+	kept := h.Samples[:0]
+	for _, s := range h.Samples {
+		if s.Planned.After(cutoff) {
+			kept = append(kept, s)
+		}
+	}
+	h.Samples = kept
+	keptJourneys := h.Journeys[:0]
+	for _, j := range h.Journeys {
+		if j.PlannedDeparture.After(cutoff) {
+			keptJourneys = append(keptJourneys, j)
+		}
+	}
+	h.Journeys = keptJourneys
+	h.reindex()
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), historyFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// merge adds the samples of another copy of the history, keeping the
+// later observation of a departure both have seen
+func (h *History) merge(other *History) {
+	for _, s := range other.Samples {
+		key := s.TripID + "|" + s.Stop
+		if i, ok := h.index[key]; !ok {
+			h.index[key] = len(h.Samples)
+			h.Samples = append(h.Samples, s)
+		} else if s.Seen.After(h.Samples[i].Seen) {
+			h.Samples[i] = s
+		}
+	}
+	for _, j := range other.Journeys {
+		if _, ok := h.jindex[j.Key]; !ok {
+			h.jindex[j.Key] = len(h.Journeys)
+			h.Journeys = append(h.Journeys, j)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestSaveMerges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now().Truncate(time.Minute)
+	journey := func(trip string, delay int) model.Journey {
+		return model.Journey{Legs: []model.Leg{{
+			Line: "S5", TripID: trip, From: "S+U Warschauer Str. (Berlin)",
+			Departure: now.Add(time.Duration(delay) * time.Second), DepDelay: delay, Arrival: now.Add(20 * time.Minute),
+		}}}
+	}
+
+	// The daemon and the TUI load the same file, record, and save in turn
+	daemon, tui := Load(), Load()
+	daemon.Record("home → work", []model.Journey{journey("a", 0), journey("b", 60)})
+	tui.Record("home → work", []model.Journey{journey("c", 0)})
+	if err := daemon.Save(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond) // the TUI's observation of b is the later one
+	tui.Record("home → work", []model.Journey{journey("b", 180)})
+	if err := tui.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	delays := map[string]int{}
+	for _, s := range Load().Samples {
+		delays[s.TripID] = s.Delay
+	}
+	want := map[string]int{"a": 0, "b": 180, "c": 0}
+	for trip, d := range want {
+		if got, ok := delays[trip]; !ok || got != d {
+			t.Errorf("trip %s: got delay %d (saved %v), want %d", trip, got, ok, d)
+		}
+	}
+	if len(delays) != len(want) {
+		t.Errorf("got trips %v, want %v", delays, want)
+	}
+}
+
+func TestSavePrunes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	h := Load()
+	h.Samples = []Sample{
+		{TripID: "old", Planned: now.Add(-Retention - time.Hour)},
+		{TripID: "recent", Planned: now.Add(-time.Hour)},
+	}
+	h.reindex()
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+	var trips []string
+	for _, s := range Load().Samples {
+		trips = append(trips, s.TripID)
+	}
+	if !slices.Equal(trips, []string{"recent"}) {
+		t.Errorf("got %v, want only the recent sample", trips)
+	}
+}
+
+func TestRecordJourneys(t *testing.T) {
+	dep := time.Date(2026, 10, 16, 8, 2, 0, 0, time.UTC)
+	journey := func(delay int, cancelled bool) model.Journey {
+		late := time.Duration(delay) * time.Second
+		return model.Journey{Legs: []model.Leg{
+			{Line: "S5", TripID: "1|S5", Departure: dep.Add(late), DepDelay: delay, Arrival: dep.Add(6*time.Minute + late), ArrDelay: delay},
+			{Line: "U2", TripID: "1|U2", Departure: dep.Add(12 * time.Minute), Arrival: dep.Add(29*time.Minute + late), ArrDelay: delay, Cancelled: cancelled},
+		}}
+	}
+	h := &History{}
+	h.reindex()
+	h.Record("home", []model.Journey{journey(0, false)})
+	h.Record("home", []model.Journey{journey(180, false)})
+	h.Record("gym", []model.Journey{journey(0, false)})
+
+	if len(h.Journeys) != 2 {
+		t.Fatalf("recorded %d journeys, want one per route", len(h.Journeys))
+	}
+	home := h.Journeys[0]
+	if !home.PlannedDeparture.Equal(dep) || !home.PlannedArrival.Equal(dep.Add(29*time.Minute)) || !home.Arrival.Equal(dep.Add(32*time.Minute)) {
+		t.Errorf("planned %s to %s, arrived %s; want the latest arrival, 3 min late", home.PlannedDeparture, home.PlannedArrival, home.Arrival)
+	}
+
+	h.Record("home", []model.Journey{journey(180, true)})
+	if !h.Journeys[0].Cancelled || len(h.Journeys) != 2 {
+		t.Errorf("the cancelled leg isn't on the journey: %+v", h.Journeys)
+	}
+}
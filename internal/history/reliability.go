@@ -0,0 +1,92 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// minSamplesForStats is how many recorded delays a line needs before its
+// history is trusted over the buffer-only heuristic
+const minSamplesForStats = 5
+
+// LineDelays returns each line's recorded delays in seconds, sorted
+func (h *History) LineDelays() map[string][]int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delays := make(map[string][]int)
+	for _, s := range h.Samples {
+		delays[s.Line] = append(delays[s.Line], s.Delay)
+	}
+	for _, d := range delays {
+		sort.Ints(d)
+	}
+	return delays
+}
+
+// transferProbability estimates the chance of making a connection. With
+// enough history it counts the feeder's past delays that fit into the
+// planned buffer, since the wait already has today's delay in it; otherwise
+// it goes by the wait that's left with a conservative heuristic.
+func transferProbability(feederDelays []int, planned, buffer time.Duration) float64 {
+	if len(feederDelays) >= minSamplesForStats {
+		// Share of past departures that ran no later than the buffer allows
+		limit := int(planned.Seconds())
+		n := sort.SearchInts(feederDelays, limit+1)
+		p := float64(n) / float64(len(feederDelays))
+		return max(p, 0.05)
+	}
+
+	switch mins := int(buffer.Minutes()); {
+	case mins >= 8:
+		return 0.99
+	case mins >= 5:
+		return 0.97
+	case mins >= 3:
+		return 0.9
+	case mins >= 2:
+		return 0.8
+	case mins >= 1:
+		return 0.65
+	}
+	return 0.5
+}
+
+// TransferMiss estimates the chance of missing the change into leg i. With
+// enough history on both lines it pairs every recorded delay of the feeder
+// with every one of the connecting line, since a late connection makes up
+// for a late feeder; otherwise it goes by the feeder alone.
+func TransferMiss(j model.Journey, i int, lineDelays map[string][]int) float64 {
+	if i <= 0 || i >= len(j.Legs) {
+		return 0
+	}
+	feeder, onward := lineDelays[j.Legs[i-1].Line], lineDelays[j.Legs[i].Line]
+	planned := j.PlannedWait(i)
+	if len(feeder) < minSamplesForStats || len(onward) < minSamplesForStats {
+		return 1 - transferProbability(feeder, planned, j.Legs[i].WaitBefore)
+	}
+
+	// Historic delays stand in for today's, so they go against the
+	// timetable's buffer rather than the wait that's left
+	wait := int(planned.Seconds())
+
+	// Missed when the feeder runs later than the buffer plus the connection's
+	// own delay; feeder is sorted, so count those past the limit per delay
+	missed := 0
+	for _, d := range onward {
+		missed += len(feeder) - sort.SearchInts(feeder, wait+max(d, 0)+1)
+	}
+	p := float64(missed) / float64(len(feeder)*len(onward))
+	return min(p, 0.95)
+}
+
+// JourneyReliability is the probability that all connections are made
+func JourneyReliability(j model.Journey, lineDelays map[string][]int) float64 {
+	p := 1.0
+	for i := 1; i < len(j.Legs); i++ {
+		p *= 1 - TransferMiss(j, i, lineDelays)
+	}
+	return p
+}
@@ -0,0 +1,144 @@
+package history
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestLineDelays(t *testing.T) {
+	h := &History{Samples: []Sample{
+		{Line: "S5", Delay: 120}, {Line: "U2", Delay: 0}, {Line: "S5", Delay: -30}, {Line: "S5", Delay: 60},
+	}}
+	want := map[string][]int{"S5": {-30, 60, 120}, "U2": {0}}
+	if got := h.LineDelays(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTransferProbability(t *testing.T) {
+	tests := []struct {
+		name   string
+		delays []int
+		buffer time.Duration
+		want   float64
+	}{
+		{"no history, long wait", nil, 10 * time.Minute, 0.99},
+		{"no history, five minutes", nil, 5 * time.Minute, 0.97},
+		{"no history, three minutes", nil, 3 * time.Minute, 0.9},
+		{"no history, two minutes", nil, 2 * time.Minute, 0.8},
+		{"no history, a minute", nil, time.Minute, 0.65},
+		{"no history, no wait", nil, 0, 0.5},
+		{"too little history", []int{600, 600, 600, 600}, 10 * time.Minute, 0.99},
+		{"history fits", []int{0, 0, 30, 60, 90}, 2 * time.Minute, 1},
+		{"history, some too late", []int{0, 60, 120, 121, 600}, 2 * time.Minute, 0.6},
+		{"history, never in time", []int{600, 600, 600, 600, 600}, 2 * time.Minute, 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transferProbability(tt.delays, tt.buffer, tt.buffer); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJourneyReliability(t *testing.T) {
+	legs := func(waits ...time.Duration) model.Journey {
+		j := model.Journey{Legs: []model.Leg{{Line: "S5"}}}
+		for _, w := range waits {
+			j.Legs = append(j.Legs, model.Leg{Line: "U2", WaitBefore: w})
+		}
+		return j
+	}
+	// Two minutes late into a five-minute change, three of them left
+	late := legs(3 * time.Minute)
+	late.Legs[0].ArrDelay = 120
+	punctual := map[string][]int{"S5": {0, 0, 30, 60, 60}}
+
+	tests := []struct {
+		name    string
+		j       model.Journey
+		history map[string][]int
+		want    float64
+	}{
+		{"direct", legs(), nil, 1},
+		{"one change", legs(3 * time.Minute), nil, 0.9},
+		{"two changes", legs(3*time.Minute, time.Minute), nil, 0.9 * 0.65},
+		{"late feeder without history", late, nil, 0.9},
+		// Today's delay is in the wait already; history is held against the plan
+		{"late feeder with history", late, punctual, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JourneyReliability(tt.j, tt.history); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeTransferReliability(t *testing.T) {
+	f := vbb.NewFake(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC))
+	oftenLate := []int{0, 60, 300, 420, 600}
+
+	tests := []struct {
+		name   string
+		s5     int // fixture S5, the third one runs two minutes late
+		delays map[string][]int
+		want   float64
+	}{
+		{"no history goes by the buffer", 0, nil, 0.97},
+		{"punctual feeder", 0, map[string][]int{"S5": {0, 0, 30, 60, 60}}, 1},
+		{"late feeder", 0, map[string][]int{"S5": oftenLate}, 0.6},
+		{"late today leaves less buffer", 2, nil, 0.9},
+		{"today's delay isn't counted twice", 2, map[string][]int{"S5": oftenLate}, 0.6},
+		{"never certain to miss", 0, map[string][]int{"S5": {900, 900, 900, 900, 900}}, 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := f.Transfer(tt.s5, 3*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := JourneyReliability(j, tt.delays); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("JourneyReliability = %v, want %v (planned buffer %s)", got, tt.want, j.PlannedWait(1))
+			}
+		})
+	}
+}
+
+func TestTransferMiss(t *testing.T) {
+	f := vbb.NewFake(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC))
+	punctual := []int{0, 0, 30, 60, 60}
+	oftenLate := []int{0, 60, 300, 420, 600}
+
+	tests := []struct {
+		name   string
+		s5     int // fixture S5, the third one runs two minutes late
+		delays map[string][]int
+		want   float64
+	}{
+		{"no history goes by the buffer", 0, nil, 0.03},
+		{"punctual feeder", 0, map[string][]int{"S5": punctual, "U2": {0, 0, 0, 0, 0}}, 0},
+		{"late feeder", 0, map[string][]int{"S5": oftenLate, "U2": {0, 0, 0, 0, 0}}, 0.4},
+		{"late connection makes up for it", 0, map[string][]int{"S5": oftenLate, "U2": {120, 120, 120, 120, 120}}, 0.2},
+		{"today's delay isn't counted twice", 2, map[string][]int{"S5": oftenLate, "U2": {0, 0, 0, 0, 0}}, 0.4},
+		{"capped below certain", 0, map[string][]int{"S5": {900, 900, 900, 900, 900}, "U2": {0, 0, 0, 0, 0}}, 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := f.Transfer(tt.s5, 3*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := TransferMiss(j, 1, tt.delays); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TransferMiss = %v, want %v (planned buffer %s)", got, tt.want, j.PlannedWait(1))
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// LineStats summarizes the recorded delays of one line
+type LineStats struct {
+	Line    string
+	Product string
+	Count   int
+	Mean    float64 // minutes
+	Median  float64
+	P90     float64
+	Worst   int
+	Late    int // samples delayed by at least the threshold
+}
+
+// SamplesBetween returns all samples with a planned departure in [from, to)
+func (h *History) SamplesBetween(from, to time.Time) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var out []Sample
+	for _, s := range h.Samples {
+		if !s.Planned.Before(from) && s.Planned.Before(to) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ComputeLineStats groups samples by line, sorted by mean delay descending
+func ComputeLineStats(samples []Sample, threshold int) []LineStats {
+	byLine := make(map[string][]Sample)
+	for _, s := range samples {
+		byLine[s.Line] = append(byLine[s.Line], s)
+	}
+
+	var stats []LineStats
+	for line, ss := range byLine {
+		delays := make([]int, len(ss))
+		st := LineStats{Line: line, Product: ss[0].Product, Count: len(ss)}
+		sum := 0
+		for i, s := range ss {
+			delays[i] = s.Delay
+			sum += s.Delay
+			if s.Delay > st.Worst {
+				st.Worst = s.Delay
+			}
+			if s.Delay/60 >= threshold {
+				st.Late++
+			}
+		}
+		sort.Ints(delays)
+		st.Mean = float64(sum) / float64(len(delays)) / 60
+		st.Median = percentile(delays, 0.5) / 60
+		st.P90 = percentile(delays, 0.9) / 60
+		st.Worst /= 60
+		stats = append(stats, st)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Mean == stats[j].Mean {
+			return stats[i].Line < stats[j].Line
+		}
+		return stats[i].Mean > stats[j].Mean
+	})
+	return stats
+}
+
+// percentile interpolates the p-th percentile of sorted values
+func percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return float64(sorted[lo])
+	}
+	frac := pos - float64(lo)
+	return float64(sorted[lo])*(1-frac) + float64(sorted[lo+1])*frac
+}
+
+// DailyMeans returns the mean delay in seconds of a line for each of the
+// last n days, oldest first; days without samples are -1
+func DailyMeans(samples []Sample, line string, n int, now time.Time) []int {
+	sums := make([]int, n)
+	counts := make([]int, n)
+	for _, s := range samples {
+		if s.Line != line {
+			continue
+		}
+		day := daysBetween(s.Planned.In(now.Location()), now)
+		if day < 0 || day >= n {
+			continue
+		}
+		sums[n-1-day] += s.Delay
+		counts[n-1-day]++
+	}
+
+	means := make([]int, n)
+	for i := range means {
+		means[i] = -1
+		if counts[i] > 0 {
+			means[i] = sums[i] / counts[i]
+		}
+	}
+	return means
+}
+
+// Trend is which way a line's delays are heading
+type Trend int
+
+const (
+	TrendUnknown Trend = iota // too few days, or days without samples
+	TrendFlat
+	TrendUp   // getting later
+	TrendDown // getting more punctual
+)
+
+// DelayTrend compares the mean delay of the last three days against the
+// days before
+func DelayTrend(means []int) Trend {
+	avg := func(vals []int) (float64, bool) {
+		sum, n := 0, 0
+		for _, v := range vals {
+			if v >= 0 {
+				sum += v
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, false
+		}
+		return float64(sum) / float64(n), true
+	}
+	if len(means) < 4 {
+		return TrendUnknown
+	}
+	recent, ok1 := avg(means[len(means)-3:])
+	before, ok2 := avg(means[:len(means)-3])
+	switch {
+	case !ok1 || !ok2:
+		return TrendUnknown
+	case recent > before+30:
+		return TrendUp
+	case recent < before-30:
+		return TrendDown
+	}
+	return TrendFlat
+}
+
+// daysBetween counts the calendar days from a to b by their dates, so the
+// 23- and 25-hour days of the DST switches count as one day each
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestComputeLineStats(t *testing.T) {
+	var samples []Sample
+	for _, d := range []int{0, 60, 120, 180, 600} {
+		samples = append(samples, Sample{Line: "S5", Product: "suburban", Delay: d})
+	}
+	samples = append(samples, Sample{Line: "U2", Product: "subway", Delay: 0}, Sample{Line: "U2", Product: "subway", Delay: 120})
+
+	stats := ComputeLineStats(samples, 2)
+	want := []LineStats{
+		{Line: "S5", Product: "suburban", Count: 5, Mean: 3.2, Median: 2, P90: 7.2, Worst: 10, Late: 3},
+		{Line: "U2", Product: "subway", Count: 2, Mean: 1, Median: 1, P90: 1.8, Worst: 2, Late: 1},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		got := stats[i]
+		// Rounded to the tenth of a minute shown
+		for _, f := range []*float64{&got.Mean, &got.Median, &got.P90} {
+			*f = float64(int(*f*10+0.5)) / 10
+		}
+		if got != want[i] {
+			t.Errorf("got %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func TestDailyMeans(t *testing.T) {
+	// The night the clocks go back in Berlin lies between the 24th and the 26th
+	now := time.Date(2026, 10, 26, 9, 0, 0, 0, model.DisplayZone)
+	day := func(d, hour int) time.Time { return time.Date(2026, 10, d, hour, 0, 0, 0, model.DisplayZone) }
+	samples := []Sample{
+		{Line: "S5", Planned: day(26, 8), Delay: 60},
+		{Line: "S5", Planned: day(26, 7), Delay: 180},
+		{Line: "S5", Planned: day(25, 23), Delay: 300},
+		{Line: "S5", Planned: day(24, 1), Delay: 30},
+		{Line: "S5", Planned: day(10, 8), Delay: 600}, // too long ago
+		{Line: "U2", Planned: day(26, 8), Delay: 900},
+	}
+	got := DailyMeans(samples, "S5", 4, now)
+	if want := []int{-1, 30, 300, 120}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDelayTrend(t *testing.T) {
+	tests := []struct {
+		name  string
+		means []int
+		want  Trend
+	}{
+		{"too few days", []int{0, 60, 600}, TrendUnknown},
+		{"no recent samples", []int{60, 60, 60, 60, -1, -1, -1}, TrendUnknown},
+		{"nothing before", []int{-1, -1, -1, -1, 60, 60, 60}, TrendUnknown},
+		{"steady", []int{60, 60, 60, 60, 60, 80, 70}, TrendFlat},
+		{"getting later", []int{60, 60, -1, 60, 120, 90, 120}, TrendUp},
+		{"getting better", []int{300, 240, 300, 300, 60, 0, -1}, TrendDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DelayTrend(tt.means); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplesBetween(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, model.DisplayZone)
+	h := &History{Samples: []Sample{
+		{TripID: "before", Planned: today.Add(-25 * time.Hour)},
+		{TripID: "midnight", Planned: today.AddDate(0, 0, -1)},
+		{TripID: "morning", Planned: today.Add(-16 * time.Hour)},
+		{TripID: "today", Planned: today},
+	}}
+	var trips []string
+	for _, s := range h.SamplesBetween(today.AddDate(0, 0, -1), today) {
+		trips = append(trips, s.TripID)
+	}
+	if want := []string{"midnight", "morning"}; !slices.Equal(trips, want) {
+		t.Errorf("got %v, want %v", trips, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Package hooks runs the user's own shell commands on events in the app,
+// with what happened as JSON on stdin, for automations berrrr doesn't ship.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/model"
+)
+
+// Events hooks can run on
+var Events = []string{"refresh_done", "delay_detected", "journey_pinned"}
+
+// timeout is how long a hook may run before it's killed
+const timeout = 30 * time.Second
+
+// Payload is what a hook reads on stdin
+type Payload struct {
+	Event    string          `json:"event"`
+	Route    string          `json:"route"`
+	Time     time.Time       `json:"time"`
+	Journey  *model.Journey  `json:"journey,omitempty"`  // the pinned or delayed one
+	Journeys []model.Journey `json:"journeys,omitempty"` // everything after a refresh
+	Alert    *alert.Alert    `json:"alert,omitempty"`    // what was detected
+}
+
+// Validate reports the first hook for an event that doesn't exist
+func Validate(hooks map[string][]string) error {
+	for event := range hooks {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("hook for unknown event %q (available: %v)", event, Events)
+		}
+	}
+	return nil
+}
+
+// Run runs the commands configured for the payload's event through the
+// shell, one after the other, and waits for them. BERRRR_EVENT holds the
+// event's name. Failures are only logged.
+func Run(hooks map[string][]string, p Payload) {
+	commands := hooks[p.Event]
+	if len(commands) == 0 {
+		return
+	}
+	if p.Time.IsZero() {
+		p.Time = time.Now()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		slog.Warn("encoding hook payload failed", "event", p.Event, "err", err)
+		return
+	}
+	for _, command := range commands {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		cmd := shell(ctx, command)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Env = append(os.Environ(), "BERRRR_EVENT="+p.Event)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			slog.Warn("hook failed", "event", p.Event, "command", command, "err", err, "output", string(out))
+		} else {
+			slog.Debug("hook ran", "event", p.Event, "command", command)
+		}
+		cancel()
+	}
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
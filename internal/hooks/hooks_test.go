@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks map[string][]string
+		ok    bool
+	}{
+		{"none", nil, true},
+		{"known events", map[string][]string{"refresh_done": {"true"}, "journey_pinned": {"true"}}, true},
+		{"unknown event", map[string][]string{"refresh_done": {"true"}, "journey_missed": {"true"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.hooks); (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks here are POSIX shell")
+	}
+	dir := t.TempDir()
+	stdin, env := filepath.Join(dir, "stdin"), filepath.Join(dir, "env")
+	hooks := map[string][]string{
+		"journey_pinned": {
+			"exit 1", // a failing hook doesn't keep the next from running
+			"cat > " + stdin + " && echo $BERRRR_EVENT > " + env,
+		},
+	}
+	j := model.Journey{Legs: []model.Leg{{Line: "S5"}}}
+	Run(hooks, Payload{Event: "journey_pinned", Route: "Home → Work", Journey: &j})
+
+	data, err := os.ReadFile(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p Payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Event != "journey_pinned" || p.Route != "Home → Work" || p.Time.IsZero() || p.Journey == nil || p.Journey.Legs[0].Line != "S5" {
+		t.Errorf("hook read %s", data)
+	}
+	if data, _ := os.ReadFile(env); strings.TrimSpace(string(data)) != "journey_pinned" {
+		t.Errorf("BERRRR_EVENT = %q", data)
+	}
+}
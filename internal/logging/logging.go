@@ -0,0 +1,58 @@
+// Package logging sets up the optional debug log. The TUI owns the
+// terminal, so log output only ever goes to a file, or nowhere.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Path is where --debug writes, $XDG_STATE_HOME/berrrr/log
+func Path() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "berrrr", "log")
+}
+
+// Open appends debug-level structured logs to the log file and makes it
+// the destination of slog and the standard log package
+func Open() (*os.File, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	return f, nil
+}
+
+// WriteCrash appends a panic and its stack trace to the log file, whether
+// or not --debug is on, and returns the file's path
+func WriteCrash(value any, stack []byte) (string, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s panic: %v\n\n%s\n", time.Now().Format(time.RFC3339), value, stack)
+	return path, err
+}
+
+// Discard drops all log output, so nothing stray reaches the screen
+func Discard() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		state, home string
+		want        string
+	}{
+		{"XDG state dir", "/tmp/state", "/home/me", "/tmp/state/berrrr/log"},
+		{"under home", "", "/home/me", "/home/me/.local/state/berrrr/log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", tt.state)
+			t.Setenv("HOME", tt.home)
+			if got := Path(); got != filepath.FromSlash(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	defer slog.SetDefault(slog.Default())
+
+	f, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.Debug("refreshed", "route", "Home → Work")
+	f.Close()
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("%v in %s", err, data)
+	}
+	if entry["level"] != "DEBUG" || entry["msg"] != "refreshed" || entry["route"] != "Home → Work" {
+		t.Errorf("logged %s", data)
+	}
+}
+
+func TestWriteCrash(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	for _, value := range []any{"index out of range", 42} {
+		path, err := WriteCrash(value, []byte("goroutine 1 [running]:"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != Path() {
+			t.Errorf("wrote to %s, want %s", path, Path())
+		}
+	}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"panic: index out of range\n\ngoroutine 1 [running]:\n", "panic: 42\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in\n%s", want, data)
+		}
+	}
+}
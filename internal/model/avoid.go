@@ -0,0 +1,126 @@
+package model
+
+import "strings"
+
+// Avoidance is what planning leaves out on request
+type Avoidance struct {
+	Stations []Station `json:"stations,omitempty"`
+
+	// Lines are line names like "U5"; "replacement" stands for all rail
+	// replacement buses
+	Lines []string `json:"lines,omitempty"`
+}
+
+// AvoidsStation reports whether s is on the list
+func (av Avoidance) AvoidsStation(s Station) bool {
+	for _, a := range av.Stations {
+		if a.ID == s.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleStation adds s to the list, or removes it if it is already there.
+// It reports whether s is avoided afterwards.
+func (av *Avoidance) ToggleStation(s Station) bool {
+	for i, a := range av.Stations {
+		if a.ID == s.ID {
+			// Copy, a refresh in flight may still be reading the old list
+			av.Stations = append(av.Stations[:i:i], av.Stations[i+1:]...)
+			return false
+		}
+	}
+	av.Stations = append(av.Stations, s)
+	return true
+}
+
+// AvoidsLine reports whether leg rides an excluded line
+func (av Avoidance) AvoidsLine(leg Leg) bool {
+	for _, l := range av.Lines {
+		if strings.EqualFold(l, leg.Line) || (strings.EqualFold(l, "replacement") && IsReplacement(leg)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReplacement guesses whether leg is a rail replacement bus: a bus named
+// after an S- or U-Bahn line, or marked SEV/EV
+func IsReplacement(leg Leg) bool {
+	if leg.Product != "bus" {
+		return false
+	}
+	name := strings.ToUpper(leg.Line)
+	if strings.Contains(name, "SEV") || strings.HasPrefix(name, "EV") {
+		return true
+	}
+	return len(name) > 1 && (name[0] == 'S' || name[0] == 'U') && name[1] >= '0' && name[1] <= '9'
+}
+
+// Avoids reports whether j rides an excluded line, or boards, changes at or
+// gets off at an avoided station. The route's own ends don't count:
+// avoiding them would leave nothing to show.
+func (av Avoidance) Avoids(j Journey, origin, dest Station) bool {
+	for _, leg := range j.Legs {
+		if av.AvoidsLine(leg) {
+			return true
+		}
+	}
+	for _, s := range av.Stations {
+		if s.ID == origin.ID || s.ID == dest.ID {
+			continue
+		}
+		for _, leg := range j.Legs {
+			if legTouches(leg, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Filter drops the journeys that Avoids, returning the rest and how many
+// were hidden
+func (av Avoidance) Filter(journeys []Journey, origin, dest Station) ([]Journey, int) {
+	if len(av.Stations) == 0 && len(av.Lines) == 0 {
+		return journeys, 0
+	}
+	kept := journeys[:0:0]
+	for _, j := range journeys {
+		if !av.Avoids(j, origin, dest) {
+			kept = append(kept, j)
+		}
+	}
+	return kept, len(journeys) - len(kept)
+}
+
+// legTouches matches by ID, falling back to the name for legs without IDs
+func legTouches(leg Leg, s Station) bool {
+	if leg.FromID != "" || leg.ToID != "" {
+		return leg.FromID == s.ID || leg.ToID == s.ID
+	}
+	return leg.From == s.Name || leg.To == s.Name
+}
+
+// Stations lists the distinct stations j stops at, in order
+func (j Journey) Stations() []Station {
+	var stations []Station
+	seen := make(map[string]bool)
+	add := func(id, name string) {
+		key := id
+		if key == "" {
+			key = name
+		}
+		if name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		stations = append(stations, Station{ID: id, Name: name})
+	}
+	for _, leg := range j.Legs {
+		add(leg.FromID, leg.From)
+		add(leg.ToID, leg.To)
+	}
+	return stations
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+var (
+	warschauer = Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	alex       = Station{ID: "900100003", Name: "S+U Alexanderplatz (Berlin)"}
+	hbf        = Station{ID: "900003201", Name: "S+U Berlin Hauptbahnhof"}
+	zoo        = Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+)
+
+func ride(line, product string, from, to Station) Leg {
+	return Leg{Line: line, Product: product, From: from.Name, FromID: from.ID, To: to.Name, ToID: to.ID}
+}
+
+// trip names a journey by its first leg's trip
+func trip(id string, legs ...Leg) Journey {
+	legs[0].TripID = id
+	return Journey{Legs: legs}
+}
+
+func trips(journeys []Journey) []string {
+	var ids []string
+	for _, j := range journeys {
+		ids = append(ids, j.Legs[0].TripID)
+	}
+	return ids
+}
+
+func TestAvoidStations(t *testing.T) {
+	journeys := []Journey{
+		trip("direct", ride("S5", "suburban", warschauer, zoo)),
+		trip("via alex", ride("S5", "suburban", warschauer, alex), ride("U2", "subway", alex, zoo)),
+		trip("via hbf", ride("S5", "suburban", warschauer, hbf), ride("S3", "suburban", hbf, zoo)),
+		trip("no IDs", Leg{Line: "S5", From: warschauer.Name, To: alex.Name}, Leg{Line: "U2", From: alex.Name, To: zoo.Name}),
+	}
+
+	tests := []struct {
+		name     string
+		avoid    []Station
+		from, to Station
+		kept     []string
+	}{
+		{"nothing avoided", nil, warschauer, zoo, []string{"direct", "via alex", "via hbf", "no IDs"}},
+		{"change station", []Station{alex}, warschauer, zoo, []string{"direct", "via hbf"}},
+		{"two stations", []Station{alex, hbf}, warschauer, zoo, []string{"direct"}},
+		{"the route's own end", []Station{zoo}, warschauer, zoo, []string{"direct", "via alex", "via hbf", "no IDs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, hidden := Avoidance{Stations: tt.avoid}.Filter(journeys, tt.from, tt.to)
+			if got := trips(kept); !slices.Equal(got, tt.kept) {
+				t.Errorf("kept %v, want %v", got, tt.kept)
+			}
+			if hidden != len(journeys)-len(tt.kept) {
+				t.Errorf("hidden = %d, want %d", hidden, len(journeys)-len(tt.kept))
+			}
+		})
+	}
+}
+
+func TestToggleStation(t *testing.T) {
+	var av Avoidance
+	if !av.ToggleStation(alex) || !av.ToggleStation(hbf) {
+		t.Fatal("adding a station reported it as not avoided")
+	}
+	shared := av
+	if av.ToggleStation(alex) {
+		t.Error("removing a station reported it as avoided")
+	}
+	if av.AvoidsStation(alex) || !av.AvoidsStation(hbf) {
+		t.Errorf("avoiding %v, want only Hauptbahnhof", av.Stations)
+	}
+	if !shared.AvoidsStation(alex) {
+		t.Error("removing changed a copy of the list")
+	}
+}
+
+func TestAvoidLines(t *testing.T) {
+	journeys := []Journey{
+		trip("s5", ride("S5", "suburban", warschauer, zoo)),
+		trip("u5", ride("U5", "subway", alex, hbf)),
+		trip("sev", ride("S5", "suburban", warschauer, alex), ride("S5", "bus", alex, zoo)),
+		trip("m41", ride("M41", "bus", hbf, zoo)),
+	}
+
+	tests := []struct {
+		name  string
+		lines []string
+		kept  []string
+	}{
+		{"nothing avoided", nil, []string{"s5", "u5", "sev", "m41"}},
+		{"a line", []string{"U5"}, []string{"s5", "sev", "m41"}},
+		{"any case", []string{"m41"}, []string{"s5", "u5", "sev"}},
+		{"replacement buses", []string{"replacement"}, []string{"s5", "u5", "m41"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, _ := Avoidance{Lines: tt.lines}.Filter(journeys, warschauer, zoo)
+			if got := trips(kept); !slices.Equal(got, tt.kept) {
+				t.Errorf("kept %v, want %v", got, tt.kept)
+			}
+		})
+	}
+}
+
+func TestIsReplacement(t *testing.T) {
+	tests := []struct {
+		line, product string
+		want          bool
+	}{
+		{"S5", "bus", true},
+		{"U2", "bus", true},
+		{"SEV S3", "bus", true},
+		{"EV U8", "bus", true},
+		{"S5", "suburban", false},
+		{"M41", "bus", false},
+		{"SXF1", "bus", false},
+		{"U", "bus", false},
+	}
+	for _, tt := range tests {
+		if got := IsReplacement(Leg{Line: tt.line, Product: tt.product}); got != tt.want {
+			t.Errorf("IsReplacement(%s %s) = %v, want %v", tt.product, tt.line, got, tt.want)
+		}
+	}
+}
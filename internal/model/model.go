@@ -0,0 +1,283 @@
+// Package model holds the journey types shared by the API client, the
+// TUI and the subcommands.
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Station represents a transit station
+type Station struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+
+	Location *Coordinates `json:"location,omitempty"` // set for addresses, which have no ID
+}
+
+// AddressAt is a street address or other spot to start from
+func AddressAt(name string, at Coordinates) Station {
+	return Station{Name: name, Type: "address", Location: &at}
+}
+
+// IsAddress reports whether the station is a street address rather than a
+// stop, so journeys start with a walk
+func (s Station) IsAddress() bool {
+	return s.Type == "address" && s.Location != nil
+}
+
+// Coordinates is a WGS84 position
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ParseCoordinates reads "52.5219,13.4132"
+func ParseCoordinates(s string) (Coordinates, error) {
+	lat, lon, ok := strings.Cut(s, ",")
+	if !ok {
+		return Coordinates{}, fmt.Errorf("coordinates must look like 52.5219,13.4132")
+	}
+	var c Coordinates
+	var err error
+	if c.Latitude, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return Coordinates{}, fmt.Errorf("latitude: %w", err)
+	}
+	if c.Longitude, err = strconv.ParseFloat(strings.TrimSpace(lon), 64); err != nil {
+		return Coordinates{}, fmt.Errorf("longitude: %w", err)
+	}
+	return c, nil
+}
+
+// NearbyStop is a stop found around some coordinates
+type NearbyStop struct {
+	Station
+	Distance int // meters, as the crow flies
+}
+
+// FavoriteRoute stores a saved route
+type FavoriteRoute struct {
+	Origin Station `json:"origin"`
+	Dest   Station `json:"dest"`
+
+	// Schedule is when the route is shown at startup, like "05:00-12:00"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Without and Sort are the products left out and the sort mode last
+	// used on the route, brought back whenever it's planned
+	Without []string `json:"without,omitempty"`
+	Sort    string   `json:"sort,omitempty"`
+}
+
+// Remark severities, most severe first
+var RemarkTypes = []string{"warning", "status", "hint"}
+
+// Remark is a note the API attaches to a leg: a warning, a status message
+// or a hint like "bicycle conveyance"
+type Remark struct {
+	Type    string
+	Summary string
+	Text    string
+}
+
+// Leg represents a single transit leg
+type Leg struct {
+	Line          string
+	Type          string
+	Product       string
+	Direction     string // headsign shown on the vehicle
+	From          string
+	To            string
+	Departure     time.Time
+	Arrival       time.Time
+	WaitBefore    time.Duration
+	DepDelay      int
+	ArrDelay      int
+	Occupancy     string
+	ServiceStatus []string
+	DepPlatform   string
+	ArrPlatform   string
+	Cycle         int
+	LineColor     string
+	TripID        string
+
+	PlannedDepPlatform string
+	Cancelled          bool
+
+	FromID     string
+	ToID       string
+	FromCoords Coordinates
+	ToCoords   Coordinates
+
+	Remarks       []Remark // every remark, hints included
+	Accessibility []string // elevator, escalator and step-free remarks
+	AccessOutage  bool     // one of them reports something broken
+}
+
+// Price is the cheapest fare the API quotes for a journey
+type Price struct {
+	Amount   float64
+	Currency string
+}
+
+func (p Price) String() string {
+	if p.Currency == "EUR" || p.Currency == "" {
+		return fmt.Sprintf("%.2f €", p.Amount)
+	}
+	return fmt.Sprintf("%.2f %s", p.Amount, p.Currency)
+}
+
+// Walk is the way on foot from an address to the first stop
+type Walk struct {
+	From       string
+	To         string
+	Departure  time.Time
+	Duration   time.Duration
+	Distance   int // meters
+	FromCoords Coordinates
+	ToCoords   Coordinates
+}
+
+// Journey represents a complete journey with multiple legs
+type Journey struct {
+	LeaveAt   time.Time
+	ArriveAt  time.Time
+	Duration  time.Duration
+	TotalWait time.Duration
+	Legs      []Leg
+	IsNew     bool
+
+	Reliability  float64 // probability of making all connections
+	RefreshToken string
+	Replanned    bool // spliced together by re-planning mid-journey
+
+	Walk  *Walk  // to the first stop, when starting at an address
+	Price *Price // when the provider quotes one
+
+	Walking         time.Duration // on foot in total, changes between stops included
+	WalkingDistance int           // meters on foot in total
+}
+
+// Cancelled reports whether any leg of the journey has been called off
+func (j Journey) Cancelled() bool {
+	for _, leg := range j.Legs {
+		if leg.Cancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessOutage reports whether an elevator or escalator on the way is
+// reported broken, so a step-free change may not be possible
+func (j Journey) AccessOutage() bool {
+	for _, leg := range j.Legs {
+		if leg.AccessOutage {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNightLine tells night buses and trams like N1 or N65 by their name
+func IsNightLine(line string) bool {
+	return len(line) > 1 && line[0] == 'N' && line[1] >= '0' && line[1] <= '9'
+}
+
+// Night reports whether the leg is night service: a night line, or any
+// line running in the small hours
+func (l Leg) Night() bool {
+	return IsNightLine(l.Line) || IsLateNight(l.Departure)
+}
+
+// JourneyID identifies a journey across refreshes. Delays shift the times,
+// so it prefers the API's refresh token, then the legs' trip IDs.
+func JourneyID(j Journey) string {
+	if j.RefreshToken != "" {
+		return j.RefreshToken
+	}
+	var trips []string
+	for _, leg := range j.Legs {
+		if leg.TripID != "" {
+			trips = append(trips, leg.TripID)
+		}
+	}
+	if len(trips) > 0 {
+		return strings.Join(trips, "|")
+	}
+	return fmt.Sprintf("%s-%s", j.LeaveAt.Format(time.RFC3339), j.Legs[0].Line)
+}
+
+// IsStationID reports whether s looks like a station ID rather than a name
+func IsStationID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// RouteName is the human readable label used in alerts and history
+func RouteName(origin, dest Station) string {
+	return fmt.Sprintf("%s → %s", CleanStation(origin.Name), CleanStation(dest.Name))
+}
+
+func CleanStation(name string) string {
+	re1 := regexp.MustCompile(`\s*\[.*?\]`)
+	re2 := regexp.MustCompile(`\s*\(Berlin\)`)
+	re3 := regexp.MustCompile(`^S\+U\s+`)
+	re4 := regexp.MustCompile(`^S\s+`)
+	re5 := regexp.MustCompile(`^U\s+`)
+
+	name = re1.ReplaceAllString(name, "")
+	name = re2.ReplaceAllString(name, "")
+	name = re3.ReplaceAllString(name, "")
+	name = re4.ReplaceAllString(name, "")
+	name = re5.ReplaceAllString(name, "")
+	name = strings.ReplaceAll(name, " Bhf", "")
+	if idx := strings.Index(name, "/"); idx != -1 {
+		name = name[:idx]
+	}
+	return strings.TrimSpace(name)
+}
+
+// ConnectionAtRisk reports whether the transfer into leg i is endangered
+// by delays: the feeding leg runs late and what's left of the wait is below
+// the buffer needed to change
+func ConnectionAtRisk(j Journey, i int, buffer time.Duration) bool {
+	if i <= 0 || i >= len(j.Legs) {
+		return false
+	}
+	prev := j.Legs[i-1]
+	return prev.ArrDelay > 0 && j.Legs[i].WaitBefore < buffer
+}
+
+// PlannedWait is the wait before leg i as the timetable has it, without
+// today's delays on either side of the change
+func (j Journey) PlannedWait(i int) time.Duration {
+	if i <= 0 || i >= len(j.Legs) {
+		return 0
+	}
+	delays := time.Duration(j.Legs[i-1].ArrDelay-j.Legs[i].DepDelay) * time.Second
+	return max(j.Legs[i].WaitBefore+delays, 0)
+}
+
+// Distance is the great-circle distance between two points in meters
+func Distance(a, b Coordinates) int {
+	const earthRadius = 6371000.0
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := rad(b.Latitude - a.Latitude)
+	dLon := rad(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(a.Latitude))*math.Cos(rad(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return int(2 * earthRadius * math.Asin(math.Sqrt(h)))
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsStationID(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"900100003", true},
+		{"8011160", true},
+		{"", false},
+		{"Alexanderplatz", false},
+		{"900 100 003", false},
+		{"-900100003", false},
+		{"٩٠٠", false},
+	}
+	for _, tt := range tests {
+		if got := IsStationID(tt.in); got != tt.want {
+			t.Errorf("IsStationID(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConnectionAtRisk(t *testing.T) {
+	journey := func(arrDelay int, wait time.Duration) Journey {
+		return Journey{Legs: []Leg{{Line: "S5", ArrDelay: arrDelay}, {Line: "U2", WaitBefore: wait}}}
+	}
+	tests := []struct {
+		name string
+		j    Journey
+		i    int
+		want bool
+	}{
+		{"late and tight", journey(120, time.Minute), 1, true},
+		{"late with time to spare", journey(120, 3*time.Minute), 1, false},
+		{"exactly the buffer", journey(120, 2*time.Minute), 1, false},
+		{"tight but on time", journey(0, time.Minute), 1, false},
+		{"early", journey(-60, time.Minute), 1, false},
+		{"first leg", journey(120, time.Minute), 0, false},
+		{"past the end", journey(120, time.Minute), 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConnectionAtRisk(tt.j, tt.i, 2*time.Minute); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlannedWait(t *testing.T) {
+	j := Journey{Legs: []Leg{{ArrDelay: 120}, {WaitBefore: 3 * time.Minute, DepDelay: 60}}}
+	tests := []struct {
+		i    int
+		want time.Duration
+	}{
+		{0, 0},
+		{1, 4 * time.Minute},
+		{2, 0},
+	}
+	for _, tt := range tests {
+		if got := j.PlannedWait(tt.i); got != tt.want {
+			t.Errorf("PlannedWait(%d) = %s, want %s", tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestJourneyID(t *testing.T) {
+	at := time.Date(2026, 10, 16, 8, 2, 0, 0, DisplayZone)
+	legs := []Leg{{Line: "S5", TripID: "1|S5"}, {Line: "U2", TripID: "1|U2"}}
+
+	tests := []struct {
+		name string
+		j    Journey
+		want string
+	}{
+		{"refresh token", Journey{RefreshToken: "T$A=1", LeaveAt: at, Legs: legs}, "T$A=1"},
+		{"trip IDs", Journey{LeaveAt: at, Legs: legs}, "1|S5|1|U2"},
+		{"delayed keeps the trip IDs", Journey{LeaveAt: at.Add(5 * time.Minute), Legs: legs}, "1|S5|1|U2"},
+		{"walking leg without a trip", Journey{LeaveAt: at, Legs: []Leg{{Product: "walking"}, legs[0]}}, "1|S5"},
+		{"no IDs at all", Journey{LeaveAt: at, Legs: []Leg{{Line: "S5"}}}, "2026-10-16T08:02:00+02:00-S5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JourneyID(tt.j); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Coordinates
+		ok   bool
+	}{
+		{"52.5219,13.4132", Coordinates{Latitude: 52.5219, Longitude: 13.4132}, true},
+		{" 52.5219 , 13.4132 ", Coordinates{Latitude: 52.5219, Longitude: 13.4132}, true},
+		{"-33.8688,151.2093", Coordinates{Latitude: -33.8688, Longitude: 151.2093}, true},
+		{"52.5219 13.4132", Coordinates{}, false},
+		{"north,13.4132", Coordinates{}, false},
+		{"52.5219,", Coordinates{}, false},
+		{"", Coordinates{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseCoordinates(tt.in)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"strings"
+	"time"
+	_ "time/tzdata" // Europe/Berlin must resolve even without system zoneinfo
+)
+
+// defaultTimezone is the VBB's own timezone
+const defaultTimezone = "Europe/Berlin"
+
+// DisplayZone is the timezone all times are shown in, independent of the
+// host's; config.Load sets it from the config
+var DisplayZone = mustLoadZone(defaultTimezone)
+
+func mustLoadZone(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// SetDisplayZone switches the display timezone, keeping the current one
+// if the name is unknown
+func SetDisplayZone(name string) {
+	if name == "" {
+		name = defaultTimezone
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		DisplayZone = loc
+	}
+}
+
+// ZoneLabel names the display timezone when it differs from the host's,
+// so times shown while travelling aren't mistaken for local ones
+func ZoneLabel(t time.Time) string {
+	_, local := t.Local().Zone()
+	name, display := t.In(DisplayZone).Zone()
+	if local == display {
+		return ""
+	}
+	return name
+}
+
+// timeLayout is how times of day are shown, 24-hour unless the config
+// or the locale asks for 12
+var timeLayout = "15:04"
+
+// SetTwelveHour switches times of day between "15:04" and "3:04 PM"
+func SetTwelveHour(on bool) {
+	if on {
+		timeLayout = "3:04 PM"
+	} else {
+		timeLayout = "15:04"
+	}
+}
+
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return "?"
+	}
+	return t.In(DisplayZone).Format(timeLayout)
+}
+
+// FormatClock is FormatTime with seconds, for the header clock
+func FormatClock(t time.Time) string {
+	return t.In(DisplayZone).Format(strings.Replace(timeLayout, "04", "04:05", 1))
+}
+
+// IsLateNight reports whether t falls in the small hours, between half
+// past midnight and half past four, when mostly the night network runs
+func IsLateNight(t time.Time) bool {
+	local := t.In(DisplayZone)
+	minutes := local.Hour()*60 + local.Minute()
+	return minutes >= 30 && minutes < 4*60+30
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	t.Cleanup(func() { SetTwelveHour(false) })
+	afternoon := time.Date(2026, 10, 16, 15, 4, 5, 0, DisplayZone)
+	night := time.Date(2026, 10, 16, 0, 30, 0, 0, DisplayZone)
+
+	tests := []struct {
+		name        string
+		twelve      bool
+		t           time.Time
+		time, clock string
+	}{
+		{"24-hour", false, afternoon, "15:04", "15:04:05"},
+		{"24-hour after midnight", false, night, "00:30", "00:30:00"},
+		{"12-hour", true, afternoon, "3:04 PM", "3:04:05 PM"},
+		{"12-hour after midnight", true, night, "12:30 AM", "12:30:00 AM"},
+		{"in the display zone", false, afternoon.UTC(), "15:04", "15:04:05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTwelveHour(tt.twelve)
+			if got := FormatTime(tt.t); got != tt.time {
+				t.Errorf("FormatTime = %q, want %q", got, tt.time)
+			}
+			if got := FormatClock(tt.t); got != tt.clock {
+				t.Errorf("FormatClock = %q, want %q", got, tt.clock)
+			}
+		})
+	}
+	if got := FormatTime(time.Time{}); got != "?" {
+		t.Errorf("zero time is %q", got)
+	}
+}
+
+func TestSetDisplayZone(t *testing.T) {
+	t.Cleanup(func() { SetDisplayZone("") })
+
+	tests := []struct {
+		name string
+		zone string
+		want string
+	}{
+		{"default", "", "Europe/Berlin"},
+		{"other zone", "Europe/Vienna", "Europe/Vienna"},
+		{"unknown keeps the current", "Europe/Atlantis", "Europe/Vienna"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDisplayZone(tt.zone)
+			if got := DisplayZone.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZoneLabel(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	winter := time.Date(2026, 1, 16, 8, 0, 0, 0, DisplayZone)
+	summer := time.Date(2026, 7, 16, 8, 0, 0, 0, DisplayZone)
+
+	tests := []struct {
+		name string
+		host string
+		t    time.Time
+		want string
+	}{
+		{"at home", "Europe/Berlin", summer, ""},
+		{"same offset elsewhere", "Europe/Paris", summer, ""},
+		{"travelling in summer", "America/New_York", summer, "CEST"},
+		{"travelling in winter", "America/New_York", winter, "CET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.host)
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Local = loc
+			if got := ZoneLabel(tt.t); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Departure is one entry of a stop's departure board
+type Departure struct {
+	TripID    string
+	Line      string
+	Product   string
+	Direction string
+	When      time.Time
+	Planned   time.Time
+	Delay     int
+	Platform  string
+	Cancelled bool
+}
+
+// Stopover is a trip's stop at one station
+type Stopover struct {
+	Station   Station
+	Arrival   time.Time
+	Departure time.Time
+	ArrDelay  int
+	DepDelay  int
+	Platform  string
+	Cancelled bool
+	Occupancy string // low, medium or high when the API knows
+}
+
+// Trip is a single run of a line with all its stops
+type Trip struct {
+	ID        string
+	Line      string
+	Product   string
+	Direction string
+	Stopovers []Stopover
+	Position  *Coordinates // where the vehicle is now, when the API knows
+}
+
+// PathPoint is a point on a trip's route. Points where it calls at a stop
+// carry the stop's ID.
+type PathPoint struct {
+	At     Coordinates
+	StopID string
+}
+
+// LegPath cuts a trip's path down to the part between two stops, or
+// returns nil if they aren't both on it
+func LegPath(path []PathPoint, fromID, toID string) []Coordinates {
+	start := -1
+	for i, p := range path {
+		if start < 0 {
+			if p.StopID == fromID {
+				start = i
+			}
+			continue
+		}
+		if p.StopID == toID {
+			coords := make([]Coordinates, 0, i-start+1)
+			for _, q := range path[start : i+1] {
+				coords = append(coords, q.At)
+			}
+			return coords
+		}
+	}
+	return nil
+}
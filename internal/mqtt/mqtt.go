@@ -0,0 +1,212 @@
+// Package mqtt publishes departures to an MQTT broker for home automation.
+package mqtt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// Departure is the payload published for a route's next departure
+type Departure struct {
+	Origin    string         `json:"origin"`
+	Dest      string         `json:"dest"`
+	Line      string         `json:"line"`
+	Departure time.Time      `json:"departure"`
+	Arrival   time.Time      `json:"arrival"`
+	InMinutes int            `json:"in_minutes"`
+	DelayMin  int            `json:"delay_min"`
+	Warnings  []string       `json:"warnings,omitempty"`
+	Delays    map[string]int `json:"delays"`
+	Updated   time.Time      `json:"updated"`
+}
+
+type Message struct {
+	topic   string
+	payload []byte
+}
+
+// routeSlug builds a topic-safe identifier like "koepenick-brunnenstr"
+func routeSlug(origin, dest model.Station) string {
+	slug := func(name string) string {
+		name = strings.ToLower(model.CleanStation(name))
+		name = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss").Replace(name)
+		var sb strings.Builder
+		dash := false
+		for _, r := range name {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				sb.WriteRune(r)
+				dash = false
+			} else if !dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+				dash = true
+			}
+		}
+		return strings.TrimSuffix(sb.String(), "-")
+	}
+	return slug(origin.Name) + "-" + slug(dest.Name)
+}
+
+// topicLevel makes a line name usable as one topic level: the wildcards
+// and the level separator can't appear in a published topic
+func topicLevel(name string) string {
+	return strings.NewReplacer(" ", "", "+", "_", "#", "_", "/", "_").Replace(name)
+}
+
+// BuildMessages turns a route's journeys into a summary message plus
+// one retained-friendly delay topic per line, e.g. berrrr/<route>/lines/S3/delay
+func BuildMessages(cfg config.MQTT, origin, dest model.Station, journeys []model.Journey) []Message {
+	prefix := strings.TrimSuffix(cfg.Topic, "/")
+	if prefix == "" {
+		prefix = "berrrr"
+	}
+	base := prefix + "/" + routeSlug(origin, dest)
+
+	now := time.Now()
+	payload := Departure{
+		Origin:  model.CleanStation(origin.Name),
+		Dest:    model.CleanStation(dest.Name),
+		Delays:  make(map[string]int),
+		Updated: now,
+	}
+
+	for _, j := range journeys {
+		for _, leg := range j.Legs {
+			d := leg.DepDelay / 60
+			if cur, ok := payload.Delays[leg.Line]; !ok || d > cur {
+				payload.Delays[leg.Line] = d
+			}
+		}
+	}
+
+	for _, j := range journeys {
+		if j.LeaveAt.Before(now) || j.Cancelled() {
+			continue
+		}
+		first := j.Legs[0]
+		payload.Line = first.Line
+		payload.Departure = j.LeaveAt
+		payload.Arrival = j.ArriveAt
+		payload.InMinutes = int(j.LeaveAt.Sub(now).Minutes())
+		payload.DelayMin = first.DepDelay / 60
+		for _, leg := range j.Legs {
+			payload.Warnings = append(payload.Warnings, leg.ServiceStatus...)
+		}
+		break
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	msgs := []Message{{topic: base, payload: data}}
+	for line, delay := range payload.Delays {
+		msgs = append(msgs, Message{
+			topic:   fmt.Sprintf("%s/lines/%s/delay", base, topicLevel(line)),
+			payload: []byte(fmt.Sprintf("%d", delay)),
+		})
+	}
+	return msgs
+}
+
+// Publish connects to the broker, publishes all messages with QoS 0
+// and disconnects again. Refreshes are infrequent enough that keeping a
+// connection open isn't worth the reconnect handling.
+func Publish(cfg config.MQTT, msgs []Message) error {
+	if cfg.Broker == "" || len(msgs) == 0 {
+		return nil
+	}
+
+	addr := strings.TrimPrefix(strings.TrimPrefix(cfg.Broker, "tcp://"), "mqtt://")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "1883")
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = fmt.Sprintf("berrrr-%d", time.Now().UnixNano()%100000)
+	}
+
+	// CONNECT: protocol "MQTT" level 4, clean session, 30s keepalive
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4)
+	flags := byte(0x02)
+	if cfg.Username != "" {
+		flags |= 0x80
+		if cfg.Password != "" {
+			flags |= 0x40
+		}
+	}
+	body = append(body, flags, 0, 30)
+	body = appendString(body, clientID)
+	if cfg.Username != "" {
+		body = appendString(body, cfg.Username)
+		if cfg.Password != "" {
+			body = appendString(body, cfg.Password)
+		}
+	}
+	if _, err := conn.Write(packet(0x10, body)); err != nil {
+		return err
+	}
+
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		return err
+	}
+	if ack[0] != 0x20 || ack[3] != 0 {
+		return fmt.Errorf("mqtt: connection refused (code %d)", ack[3])
+	}
+
+	for _, m := range msgs {
+		header := byte(0x30)
+		if cfg.Retain {
+			header |= 0x01
+		}
+		pub := appendString(nil, m.topic)
+		pub = append(pub, m.payload...)
+		if _, err := conn.Write(packet(header, pub)); err != nil {
+			return err
+		}
+	}
+
+	_, err = conn.Write([]byte{0xE0, 0x00})
+	return err
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// packet prefixes body with the fixed header and variable-length size
+func packet(header byte, body []byte) []byte {
+	pkt := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		pkt = append(pkt, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(pkt, body...)
+}
@@ -0,0 +1,159 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestRouteSlug(t *testing.T) {
+	tests := []struct {
+		origin, dest string
+		want         string
+	}{
+		{"S Köpenick (Berlin)", "U Brunnenstr. (Berlin)", "koepenick-brunnenstr"},
+		{"S+U Warschauer Str. (Berlin)", "S+U Zoologischer Garten (Berlin)", "warschauer-str-zoologischer-garten"},
+		{"Berlin, Straße des 17. Juni", "S+U Berlin Hauptbahnhof", "berlin-strasse-des-17-juni-berlin-hauptbahnhof"},
+	}
+	for _, tt := range tests {
+		got := routeSlug(model.Station{Name: tt.origin}, model.Station{Name: tt.dest})
+		if got != tt.want {
+			t.Errorf("routeSlug(%q, %q) = %q, want %q", tt.origin, tt.dest, got, tt.want)
+		}
+	}
+}
+
+func TestBuildMessages(t *testing.T) {
+	now := time.Now()
+	origin := model.Station{Name: "S+U Warschauer Str. (Berlin)"}
+	dest := model.Station{Name: "S+U Zoologischer Garten (Berlin)"}
+	journey := func(in time.Duration, legs ...model.Leg) model.Journey {
+		for i := range legs {
+			legs[i].Departure = now.Add(in)
+		}
+		return model.Journey{LeaveAt: now.Add(in), ArriveAt: now.Add(in + 20*time.Minute), Legs: legs}
+	}
+	journeys := []model.Journey{
+		journey(-2*time.Minute, model.Leg{Line: "S5", DepDelay: 60}),
+		journey(time.Minute, model.Leg{Line: "S5", Cancelled: true}),
+		journey(4*time.Minute+30*time.Second, model.Leg{Line: "S5", DepDelay: 240}),
+		journey(5*time.Minute, model.Leg{Line: "S5"}, model.Leg{Line: "U2", DepDelay: 120, ServiceStatus: []string{"Construction work"}}),
+		journey(8*time.Minute, model.Leg{Line: "Bus M1/N1"}),
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		topics map[string]string // to payload, the summary's checked apart
+	}{
+		{"default prefix", "", map[string]string{
+			"berrrr/warschauer-str-zoologischer-garten/lines/S5/delay":       "4",
+			"berrrr/warschauer-str-zoologischer-garten/lines/U2/delay":       "2",
+			"berrrr/warschauer-str-zoologischer-garten/lines/BusM1_N1/delay": "0",
+		}},
+		{"own prefix", "home/commute/", map[string]string{
+			"home/commute/warschauer-str-zoologischer-garten/lines/S5/delay":       "4",
+			"home/commute/warschauer-str-zoologischer-garten/lines/U2/delay":       "2",
+			"home/commute/warschauer-str-zoologischer-garten/lines/BusM1_N1/delay": "0",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := BuildMessages(config.MQTT{Topic: tt.prefix}, origin, dest, journeys)
+			if len(msgs) != len(tt.topics)+1 {
+				t.Fatalf("got %d messages, want %d", len(msgs), len(tt.topics)+1)
+			}
+
+			var summary Departure
+			if err := json.Unmarshal(msgs[0].payload, &summary); err != nil {
+				t.Fatal(err)
+			}
+			if summary.Line != "S5" || summary.InMinutes != 4 || summary.DelayMin != 4 {
+				t.Errorf("summary is %s in %d min +%d, want the S5 in 4 min +4", summary.Line, summary.InMinutes, summary.DelayMin)
+			}
+			for _, m := range msgs[1:] {
+				if strings.ContainsAny(strings.TrimPrefix(m.topic, msgs[0].topic+"/lines/"), "+# ") {
+					t.Errorf("topic %q has a wildcard or a space in the line", m.topic)
+				}
+				if want, ok := tt.topics[m.topic]; !ok || string(m.payload) != want {
+					t.Errorf("%s = %s, want %q", m.topic, m.payload, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no local listener:", err)
+	}
+	defer ln.Close()
+
+	type packet struct {
+		header byte
+		body   []byte
+	}
+	got := make(chan []packet, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			got <- nil
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var packets []packet
+		for {
+			header, err := r.ReadByte()
+			if err != nil {
+				break
+			}
+			size, err := binary.ReadUvarint(r)
+			if err != nil {
+				break
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				break
+			}
+			packets = append(packets, packet{header, body})
+			if header == 0x10 {
+				conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+			}
+			if header == 0xE0 {
+				break
+			}
+		}
+		got <- packets
+	}()
+
+	cfg := config.MQTT{Broker: "tcp://" + ln.Addr().String(), ClientID: "test", Username: "user", Password: "secret", Retain: true}
+	msgs := []Message{{topic: "berrrr/a-b", payload: []byte(`{}`)}, {topic: "berrrr/a-b/lines/S5/delay", payload: []byte("3")}}
+	if err := Publish(cfg, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	packets := <-got
+	if len(packets) != 4 {
+		t.Fatalf("got %d packets, want connect, two publishes and disconnect", len(packets))
+	}
+	if !strings.HasSuffix(string(packets[0].body), "\x00\x04test\x00\x04user\x00\x06secret") {
+		t.Errorf("connect doesn't carry the client ID and credentials: %q", packets[0].body)
+	}
+	for i, m := range msgs {
+		p := packets[i+1]
+		want := string(appendString(nil, m.topic)) + string(m.payload)
+		if p.header != 0x31 || string(p.body) != want {
+			t.Errorf("publish %d = %#x %q, want retained %q", i, p.header, p.body, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package provider holds the built-in presets for hafas-rest instances:
+// where the API lives, which products it knows and how to show them, and
+// which stations to start with.
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"go-commute/internal/model"
+)
+
+// Default is the preset used when none is configured
+const Default = "vbb"
+
+// Product is a mode of transport as the provider names it, with its icon
+// and tview color in the journey list
+type Product struct {
+	ID    string
+	Icon  string
+	Color string
+
+	// LongDistance marks intercity and express trains, which can be left
+	// out for the daily commute
+	LongDistance bool
+}
+
+// Preset describes one hafas-rest instance
+type Preset struct {
+	Name     string
+	Title    string
+	BaseURL  string
+	Timezone string
+	Products []Product
+	Home     model.Station
+	Work     model.Station
+
+	// FareZones is set for the Berlin presets, whose journeys get VBB
+	// fare zones
+	FareZones bool
+}
+
+// Product looks up a product by its ID
+func (p Preset) Product(id string) (Product, bool) {
+	for _, prod := range p.Products {
+		if prod.ID == id {
+			return prod, true
+		}
+	}
+	return Product{}, false
+}
+
+var berlinProducts = []Product{
+	{ID: "suburban", Icon: "[S]", Color: "green"},
+	{ID: "subway", Icon: "[U]", Color: "blue"},
+	{ID: "tram", Icon: "[T]", Color: "red"},
+	{ID: "bus", Icon: "[B]", Color: "purple"},
+	{ID: "ferry", Icon: "[F]", Color: "teal"},
+	{ID: "regional", Icon: "[R]", Color: "yellow"},
+	{ID: "express", Icon: "[I]", Color: "yellow", LongDistance: true},
+}
+
+var presets = map[string]Preset{
+	"vbb": {
+		Name:     "vbb",
+		Title:    "VBB (Berlin-Brandenburg)",
+		BaseURL:  "https://v6.vbb.transport.rest",
+		Timezone: "Europe/Berlin",
+		Products: berlinProducts,
+		Home:     model.Station{ID: "900180001", Name: "S Köpenick (Berlin)"},
+		Work:     model.Station{ID: "900100041", Name: "Brunnenstr./Invalidenstr. (Berlin)"},
+
+		FareZones: true,
+	},
+	"bvg": {
+		Name:     "bvg",
+		Title:    "BVG (Berlin)",
+		BaseURL:  "https://v6.bvg.transport.rest",
+		Timezone: "Europe/Berlin",
+		Products: berlinProducts,
+		Home:     model.Station{ID: "900180001", Name: "S Köpenick (Berlin)"},
+		Work:     model.Station{ID: "900100041", Name: "Brunnenstr./Invalidenstr. (Berlin)"},
+
+		FareZones: true,
+	},
+	"db": {
+		Name:     "db",
+		Title:    "Deutsche Bahn",
+		BaseURL:  "https://v6.db.transport.rest",
+		Timezone: "Europe/Berlin",
+		Products: []Product{
+			{ID: "nationalExpress", Icon: "[ICE]", Color: "white", LongDistance: true},
+			{ID: "national", Icon: "[IC]", Color: "silver", LongDistance: true},
+			{ID: "regionalExpress", Icon: "[RE]", Color: "red", LongDistance: true},
+			{ID: "regional", Icon: "[RB]", Color: "orange"},
+			{ID: "suburban", Icon: "[S]", Color: "green"},
+			{ID: "subway", Icon: "[U]", Color: "blue"},
+			{ID: "tram", Icon: "[T]", Color: "red"},
+			{ID: "bus", Icon: "[B]", Color: "purple"},
+			{ID: "ferry", Icon: "[F]", Color: "teal"},
+			{ID: "taxi", Icon: "[X]", Color: "yellow"},
+		},
+		Home: model.Station{ID: "8011160", Name: "Berlin Hbf"},
+		Work: model.Station{ID: "8000261", Name: "München Hbf"},
+	},
+}
+
+// Lookup returns the preset called name, or the default one for ""
+func Lookup(name string) (Preset, error) {
+	if name == "" {
+		name = Default
+	}
+	p, ok := presets[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown provider %q (available: %v)", name, Names())
+	}
+	return p, nil
+}
+
+// Names lists the available presets
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name, want string
+		ok         bool
+	}{
+		{"", Default, true},
+		{"vbb", "vbb", true},
+		{"db", "db", true},
+		{"VBB", "", false},
+		{"mvv", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Lookup(tt.name)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if p.Name != tt.want {
+				t.Errorf("got %q, want %q", p.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresets(t *testing.T) {
+	for _, name := range Names() {
+		p, _ := Lookup(name)
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			t.Errorf("%s: timezone %q: %v", name, p.Timezone, err)
+		}
+		if p.Home.ID == "" || p.Work.ID == "" {
+			t.Errorf("%s: no home or work station to start with", name)
+		}
+		seen := map[string]bool{}
+		for _, prod := range p.Products {
+			if seen[prod.ID] {
+				t.Errorf("%s: product %s listed twice", name, prod.ID)
+			}
+			seen[prod.ID] = true
+			if _, ok := p.Product(prod.ID); !ok {
+				t.Errorf("%s: can't look up product %s", name, prod.ID)
+			}
+		}
+	}
+}
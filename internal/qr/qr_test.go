@@ -0,0 +1,128 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int // bytes of data
+		size int // 0 when it doesn't fit
+	}{
+		{"empty", 0, 21},
+		{"fills version 1", 17, 21},
+		{"just over", 18, 25},
+		{"longer count from version 10", 250, 57},
+		{"fills version 15", 520, 77},
+		{"too long", 521, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Encode(bytes.Repeat([]byte("a"), tt.n))
+			if tt.size == 0 {
+				if err == nil {
+					t.Fatalf("encoded in size %d", q.Size)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Size != tt.size {
+				t.Errorf("size %d, want %d", q.Size, tt.size)
+			}
+
+			// The finder patterns and their separators
+			for _, c := range [][2]int{{0, 0}, {q.Size - 7, 0}, {0, q.Size - 7}} {
+				if !q.Dark(c[0], c[1]) || !q.Dark(c[0]+3, c[1]+3) || q.Dark(c[0]+1, c[1]+1) {
+					t.Errorf("no finder pattern at %v", c)
+				}
+			}
+			if q.Dark(7, 0) || q.Dark(0, 7) || q.Dark(q.Size-8, 0) {
+				t.Error("finder patterns aren't separated")
+			}
+
+			// Both copies of the format bits agree and are a valid level L word
+			var first, second int
+			for i := 0; i <= 5; i++ {
+				first |= bit(q.Dark(8, i)) << i
+			}
+			first |= bit(q.Dark(8, 7))<<6 | bit(q.Dark(8, 8))<<7 | bit(q.Dark(7, 8))<<8
+			for i := 9; i < 15; i++ {
+				first |= bit(q.Dark(14-i, 8)) << i
+			}
+			for i := 0; i < 8; i++ {
+				second |= bit(q.Dark(q.Size-1-i, 8)) << i
+			}
+			for i := 8; i < 15; i++ {
+				second |= bit(q.Dark(8, q.Size-15+i)) << i
+			}
+			if first != second {
+				t.Errorf("format bits %015b and %015b differ", first, second)
+			}
+			if !isFormatL(first) {
+				t.Errorf("format bits %015b aren't level L", first)
+			}
+			if !q.Dark(8, q.Size-8) {
+				t.Error("no dark module")
+			}
+		})
+	}
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
+
+// isFormatL is whether bits are the format information for level L with
+// one of the eight masks, as tabled in ISO/IEC 18004
+func isFormatL(bits int) bool {
+	for _, s := range []string{
+		"111011111000100", "111001011110011", "111110110101010", "111100010011101",
+		"110011000101111", "110001100011000", "110110001000001", "110100101110110",
+	} {
+		var want int
+		for _, c := range s {
+			want = want<<1 | int(c-'0')
+		}
+		if bits == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD as version 1-M, the usual worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestHalfBlocks(t *testing.T) {
+	q, err := Encode([]byte("S5 08:02 Warschauer Str. → 08:21 Zoologischer Garten"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSuffix(q.HalfBlocks(), "\n"), "\n")
+	if want := (q.Size + 4 + 1) / 2; len(rows) != want {
+		t.Errorf("%d rows, want %d", len(rows), want)
+	}
+	for i, row := range rows {
+		inner := strings.TrimSuffix(strings.TrimPrefix(row, "[black:white]"), "[-:-]")
+		if n := len([]rune(inner)); n != q.Size+4 {
+			t.Errorf("row %d is %d wide, want %d", i, n, q.Size+4)
+		}
+	}
+	if !strings.HasPrefix(rows[1], "[black:white]  █") {
+		t.Errorf("the top left finder doesn't start after the quiet zone: %q", rows[1])
+	}
+}
@@ -0,0 +1,71 @@
+package share
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// WalkingMapURL is an OpenStreetMap link routing on foot between two points
+func WalkingMapURL(from, to model.Coordinates) string {
+	return fmt.Sprintf("https://www.openstreetmap.org/directions?engine=fossgis_osrm_foot&route=%.5f%%2C%.5f%%3B%.5f%%2C%.5f",
+		from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+}
+
+// GoogleTransitURL is a Google Maps link planning the journey by public
+// transport, from where it starts to where it ends
+func GoogleTransitURL(j model.Journey) string {
+	first, last := j.Legs[0], j.Legs[len(j.Legs)-1]
+	from := place(first.From, first.FromCoords)
+	if j.Walk != nil {
+		from = place(j.Walk.From, j.Walk.FromCoords)
+	}
+	params := url.Values{}
+	params.Set("api", "1")
+	params.Set("origin", from)
+	params.Set("destination", place(last.To, last.ToCoords))
+	params.Set("travelmode", "transit")
+	return "https://www.google.com/maps/dir/?" + params.Encode()
+}
+
+// FahrinfoURL is a BVG Fahrinfo query for the journey's stops, leaving
+// when it does
+func FahrinfoURL(j model.Journey) string {
+	first, last := j.Legs[0], j.Legs[len(j.Legs)-1]
+	leave := first.Departure.Add(-time.Duration(first.DepDelay) * time.Second).In(model.DisplayZone)
+	params := url.Values{}
+	params.Set("S", first.From)
+	params.Set("Z", last.To)
+	params.Set("date", leave.Format("02.01.06"))
+	params.Set("time", leave.Format("15:04"))
+	params.Set("timesel", "depart")
+	params.Set("start", "1")
+	return "https://fahrinfo.bvg.de/Fahrinfo/bin/query.bin/dn?" + params.Encode()
+}
+
+// place is a stop's coordinates when known, which maps resolve exactly,
+// else its name
+func place(name string, at model.Coordinates) string {
+	if at == (model.Coordinates{}) {
+		return name
+	}
+	return fmt.Sprintf("%.6f,%.6f", at.Latitude, at.Longitude)
+}
+
+// OpenURL opens a link in the default browser
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,71 @@
+package share
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestGoogleTransitURL(t *testing.T) {
+	alex := model.Coordinates{Latitude: 52.521508, Longitude: 13.411267}
+	zoo := model.Coordinates{Latitude: 52.506921, Longitude: 13.332707}
+	home := model.Coordinates{Latitude: 52.5219, Longitude: 13.4132}
+	leg := func(fromCoords, toCoords model.Coordinates) model.Leg {
+		return model.Leg{From: "S+U Alexanderplatz (Berlin)", FromCoords: fromCoords, To: "S+U Zoologischer Garten (Berlin)", ToCoords: toCoords}
+	}
+
+	tests := []struct {
+		name         string
+		j            model.Journey
+		origin, dest string
+	}{
+		{"coordinates", model.Journey{Legs: []model.Leg{leg(alex, zoo)}}, "52.521508,13.411267", "52.506921,13.332707"},
+		{"names without", model.Journey{Legs: []model.Leg{leg(model.Coordinates{}, model.Coordinates{})}}, "S+U Alexanderplatz (Berlin)", "S+U Zoologischer Garten (Berlin)"},
+		{"from the address", model.Journey{Walk: &model.Walk{From: "Torstraße 1", FromCoords: home}, Legs: []model.Leg{leg(alex, zoo)}}, "52.521900,13.413200", "52.506921,13.332707"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(GoogleTransitURL(tt.j))
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := u.Query()
+			if q.Get("origin") != tt.origin || q.Get("destination") != tt.dest || q.Get("travelmode") != "transit" {
+				t.Errorf("got %s", u)
+			}
+		})
+	}
+}
+
+func TestFahrinfoURL(t *testing.T) {
+	leave := time.Date(2026, 10, 16, 23, 58, 0, 0, model.DisplayZone)
+	tests := []struct {
+		name       string
+		delay      int // seconds
+		date, time string
+	}{
+		{"on time", 0, "16.10.26", "23:58"},
+		{"late past midnight", 240, "16.10.26", "23:58"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := model.Journey{Legs: []model.Leg{
+				{From: "S+U Alexanderplatz (Berlin)", Departure: leave.Add(time.Duration(tt.delay) * time.Second).UTC(), DepDelay: tt.delay},
+				{To: "S+U Zoologischer Garten (Berlin)"},
+			}}
+			u, err := url.Parse(FahrinfoURL(j))
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := u.Query()
+			if q.Get("S") != "S+U Alexanderplatz (Berlin)" || q.Get("Z") != "S+U Zoologischer Garten (Berlin)" {
+				t.Errorf("stops %q → %q", q.Get("S"), q.Get("Z"))
+			}
+			if q.Get("date") != tt.date || q.Get("time") != tt.time {
+				t.Errorf("leaves %s %s, want %s %s", q.Get("date"), q.Get("time"), tt.date, tt.time)
+			}
+		})
+	}
+}
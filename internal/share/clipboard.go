@@ -0,0 +1,57 @@
+package share
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// CopyToClipboard puts text on the system clipboard. Over SSH, or when no
+// clipboard tool is installed, it falls back to the OSC 52 escape sequence
+// which most terminal emulators forward to the local clipboard. The
+// sequence is handed to osc, which has to get it to the terminal without
+// getting in the way of whatever else is drawing there.
+func CopyToClipboard(text string, osc func(seq string) error) (string, error) {
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		for _, tool := range clipboardTools() {
+			if _, err := exec.LookPath(tool[0]); err != nil {
+				continue
+			}
+			cmd := exec.Command(tool[0], tool[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err == nil {
+				return tool[0], nil
+			}
+		}
+	}
+
+	seq := fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	if err := osc(seq); err != nil {
+		return "", err
+	}
+	return "OSC 52", nil
+}
+
+func clipboardTools() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	tools := [][]string{
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"clip.exe"}, // WSL
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append([][]string{{"wl-copy"}}, tools...)
+	}
+	return tools
+}
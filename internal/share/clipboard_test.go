@@ -0,0 +1,66 @@
+package share
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestCopyToClipboardOSC(t *testing.T) {
+	tests := []struct {
+		name string
+		tmux string
+		want string
+	}{
+		{"terminal", "", "\x1b]52;c;UzUgMDg6MDI=\a"},
+		{"inside tmux", "/tmp/tmux-1000/default,1,0", "\x1bPtmux;\x1b\x1b]52;c;UzUgMDg6MDI=\a\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Over SSH the local clipboard tools are the wrong clipboard
+			t.Setenv("SSH_TTY", "/dev/pts/0")
+			t.Setenv("TMUX", tt.tmux)
+			var got string
+			via, err := CopyToClipboard("S5 08:02", func(seq string) error {
+				got = seq
+				return nil
+			})
+			if err != nil || via != "OSC 52" {
+				t.Fatalf("copied via %q, %v", via, err)
+			}
+			if got != tt.want {
+				t.Errorf("sequence %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	if _, err := CopyToClipboard("S5", func(string) error { return errors.New("closed") }); err == nil {
+		t.Error("no error when the sequence can't be written")
+	}
+}
+
+func TestClipboardTools(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("one tool only")
+	}
+	tests := []struct {
+		wayland string
+		first   string
+	}{
+		{"", "xclip"},
+		{"wayland-0", "wl-copy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.first, func(t *testing.T) {
+			t.Setenv("WAYLAND_DISPLAY", tt.wayland)
+			tools := clipboardTools()
+			if tools[0][0] != tt.first {
+				t.Errorf("tries %s first, want %s", tools[0][0], tt.first)
+			}
+			if last := tools[len(tools)-1][0]; last != "clip.exe" {
+				t.Errorf("tries %s last, want the WSL clip.exe", last)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package share
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// CSV renders journeys as a spreadsheet, a row each
+func CSV(journeys []model.Journey, origin, dest model.Station) string {
+	var sb strings.Builder
+	cw := csv.NewWriter(&sb)
+	cw.Write([]string{"route", "leave", "arrive", "duration_min", "transfers", "wait_min",
+		"walk_min", "lines", "delay_min", "cancelled", "price", "currency"})
+	route := model.RouteName(origin, dest)
+	for _, j := range journeys {
+		var lines []string
+		delay := 0
+		for _, leg := range j.Legs {
+			lines = append(lines, leg.Line)
+			delay = leg.ArrDelay / 60 // at the destination
+		}
+		price, currency := "", ""
+		if j.Price != nil {
+			price = strconv.FormatFloat(j.Price.Amount, 'f', 2, 64)
+			currency = j.Price.Currency
+		}
+		cw.Write([]string{
+			route, j.LeaveAt.Format(time.RFC3339), j.ArriveAt.Format(time.RFC3339),
+			strconv.Itoa(int(j.Duration.Minutes())), strconv.Itoa(max(len(j.Legs)-1, 0)),
+			strconv.Itoa(int(j.TotalWait.Minutes())), strconv.Itoa(int(j.Walking.Minutes())),
+			strings.Join(lines, " "), strconv.Itoa(delay), strconv.FormatBool(j.Cancelled()),
+			price, currency,
+		})
+	}
+	cw.Flush()
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package share
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestCSV(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, model.DisplayZone)
+	}
+	origin := model.Station{Name: "S+U Warschauer Str. (Berlin)"}
+	dest := model.Station{Name: "S+U Zoologischer Garten (Berlin)"}
+	direct := model.Journey{
+		LeaveAt: at(8, 2), ArriveAt: at(8, 23), Duration: 21 * time.Minute,
+		Legs:  []model.Leg{{Line: "S5", Departure: at(8, 2), Arrival: at(8, 23), ArrDelay: 120}},
+		Price: &model.Price{Amount: 3.8, Currency: "EUR"},
+	}
+	change := model.Journey{
+		LeaveAt: at(8, 12), ArriveAt: at(8, 41), Duration: 29 * time.Minute, TotalWait: 4 * time.Minute,
+		Walking: 3 * time.Minute,
+		Legs: []model.Leg{
+			{Line: "S5", Departure: at(8, 12), Arrival: at(8, 18)},
+			{Line: "U2", Departure: at(8, 22), Arrival: at(8, 41), Cancelled: true},
+		},
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(CSV([]model.Journey{direct, change}, origin, dest))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"route", "leave", "arrive", "duration_min", "transfers", "wait_min", "walk_min", "lines", "delay_min", "cancelled", "price", "currency"},
+		{"Warschauer Str. → Zoologischer Garten", "2026-10-16T08:02:00+02:00", "2026-10-16T08:23:00+02:00", "21", "0", "0", "0", "S5", "2", "false", "3.80", "EUR"},
+		{"Warschauer Str. → Zoologischer Garten", "2026-10-16T08:12:00+02:00", "2026-10-16T08:41:00+02:00", "29", "1", "4", "3", "S5 U2", "0", "true", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	at := time.Date(2026, 10, 16, 8, 2, 0, 0, model.DisplayZone)
+	origin := model.Station{Name: "S+U Warschauer Str. (Berlin)"}
+	dest := model.Station{Name: "S+U Zoologischer Garten (Berlin)"}
+	var journeys []model.Journey
+	for i := 0; i < 3; i++ {
+		leave := at.Add(time.Duration(i) * 10 * time.Minute)
+		journeys = append(journeys, model.Journey{
+			LeaveAt: leave, ArriveAt: leave.Add(21 * time.Minute),
+			Legs: []model.Leg{{Line: "S5", TripID: fmt.Sprintf("S5|%d", i), Departure: leave, Arrival: leave.Add(21 * time.Minute)}},
+		})
+	}
+
+	ics := Calendar(journeys, origin, dest)
+	if n := strings.Count(ics, "BEGIN:VCALENDAR"); n != 1 {
+		t.Errorf("got %d calendars, want 1", n)
+	}
+	if n := strings.Count(ics, "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("got %d events, want 3", n)
+	}
+	uids := map[string]bool{}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if strings.HasPrefix(line, "UID:") {
+			uids[line] = true
+		}
+	}
+	if len(uids) != 3 {
+		t.Errorf("got %d distinct UIDs, want 3", len(uids))
+	}
+}
@@ -0,0 +1,79 @@
+package share
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// GPX renders a journey as a GPX track for GPS apps and activity logs: a
+// track segment per leg, walking to the first stop included, and a
+// waypoint per stop to board, change or get off at. paths holds each
+// leg's shape; legs without one run straight from stop to stop.
+func GPX(j model.Journey, origin, dest model.Station, paths [][]model.Coordinates) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<gpx version="1.1" creator="berrrr" xmlns="http://www.topografix.com/GPX/1/1">` + "\n")
+	sb.WriteString(fmt.Sprintf("  <metadata><name>%s</name><time>%s</time></metadata>\n",
+		escapeXML(model.RouteName(origin, dest)), gpxTime(j.LeaveAt)))
+
+	wpt := func(name string, at model.Coordinates, t time.Time) {
+		if at == (model.Coordinates{}) {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("  <wpt lat=\"%.6f\" lon=\"%.6f\"><time>%s</time><name>%s</name></wpt>\n",
+			at.Latitude, at.Longitude, gpxTime(t), escapeXML(name)))
+	}
+	if w := j.Walk; w != nil {
+		wpt(w.From, w.FromCoords, w.Departure)
+	}
+	for i, leg := range j.Legs {
+		wpt(model.CleanStation(leg.From), leg.FromCoords, leg.Departure)
+		if i == len(j.Legs)-1 || j.Legs[i+1].From != leg.To {
+			wpt(model.CleanStation(leg.To), leg.ToCoords, leg.Arrival)
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("  <trk>\n    <name>%s</name>\n", escapeXML(model.RouteName(origin, dest))))
+	seg := func(points []model.Coordinates) {
+		var pts []model.Coordinates
+		for _, p := range points {
+			if p != (model.Coordinates{}) {
+				pts = append(pts, p)
+			}
+		}
+		if len(pts) < 2 {
+			return
+		}
+		sb.WriteString("    <trkseg>\n")
+		for _, p := range pts {
+			sb.WriteString(fmt.Sprintf("      <trkpt lat=\"%.6f\" lon=\"%.6f\"/>\n", p.Latitude, p.Longitude))
+		}
+		sb.WriteString("    </trkseg>\n")
+	}
+	if w := j.Walk; w != nil {
+		seg([]model.Coordinates{w.FromCoords, w.ToCoords})
+	}
+	for i, leg := range j.Legs {
+		if i < len(paths) && len(paths[i]) >= 2 {
+			seg(paths[i])
+		} else {
+			seg([]model.Coordinates{leg.FromCoords, leg.ToCoords})
+		}
+	}
+	sb.WriteString("  </trk>\n</gpx>\n")
+	return sb.String()
+}
+
+func gpxTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package share
+
+import (
+	"encoding/xml"
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestGPX(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, model.DisplayZone)
+	}
+	warschauer := model.Coordinates{Latitude: 52.505772, Longitude: 13.449482}
+	alex := model.Coordinates{Latitude: 52.521508, Longitude: 13.411267}
+	zoo := model.Coordinates{Latitude: 52.506921, Longitude: 13.332711}
+	home := model.Coordinates{Latitude: 52.508, Longitude: 13.455}
+	origin := model.Station{Name: "Revaler Str. 99"}
+	dest := model.Station{Name: "S+U Zoologischer Garten (Berlin)"}
+
+	s5 := model.Leg{Line: "S5", From: "S+U Warschauer Str. (Berlin)", To: "S+U Alexanderplatz (Berlin)",
+		FromCoords: warschauer, ToCoords: alex, Departure: at(8, 2), Arrival: at(8, 8)}
+	u2 := model.Leg{Line: "U2", From: "S+U Alexanderplatz (Berlin)", To: dest.Name,
+		FromCoords: alex, ToCoords: zoo, Departure: at(8, 12), Arrival: at(8, 31)}
+	walk := &model.Walk{From: origin.Name, FromCoords: home, ToCoords: warschauer, Departure: at(7, 55)}
+	s5Shape := []model.Coordinates{warschauer, {Latitude: 52.5103, Longitude: 13.4345}, alex}
+
+	tests := []struct {
+		name   string
+		j      model.Journey
+		paths  [][]model.Coordinates
+		wpts   int
+		points []int // per track segment
+	}{
+		{"straight lines", model.Journey{LeaveAt: at(8, 2), Legs: []model.Leg{s5, u2}}, nil, 3, []int{2, 2}},
+		{"with a shape", model.Journey{LeaveAt: at(8, 2), Legs: []model.Leg{s5, u2}}, [][]model.Coordinates{s5Shape}, 3, []int{3, 2}},
+		{"walk first", model.Journey{LeaveAt: at(7, 55), Walk: walk, Legs: []model.Leg{s5, u2}}, nil, 4, []int{2, 2, 2}},
+		{"leg without coordinates", model.Journey{LeaveAt: at(8, 2), Legs: []model.Leg{{Line: "S5", Departure: at(8, 2)}}}, nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc struct {
+				Wpts []struct{} `xml:"wpt"`
+				Trk  struct {
+					Segs []struct {
+						Pts []struct{} `xml:"trkpt"`
+					} `xml:"trkseg"`
+				} `xml:"trk"`
+			}
+			out := GPX(tt.j, origin, dest, tt.paths)
+			if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatalf("not XML: %v\n%s", err, out)
+			}
+			if len(doc.Wpts) != tt.wpts {
+				t.Errorf("got %d waypoints, want %d", len(doc.Wpts), tt.wpts)
+			}
+			var points []int
+			for _, seg := range doc.Trk.Segs {
+				points = append(points, len(seg.Pts))
+			}
+			if !slices.Equal(points, tt.points) {
+				t.Errorf("got segments of %v points, want %v", points, tt.points)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package share
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+const icsTimeFormat = "20060102T150405"
+
+// ICS renders a journey as an iCalendar document with one event,
+// an alarm before departure and one before every transfer
+func ICS(j model.Journey, origin, dest model.Station) string {
+	return Calendar([]model.Journey{j}, origin, dest)
+}
+
+// Calendar renders journeys as one iCalendar document, an event each
+func Calendar(journeys []model.Journey, origin, dest model.Station) string {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		loc = nil
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//berrrr//Berlin route finder//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	if loc != nil {
+		lines = append(lines,
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Berlin",
+			"BEGIN:DAYLIGHT",
+			"TZOFFSETFROM:+0100",
+			"TZOFFSETTO:+0200",
+			"TZNAME:CEST",
+			"DTSTART:19700329T020000",
+			"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+			"END:DAYLIGHT",
+			"BEGIN:STANDARD",
+			"TZOFFSETFROM:+0200",
+			"TZOFFSETTO:+0100",
+			"TZNAME:CET",
+			"DTSTART:19701025T030000",
+			"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+			"END:STANDARD",
+			"END:VTIMEZONE",
+		)
+	}
+	for _, j := range journeys {
+		lines = append(lines, icsEvent(j, origin, dest, loc)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(icsFold(line))
+		sb.WriteString("\r\n")
+	}
+	return sb.String()
+}
+
+// icsEvent is a journey's VEVENT with its alarms, in loc's time or UTC
+func icsEvent(j model.Journey, origin, dest model.Station, loc *time.Location) []string {
+	stamp := func(prop string, t time.Time) string {
+		if loc == nil {
+			return fmt.Sprintf("%s:%sZ", prop, t.UTC().Format(icsTimeFormat))
+		}
+		return fmt.Sprintf("%s;TZID=Europe/Berlin:%s", prop, t.In(loc).Format(icsTimeFormat))
+	}
+
+	var desc strings.Builder
+	for i, leg := range j.Legs {
+		if leg.WaitBefore > 0 {
+			desc.WriteString(fmt.Sprintf("Change, %d min\n", int(leg.WaitBefore.Minutes())))
+		}
+		desc.WriteString(fmt.Sprintf("%s %s %s → %s %s", leg.Line,
+			model.FormatTime(leg.Departure), model.CleanStation(leg.From),
+			model.FormatTime(leg.Arrival), model.CleanStation(leg.To)))
+		if leg.DepPlatform != "" {
+			desc.WriteString(fmt.Sprintf(" (Plt %s)", leg.DepPlatform))
+		}
+		if i < len(j.Legs)-1 {
+			desc.WriteString("\n")
+		}
+	}
+
+	var ids []string
+	for _, leg := range j.Legs {
+		ids = append(ids, leg.TripID)
+	}
+	uid := fmt.Sprintf("%x@berrrr", sha1.Sum([]byte(strings.Join(ids, "|")+j.LeaveAt.String())))
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + time.Now().UTC().Format(icsTimeFormat) + "Z",
+		stamp("DTSTART", j.LeaveAt),
+		stamp("DTEND", j.ArriveAt),
+		"SUMMARY:" + icsEscape(fmt.Sprintf("%s → %s", model.CleanStation(origin.Name), model.CleanStation(dest.Name))),
+		"LOCATION:" + icsEscape(origin.Name),
+		"DESCRIPTION:" + icsEscape(desc.String()),
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:" + icsEscape(fmt.Sprintf("Leave now for %s %s", j.Legs[0].Line, model.FormatTime(j.LeaveAt))),
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+	}
+	for _, leg := range j.Legs[1:] {
+		lines = append(lines,
+			"BEGIN:VALARM",
+			"ACTION:DISPLAY",
+			"DESCRIPTION:"+icsEscape(fmt.Sprintf("Change to %s at %s", leg.Line, model.CleanStation(leg.From))),
+			"TRIGGER;VALUE=DATE-TIME:"+leg.Departure.Add(-2*time.Minute).UTC().Format(icsTimeFormat)+"Z",
+			"END:VALARM",
+		)
+	}
+	return append(lines, "END:VEVENT")
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsFold wraps content lines at 75 octets without splitting UTF-8 runes
+func icsFold(line string) string {
+	var sb strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > 75 {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += n
+	}
+	return sb.String()
+}
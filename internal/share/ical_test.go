@@ -0,0 +1,96 @@
+package share
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestICS(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, model.DisplayZone)
+	}
+	origin := model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	dest := model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+	j := model.Journey{
+		LeaveAt:  at(8, 2),
+		ArriveAt: at(8, 31),
+		Legs: []model.Leg{
+			{Line: "S5", TripID: "1|S5", From: origin.Name, To: "S+U Alexanderplatz (Berlin)", DepPlatform: "1",
+				Departure: at(8, 2), Arrival: at(8, 8)},
+			{Line: "U2", TripID: "1|U2", From: "S+U Alexanderplatz (Berlin)", To: dest.Name,
+				Departure: at(8, 12), Arrival: at(8, 31), WaitBefore: 4 * time.Minute},
+		},
+	}
+
+	ics := ICS(j, origin, dest)
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Berlin:20261016T080200\r\n",
+		"DTEND;TZID=Europe/Berlin:20261016T083100\r\n",
+		"SUMMARY:Warschauer Str. → Zoologischer Garten\r\n",
+		`DESCRIPTION:S5 08:02 Warschauer Str. → 08:08 Alexanderplatz (Plt 1)\nChange\, 4 min\nU2 08:12 Alexanderplatz → 08:31 Zoologischer Garten` + "\r\n",
+		"DESCRIPTION:Leave now for S5 08:02\r\n",
+		"DESCRIPTION:Change to U2 at Alexanderplatz\r\n",
+		"TRIGGER;VALUE=DATE-TIME:20261016T061000Z\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("missing %q in\n%s", want, unfolded)
+		}
+	}
+	if n := strings.Count(ics, "BEGIN:VALARM"); n != 2 {
+		t.Errorf("got %d alarms, want one to leave and one to change", n)
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines int
+	}{
+		{"short", "SUMMARY:Alexanderplatz", 1},
+		{"exactly 75", "DESCRIPTION:" + strings.Repeat("a", 63), 1},
+		{"76", "DESCRIPTION:" + strings.Repeat("a", 64), 2},
+		{"multibyte on the edge", "DESCRIPTION:" + strings.Repeat("a", 62) + "→→", 2},
+		{"long", "DESCRIPTION:" + strings.Repeat("ü", 100), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := strings.Split(icsFold(tt.line), "\r\n")
+			if len(folded) != tt.lines {
+				t.Errorf("got %d lines, want %d", len(folded), tt.lines)
+			}
+			for _, l := range folded {
+				if len(l) > 75 {
+					t.Errorf("line of %d octets", len(l))
+				}
+			}
+			if got := strings.ReplaceAll(icsFold(tt.line), "\r\n ", ""); got != tt.line {
+				t.Errorf("unfolds to %q", got)
+			}
+		})
+	}
+}
+
+func TestICSEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Alexanderplatz", "Alexanderplatz"},
+		{"Change, 4 min", `Change\, 4 min`},
+		{"S5; U2", `S5\; U2`},
+		{"one\ntwo", `one\ntwo`},
+		{`C:\tmp`, `C:\\tmp`},
+	}
+	for _, tt := range tests {
+		if got := icsEscape(tt.in); got != tt.want {
+			t.Errorf("icsEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
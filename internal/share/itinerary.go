@@ -0,0 +1,34 @@
+// Package share turns journeys into calendar files, plain-text itineraries
+// and clipboard contents.
+package share
+
+import (
+	"fmt"
+	"strings"
+
+	"go-commute/internal/model"
+)
+
+// Itinerary formats a journey as plain text suitable for chats,
+// QR codes and the terminal scrollback
+func Itinerary(j model.Journey, origin, dest model.Station) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s → %s, %s\n",
+		model.CleanStation(origin.Name), model.CleanStation(dest.Name), j.LeaveAt.In(model.DisplayZone).Format("Mon 02.01.")))
+
+	for _, leg := range j.Legs {
+		if leg.WaitBefore > 0 {
+			sb.WriteString(fmt.Sprintf("  change, %d min\n", int(leg.WaitBefore.Minutes())))
+		}
+		from := model.CleanStation(leg.From)
+		if leg.DepPlatform != "" {
+			from += fmt.Sprintf(" (Plt %s)", leg.DepPlatform)
+		}
+		sb.WriteString(fmt.Sprintf("%s %s %s → %s %s\n",
+			model.FormatTime(leg.Departure), leg.Line, from,
+			model.FormatTime(leg.Arrival), model.CleanStation(leg.To)))
+	}
+
+	sb.WriteString(fmt.Sprintf("Arrive %s (%d min)", model.FormatTime(j.ArriveAt), int(j.Duration.Minutes())))
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestItinerary(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, model.DisplayZone)
+	}
+	origin := model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	dest := model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+	s5 := model.Leg{Line: "S5", From: origin.Name, To: "S+U Alexanderplatz (Berlin)", DepPlatform: "1",
+		Departure: at(8, 2), Arrival: at(8, 8)}
+	u2 := model.Leg{Line: "U2", From: "S+U Alexanderplatz (Berlin)", To: dest.Name,
+		Departure: at(8, 12), Arrival: at(8, 31), WaitBefore: 4 * time.Minute}
+
+	tests := []struct {
+		name string
+		legs []model.Leg
+		want string
+	}{
+		{"direct", []model.Leg{s5}, "Warschauer Str. → Zoologischer Garten, Fri 16.10.\n" +
+			"08:02 S5 Warschauer Str. (Plt 1) → 08:08 Alexanderplatz\n" +
+			"Arrive 08:31 (29 min)"},
+		{"with a change", []model.Leg{s5, u2}, "Warschauer Str. → Zoologischer Garten, Fri 16.10.\n" +
+			"08:02 S5 Warschauer Str. (Plt 1) → 08:08 Alexanderplatz\n" +
+			"  change, 4 min\n" +
+			"08:12 U2 Alexanderplatz → 08:31 Zoologischer Garten\n" +
+			"Arrive 08:31 (29 min)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := model.Journey{LeaveAt: at(8, 2), ArriveAt: at(8, 31), Duration: 29 * time.Minute, Legs: tt.legs}
+			if got := Itinerary(j, origin, dest); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package statusline fills user-defined Go templates with the state of a
+// route, for the TUI header and for status bars calling `berrrr check`.
+package statusline
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// Fields are what a template can use, like {{.Line}} in {{.In}} min
+type Fields struct {
+	Origin   string // origin station, by its alias if it has one
+	Dest     string // destination station, likewise
+	Line     string // line of the next journey's first leg
+	Next     string // when the next journey leaves, "15:04"
+	In       int    // minutes until then
+	Delay    int    // minutes the first leg runs late
+	Platform string // where it leaves from
+	Arrive   string // when the next journey arrives, "15:04"
+	Status   string // check's verdict, empty in the TUI
+}
+
+// Collect fills the fields from the first journey that hasn't left yet,
+// origin and dest being the names to show
+func Collect(origin, dest string, journeys []model.Journey, now time.Time) Fields {
+	f := Fields{Origin: origin, Dest: dest}
+	for _, j := range journeys {
+		if j.LeaveAt.Before(now) || j.Cancelled() || len(j.Legs) == 0 {
+			continue
+		}
+		leg := j.Legs[0]
+		f.Line = leg.Line
+		f.Next = model.FormatTime(j.LeaveAt)
+		f.In = int(j.LeaveAt.Sub(now).Minutes())
+		f.Delay = leg.DepDelay / 60
+		f.Platform = leg.DepPlatform
+		f.Arrive = model.FormatTime(j.ArriveAt)
+		break
+	}
+	return f
+}
+
+// Parse checks a template once, so mistakes show up at startup: a dry
+// run against empty fields catches misspelled field names too
+func Parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, Fields{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Render executes a parsed template, showing the error in its place if
+// it fails
+func Render(tmpl *template.Template, f Fields) string {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, f); err != nil {
+		return err.Error()
+	}
+	return sb.String()
+}
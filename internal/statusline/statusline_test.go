@@ -0,0 +1,62 @@
+package statusline
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestCollect(t *testing.T) {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	journeys, err := vbb.NewFake(start).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want Fields
+	}{
+		{"first one", start, Fields{Origin: "home", Dest: "work", Line: "S5", Next: "08:02", In: 2, Platform: "1", Arrive: "08:21"}},
+		{"skips the gone and the cancelled", start.Add(33 * time.Minute), Fields{Origin: "home", Dest: "work", Line: "S5", Next: "08:54", In: 21, Delay: 2, Platform: "1", Arrive: "09:13"}},
+		{"nothing left", start.Add(3 * time.Hour), Fields{Origin: "home", Dest: "work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Collect("home", "work", journeys, tt.now); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	f := Fields{Origin: "home", Dest: "work", Line: "S5", Next: "08:02", In: 2, Delay: 3}
+
+	tests := []struct {
+		text string
+		want string // empty when it doesn't parse
+	}{
+		{"{{.Line}} in {{.In}} min", "S5 in 2 min"},
+		{"{{.Origin}} → {{.Dest}}{{if .Delay}} +{{.Delay}}{{end}}", "home → work +3"},
+		{"{{.Line", ""},
+		{"{{.Lines}}", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			tmpl, err := Parse("test", tt.text)
+			if (err == nil) != (tt.want != "") {
+				t.Fatalf("err = %v, want it to parse: %v", err, tt.want != "")
+			}
+			if err != nil {
+				return
+			}
+			if got := Render(tmpl, f); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
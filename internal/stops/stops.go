@@ -0,0 +1,227 @@
+// Package stops keeps a local copy of the provider's stop list so station
+// search can match while typing, without a request per keystroke, and offline.
+package stops
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-commute/internal/model"
+)
+
+// MaxAge is how long a downloaded stop list is trusted before fetching it again
+const MaxAge = 30 * 24 * time.Hour
+
+// Cache is the stop list of one provider. Besides the full download it
+// learns every stop the API's search returns.
+type Cache struct {
+	mu      sync.RWMutex
+	Stops   []model.Station `json:"stops"`
+	Fetched time.Time       `json:"fetched"` // last full download
+
+	path  string
+	index map[string]int
+	names []string // normalized names, parallel to Stops
+}
+
+// Path is where a provider's stop list is cached,
+// $XDG_CACHE_HOME/berrrr/stops-<provider>.json
+func Path(provider string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "berrrr", "stops-"+provider+".json")
+}
+
+// Load reads a provider's cached stop list, empty if there is none yet
+func Load(provider string) *Cache {
+	c := &Cache{path: Path(provider)}
+	if data, err := os.ReadFile(c.path); err == nil {
+		json.Unmarshal(data, c)
+	}
+	c.reindex()
+	return c
+}
+
+func (c *Cache) reindex() {
+	c.index = make(map[string]int, len(c.Stops))
+	c.names = make([]string, len(c.Stops))
+	for i, s := range c.Stops {
+		c.index[s.ID] = i
+		c.names[i] = normalize(s.Name)
+	}
+}
+
+// Save writes the stop list back to disk
+func (c *Cache) Save() error {
+	c.mu.RLock()
+	data, err := json.Marshal(c)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// Stale reports whether the full list should be downloaded (again)
+func (c *Cache) Stale() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.Fetched) > MaxAge
+}
+
+// Replace swaps in a freshly downloaded stop list, keeping learned stops
+// the download doesn't have
+func (c *Cache) Replace(stations []model.Station) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen := make(map[string]bool, len(stations))
+	for _, s := range stations {
+		seen[s.ID] = true
+	}
+	for _, s := range c.Stops {
+		if !seen[s.ID] {
+			stations = append(stations, s)
+		}
+	}
+	c.Stops = stations
+	c.Fetched = time.Now()
+	c.reindex()
+}
+
+// Learn adds stops found through the API, reporting whether any were new
+func (c *Cache) Learn(stations []model.Station) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	added := false
+	for _, s := range stations {
+		if s.ID == "" {
+			continue
+		}
+		if i, ok := c.index[s.ID]; ok {
+			c.Stops[i] = s
+			c.names[i] = normalize(s.Name)
+			continue
+		}
+		c.index[s.ID] = len(c.Stops)
+		c.Stops = append(c.Stops, s)
+		c.names = append(c.names, normalize(s.Name))
+		added = true
+	}
+	return added
+}
+
+// Search fuzzy-matches a query against the stop names, best first. Every
+// character of the query has to appear in order; word starts, runs and
+// short names rank higher.
+func (c *Cache) Search(query string, limit int) []model.Station {
+	q := normalize(query)
+	if q == "" {
+		return nil
+	}
+
+	type hit struct {
+		i     int
+		score int
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var hits []hit
+	for i, name := range c.names {
+		if score, ok := match(q, name); ok {
+			hits = append(hits, hit{i, score})
+		}
+	}
+	sort.SliceStable(hits, func(x, y int) bool {
+		if hits[x].score != hits[y].score {
+			return hits[x].score > hits[y].score
+		}
+		return len(c.names[hits[x].i]) < len(c.names[hits[y].i])
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	stations := make([]model.Station, len(hits))
+	for k, h := range hits {
+		stations[k] = c.Stops[h.i]
+	}
+	return stations
+}
+
+// Score fuzzy-matches a query against any label the way Search matches
+// stop names, higher is better
+func Score(query, label string) (int, bool) {
+	q := normalize(query)
+	if q == "" {
+		return 0, true
+	}
+	return match(q, normalize(label))
+}
+
+// match scores q as a subsequence of name
+func match(q, name string) (int, bool) {
+	if strings.HasPrefix(name, q) {
+		return 1000 - len(name), true
+	}
+	if strings.Contains(name, " "+q) {
+		return 800 - len(name), true
+	}
+	if strings.Contains(name, q) {
+		return 600 - len(name), true
+	}
+
+	score, run := 0, 0
+	qi := 0
+	for ni := 0; ni < len(name) && qi < len(q); ni++ {
+		if name[ni] != q[qi] {
+			run = 0
+			continue
+		}
+		run++
+		score += run
+		if ni == 0 || name[ni-1] == ' ' {
+			score += 5
+		}
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Scattered matches never beat a substring
+	return min(score-len(name)/4, 599), true
+}
+
+// normalize lowercases, spells out umlauts and drops the punctuation,
+// prefix and city suffix that make "S+U Alexanderplatz (Berlin)" hard to type
+func normalize(s string) string {
+	s = strings.NewReplacer(
+		"(berlin)", "", "s+u ", "",
+		"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+		"str.", "strasse",
+	).Replace(strings.ToLower(s))
+
+	var sb strings.Builder
+	space := true
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+			space = false
+		case !space:
+			sb.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
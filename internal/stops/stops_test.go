@@ -0,0 +1,115 @@
+package stops
+
+import (
+	"slices"
+	"testing"
+
+	"go-commute/internal/model"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"S+U Alexanderplatz (Berlin)", "alexanderplatz"},
+		{"S+U Warschauer Str. (Berlin)", "warschauer strasse"},
+		{"U Mehringdamm (Berlin)", "u mehringdamm"},
+		{"S Köpenick (Berlin)", "s koepenick"},
+		{"Brunnenstr./Invalidenstr. (Berlin)", "brunnenstrasse invalidenstrasse"},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.in); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	c := &Cache{}
+	c.reindex()
+	c.Replace([]model.Station{
+		{ID: "1", Name: "S+U Alexanderplatz (Berlin)"},
+		{ID: "2", Name: "U Alexanderplatz [Tram] (Berlin)"},
+		{ID: "3", Name: "S+U Warschauer Str. (Berlin)"},
+		{ID: "4", Name: "Warschauer Str./Revaler Str. (Berlin)"},
+		{ID: "5", Name: "S Köpenick (Berlin)"},
+		{ID: "6", Name: "S+U Zoologischer Garten (Berlin)"},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"alex", []string{"1", "2"}},
+		{"Warschauer Str", []string{"3", "4"}},
+		{"revaler", []string{"4"}},
+		{"koepenick", []string{"5"}},
+		{"köp", []string{"5"}},
+		{"zoo garten", []string{"6"}},
+		{"zg", []string{"6"}},
+		{"xyz", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, s := range c.Search(tt.query, 5) {
+				got = append(got, s.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := c.Search("s", 2); len(got) != 2 {
+		t.Errorf("got %d results past the limit of 2", len(got))
+	}
+}
+
+func TestLearnAndReplace(t *testing.T) {
+	c := &Cache{}
+	c.reindex()
+	c.Replace([]model.Station{{ID: "1", Name: "S+U Alexanderplatz (Berlin)"}})
+	if c.Learn([]model.Station{{ID: "1", Name: "S+U Alexanderplatz Bhf (Berlin)"}, {Name: "Revaler Str. 99"}}) {
+		t.Error("learning a known stop and an address reported something new")
+	}
+	if !c.Learn([]model.Station{{ID: "7", Name: "S Ostkreuz (Berlin)"}}) {
+		t.Error("learning a new stop reported nothing new")
+	}
+	// A download without the learned stop keeps it
+	c.Replace([]model.Station{{ID: "1", Name: "S+U Alexanderplatz (Berlin)"}, {ID: "3", Name: "S+U Warschauer Str. (Berlin)"}})
+	if got := c.Search("ostkreuz", 5); len(got) != 1 || got[0].ID != "7" {
+		t.Errorf("learned stop lost: %v", got)
+	}
+	if c.Stale() {
+		t.Error("stale right after a download")
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		query, label string
+		ok           bool
+	}{
+		{"", "anything", true},
+		{"home", "Home → Work", true},
+		{"hw", "Home → Work", true},
+		{"transf", "transfers N", true},
+		{"wh", "Home → Work", false},
+	}
+	for _, tt := range tests {
+		if _, ok := Score(tt.query, tt.label); ok != tt.ok {
+			t.Errorf("Score(%q, %q) matched %v, want %v", tt.query, tt.label, ok, tt.ok)
+		}
+	}
+
+	// Better matches score higher
+	ranked := []string{"Alexanderplatz", "U Alexanderplatz", "Hackescher Markt, Alexanderplatz side", "Anhalter Bahnhof, Lehrter Str., Xanten"}
+	prev := 1 << 30
+	for _, label := range ranked {
+		score, ok := Score("alex", label)
+		if !ok || score >= prev {
+			t.Errorf("Score(alex, %q) = %d, %v, want below %d", label, score, ok, prev)
+		}
+		prev = score
+	}
+}
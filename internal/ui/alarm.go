@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/hooks"
+	"go-commute/internal/model"
+)
+
+// togglePin pins the selected journey so alarms follow it across refreshes
+func (a *App) togglePin() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	id := model.JourneyID(a.journeys[a.selectedIdx])
+	if a.pinnedID == id {
+		a.pinnedID = ""
+		a.statusMsg = "Unpinned journey"
+	} else {
+		a.pinnedID = id
+		a.statusMsg = "Pinned journey"
+		if hookCfg := a.config.Hooks; len(hookCfg) > 0 {
+			j := a.journeys[a.selectedIdx]
+			payload := hooks.Payload{
+				Event:   "journey_pinned",
+				Route:   model.RouteName(a.config.LastOrigin, a.config.LastDest),
+				Journey: &j,
+			}
+			a.goSafe(func() { hooks.Run(hookCfg, payload) })
+		}
+	}
+	a.statusMsgFrame = 30
+}
+
+// trackedJourney returns the pinned journey, or the selected one when
+// nothing is pinned
+func (a *App) trackedJourney() (model.Journey, bool) {
+	if a.pinnedID != "" {
+		for _, j := range a.journeys {
+			if model.JourneyID(j) == a.pinnedID {
+				return j, true
+			}
+		}
+		return model.Journey{}, false
+	}
+	if a.selectedIdx < len(a.journeys) {
+		return a.journeys[a.selectedIdx], true
+	}
+	return model.Journey{}, false
+}
+
+// checkLeaveAlarm fires once per journey when its departure comes within
+// the configured threshold: the header flashes, the bell rings and an
+// optional desktop notification goes out
+func (a *App) checkLeaveAlarm() {
+	minutes := a.config.LeaveAlarm.Minutes
+	if minutes <= 0 {
+		return
+	}
+	j, ok := a.trackedJourney()
+	if !ok {
+		return
+	}
+
+	until := time.Until(j.LeaveAt)
+	id := model.JourneyID(j)
+	if until <= 0 || until > time.Duration(minutes)*time.Minute || a.alarmFiredFor == id {
+		return
+	}
+
+	a.alarmFiredFor = id
+	a.alarmFrame = 100 // flash for ~10 seconds
+	a.ring("leave")
+
+	first := j.Legs[0]
+	msg := fmt.Sprintf("%s %s from %s leaves in %d min", first.Line, model.FormatTime(j.LeaveAt),
+		model.CleanStation(first.From), int(until.Minutes()))
+	a.statusMsg = "⏰ Time to leave! " + msg
+	a.statusMsgFrame = 100
+	if a.config.LeaveAlarm.Desktop && !a.config.Notify.QuietHours.Active(time.Now()) {
+		a.goSafe(func() { alert.SendDesktop("Time to leave", msg) })
+	}
+}
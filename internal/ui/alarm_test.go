@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestCheckLeaveAlarm(t *testing.T) {
+	journey := func(trip string, in time.Duration) model.Journey {
+		leave := time.Now().Add(in)
+		return model.Journey{LeaveAt: leave, Legs: []model.Leg{{
+			Line: "S5", TripID: trip, From: "S+U Warschauer Str. (Berlin)", Departure: leave,
+		}}}
+	}
+	journeys := []model.Journey{journey("1|S5", 3*time.Minute+30*time.Second), journey("2|S5", 13*time.Minute+30*time.Second)}
+
+	tests := []struct {
+		name    string
+		minutes int
+		pinned  int // index of the pinned journey, -1 for none
+		leaveIn time.Duration
+		want    string // the alarm's status, empty for none
+	}{
+		{"off", 0, -1, 0, ""},
+		{"selected within", 5, -1, 0, "⏰ Time to leave! S5 " + model.FormatTime(journeys[0].LeaveAt) + " from Warschauer Str. leaves in 3 min"},
+		{"selected too early", 3, -1, 0, ""},
+		{"pinned over the selection", 15, 1, 0, "leaves in 13 min"},
+		{"already gone", 5, -1, -5 * time.Minute, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{journeys: append([]model.Journey(nil), journeys...)}
+			a.journeys[0].LeaveAt = a.journeys[0].LeaveAt.Add(tt.leaveIn)
+			a.config.LeaveAlarm.Minutes = tt.minutes
+			if tt.pinned >= 0 {
+				a.pinnedID = model.JourneyID(a.journeys[tt.pinned])
+			}
+
+			a.checkLeaveAlarm()
+			if tt.want == "" {
+				if a.alarmFiredFor != "" {
+					t.Errorf("fired: %s", a.statusMsg)
+				}
+				return
+			}
+			if !strings.Contains(a.statusMsg, tt.want) || a.alarmFrame == 0 {
+				t.Errorf("status %q, want %q", a.statusMsg, tt.want)
+			}
+
+			// Once per journey
+			a.statusMsg, a.alarmFrame = "", 0
+			a.checkLeaveAlarm()
+			if a.statusMsg != "" || a.alarmFrame != 0 {
+				t.Error("fired twice")
+			}
+		})
+	}
+}
+
+func TestTogglePin(t *testing.T) {
+	now := time.Now()
+	journeys := []model.Journey{
+		{LeaveAt: now, Legs: []model.Leg{{Line: "S5", TripID: "1|S5"}}},
+		{LeaveAt: now.Add(10 * time.Minute), Legs: []model.Leg{{Line: "S5", TripID: "2|S5"}}},
+	}
+	a := &App{journeys: journeys, selectedIdx: 1}
+	a.togglePin()
+	if a.pinnedID != model.JourneyID(journeys[1]) {
+		t.Fatalf("pinned %q", a.pinnedID)
+	}
+
+	// The pin follows the journey, not the selection
+	a.selectedIdx = 0
+	if j, ok := a.trackedJourney(); !ok || model.JourneyID(j) != a.pinnedID {
+		t.Errorf("tracks %v, want the pinned journey", j.Legs)
+	}
+	a.journeys = journeys[:1]
+	if _, ok := a.trackedJourney(); ok {
+		t.Error("tracks something once the pinned journey is gone")
+	}
+
+	a.journeys, a.selectedIdx = journeys, 1
+	a.togglePin()
+	if a.pinnedID != "" {
+		t.Errorf("still pinned to %q", a.pinnedID)
+	}
+	if j, ok := a.trackedJourney(); !ok || model.JourneyID(j) != model.JourneyID(journeys[1]) {
+		t.Error("doesn't track the selection once unpinned")
+	}
+}
@@ -0,0 +1,162 @@
+package ui
+
+import "time"
+
+func (a *App) startAnimationLoop() {
+	frame := time.NewTimer(frameInterval)
+	nextFrame := make(chan time.Duration, 1)
+	refreshTicker := time.NewTicker(refreshInterval)
+	watchTicker := time.NewTicker(5 * time.Minute)
+
+	a.goSafe(func() {
+		for {
+			select {
+			case <-a.stopChan:
+				frame.Stop()
+				refreshTicker.Stop()
+				watchTicker.Stop()
+				return
+			case <-frame.C:
+				a.app.QueueUpdate(func() {
+					a.tick()
+					nextFrame <- a.nextFrameDelay()
+				})
+			case d := <-nextFrame:
+				frame.Reset(d)
+			case <-refreshTicker.C:
+				a.app.QueueUpdate(func() {
+					// Refresh less often during quiet hours
+					quiet := a.config.Notify.QuietHours
+					if quiet.Active(time.Now()) && time.Since(a.lastUpdate) < a.refreshEvery() {
+						return
+					}
+					a.refresh()
+				})
+			case <-watchTicker.C:
+				a.app.QueueUpdate(a.pollWatchList)
+			}
+		}
+	})
+}
+
+// refreshInterval is how often the journeys refresh outside quiet hours
+const refreshInterval = 30 * time.Second
+
+// frameInterval is the animation frame length; frame counters such as
+// statusMsgFrame count in these units even when ticking slower
+const frameInterval = 100 * time.Millisecond
+
+func (a *App) animationsEnabled() bool {
+	return !a.config.NoAnimations && !a.config.ReducedMotion
+}
+
+// animating reports whether anything on screen moves from frame to frame
+func (a *App) animating() bool {
+	if !a.animationsEnabled() {
+		return false
+	}
+	return a.showSplash || a.isLoading || a.loadingLater || a.refreshPulse || a.alarmFrame > 0
+}
+
+// nextFrameDelay runs at 10 FPS while something moves, and otherwise wakes
+// up on the next full second for the clock and countdowns
+func (a *App) nextFrameDelay() time.Duration {
+	if a.animating() {
+		return frameInterval
+	}
+	now := time.Now()
+	return now.Truncate(time.Second).Add(time.Second).Sub(now)
+}
+
+// blink alternates for flashing elements and stays on without animations
+func (a *App) blink() bool {
+	return !a.animationsEnabled() || a.animFrame%4 < 2
+}
+
+func (a *App) spinner() string {
+	if !a.animationsEnabled() {
+		return "⟳"
+	}
+	return spinnerFrames[a.animFrame%len(spinnerFrames)]
+}
+
+// tick advances the animation on the event loop and redraws only when
+// something visible changed
+func (a *App) tick() {
+	now := time.Now()
+	frames := 1
+	if !a.lastTick.IsZero() {
+		if n := int(now.Sub(a.lastTick) / frameInterval); n > 1 {
+			frames = n
+		}
+	}
+	a.lastTick = now
+
+	a.animFrame += frames
+	if a.selectedIdx < len(a.journeys) {
+		a.routeAnimFrame += frames
+	}
+
+	// Splash screen countdown
+	if a.showSplash {
+		a.splashFrame -= frames
+		if a.splashFrame > 0 {
+			return
+		}
+		a.showSplash = false
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+		a.suggestReverse()
+		a.refresh()
+		a.pollWatchList()
+		a.dirty = true
+	}
+
+	// Count down timed effects, redrawing once they end
+	countdown := func(n *int) {
+		if *n > 0 {
+			*n -= frames
+			if *n <= 0 {
+				*n = 0
+				a.dirty = true
+			}
+		}
+	}
+	countdown(&a.newHighlight)
+	countdown(&a.statusMsgFrame)
+	countdown(&a.alarmFrame)
+	countdown(&a.visualBellFrame)
+
+	a.pruneDeparted()
+	a.prefetchSelected()
+	a.checkLeaveAlarm()
+	a.checkDepartureBell()
+	a.checkGetOff()
+	a.checkConnectionDanger()
+	a.pollBookmarks()
+	a.pollLineWatch()
+	a.pollSplit()
+	if a.split != nil && now.Unix() != a.renderedAt.Unix() {
+		a.renderSplit()
+	}
+	if a.detailLive && now.Unix() != a.renderedAt.Unix() {
+		if page, _ := a.pages.GetFrontPage(); page == "detail" {
+			a.renderDetail()
+		}
+	}
+
+	// Clear IsNew after animation
+	if a.animFrame > 50 {
+		for i := range a.journeys {
+			a.journeys[i].IsNew = false
+		}
+	}
+
+	// The clock and countdowns change once a second
+	if a.animating() || now.Unix() != a.renderedAt.Unix() {
+		a.dirty = true
+	}
+	if a.dirty {
+		a.app.ForceDraw()
+	}
+}
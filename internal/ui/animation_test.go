@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestTickDirty(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(a *App)
+		dirty bool
+	}{
+		{"idle", func(a *App) {}, false},
+		{"the clock moved on", func(a *App) { a.renderedAt = a.renderedAt.Add(-time.Second) }, true},
+		{"loading", func(a *App) { a.isLoading = true }, true},
+		{"message still up", func(a *App) { a.statusMsgFrame = 5 }, false},
+		{"message ends", func(a *App) { a.statusMsgFrame = 1 }, true},
+		{"new journeys stop flashing", func(a *App) { a.newHighlight = 1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{app: tview.NewApplication(), renderedAt: time.Now()}
+			tt.setup(a)
+			a.tick()
+			if a.lastTick.Unix() != a.renderedAt.Unix() && !tt.dirty {
+				t.Skip("ticked into the next second")
+			}
+			if a.dirty != tt.dirty {
+				t.Errorf("dirty = %v, want %v", a.dirty, tt.dirty)
+			}
+		})
+	}
+}
+
+func TestNextFrameDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(a *App)
+		animating bool
+	}{
+		{"idle", func(a *App) {}, false},
+		{"loading", func(a *App) { a.isLoading = true }, true},
+		{"alarm flashing", func(a *App) { a.alarmFrame = 10 }, true},
+		{"loading without animations", func(a *App) { a.isLoading = true; a.config.NoAnimations = true }, false},
+		{"loading with --no-animations", func(a *App) { a.isLoading = true; a.config.ReducedMotion = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{}
+			tt.setup(a)
+			d := a.nextFrameDelay()
+			if tt.animating && d != frameInterval {
+				t.Errorf("waits %v while animating, want %v", d, frameInterval)
+			}
+			if !tt.animating && (d <= 0 || d > time.Second) {
+				t.Errorf("waits %v, want up to the next full second", d)
+			}
+			if !a.animationsEnabled() && (!a.blink() || a.spinner() != "⟳") {
+				t.Errorf("blinks or spins with animations off")
+			}
+		})
+	}
+}
+
+func TestTickCatchesUp(t *testing.T) {
+	a := &App{app: tview.NewApplication(), statusMsgFrame: 25, lastTick: time.Now().Add(-time.Second)}
+	a.tick()
+	if a.animFrame < 10 || a.statusMsgFrame > 15 {
+		t.Errorf("a second between ticks counts %d frames, message at %d, want 10 frames gone", a.animFrame, a.statusMsgFrame)
+	}
+}
@@ -0,0 +1,1019 @@
+// Package ui is the interactive terminal frontend.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/alert"
+	"go-commute/internal/config"
+	"go-commute/internal/diary"
+	"go-commute/internal/history"
+	"go-commute/internal/hooks"
+	"go-commute/internal/model"
+	"go-commute/internal/statusline"
+	"go-commute/internal/stops"
+	"go-commute/internal/vbb"
+	"go-commute/internal/weather"
+)
+
+// App holds the application state. Everything past the widgets is owned by
+// the tview event loop: mutate it only from input handlers or inside
+// QueueUpdate/QueueUpdateDraw, and hand background results back the same way.
+type App struct {
+	app         *tview.Application
+	screen      tcell.Screen
+	pages       *tview.Pages
+	list        *tview.Table
+	detail      *tview.TextView
+	header      *tview.TextView
+	legend      *tview.TextView
+	searchInput *tview.InputField
+	searchList  *tview.List
+	favList     *tview.List
+	lineWidget  *tview.TextView // the pinned line watch, collapsed when unused
+	mainFlex    *tview.Flex
+	cmdLine     *tview.InputField // the ':' command line, collapsed when closed
+	cmdHistory  []string
+
+	config         config.Config
+	client         vbb.TransitClient
+	journeys       []model.Journey
+	prevJourneyIDs map[string]bool
+	selectedIdx    int
+	pinnedID       string
+	tagged         map[string]bool // journey IDs picked with Space for the batch actions
+
+	bookmarksFetched  time.Time
+	bookmarksFetching bool
+	lastUpdate        time.Time
+	isLoading         bool
+
+	filters   map[string]bool
+	localOnly bool // long-distance products are filtered out
+	sortMode  string
+	viewFor   string // the route whose favorite's filters and sort are applied
+	transfers int
+	departAt  time.Time // zero leaves now
+
+	departed     []model.Journey // left already and put away, see pruneDeparted
+	showDeparted bool
+
+	refreshing string                   // refreshKey of the fetch in flight, empty when idle
+	changes    map[string]journeyChange // what the last refresh changed, by journey ID
+	better     map[string]bool          // journeys beating the pinned or selected one
+
+	trips         *tripCache
+	warm          map[string]*warmFetch // fetched at launch, by routeKey
+	selectedFor   string                // journey selected on the last tick
+	prefetchedFor string                // journey whose trips were last prefetched
+
+	laterRef     string // continues the journey search past the listed ones
+	laterPages   int    // later pages loaded by scrolling, fetched again on refresh
+	loadingLater bool
+
+	reverseUndo *[2]model.Station // origin and destination before auto-reverse
+
+	split *splitView // while the departures/arrivals boards are shown
+
+	farewell string // printed after the TUI exits, set by 'Q'
+
+	headerTmpl *template.Template // the configured header template, nil for the built-in one
+
+	searchTarget  string
+	searchResults []model.Station
+	searchTimer   *time.Timer
+	searchCancel  context.CancelFunc
+
+	// Animation state
+	animFrame      int
+	routeAnimFrame int
+	refreshPulse   bool
+	newHighlight   int // frames remaining for new highlight
+	delayHistory   map[string]*DelayHistory
+
+	alerts     *alert.Tracker
+	history    *history.History
+	diary      *diary.Diary
+	stops      *stops.Cache
+	lineStatus map[string][]string
+
+	// Status message
+	statusMsg      string
+	statusMsgFrame int
+
+	// Leave alarm
+	alarmFiredFor string
+	alarmFrame    int
+	riskAlerted   map[string]bool
+	getOffAlerted map[string]bool
+	detailLive    bool // a leg in the detail view is under way
+	danger        *connectionDanger
+
+	// Bells
+	visualBellFrame  int
+	departureRungFor string
+
+	// Last refresh error, shown as a banner over the previous journeys
+	refreshErr  error
+	journeysFor string
+
+	hidden    int  // journeys dropped by the avoid list
+	showHints bool // list hint remarks in the detail view
+
+	// Departures of the pinned line watch
+	lineDepartures    []model.Departure
+	lineWatchFetched  time.Time
+	lineWatchFetching bool
+	lineWatchErr      error
+
+	// Alternatives from re-planning mid-journey, kept across refreshes
+	replans    []model.Journey
+	replansFor string
+
+	weather    *weather.Conditions
+	weatherFor string // station ID the weather is for
+
+	// Redraw only when something visible changed
+	dirty      bool
+	renderedAt time.Time
+	lastTick   time.Time
+
+	// Splash screen
+	showSplash  bool
+	splashFrame int
+
+	ctx        context.Context // cancelled on shutdown
+	cancel     context.CancelFunc
+	stopChan   chan struct{}
+	stopping   bool
+	writes     sync.WaitGroup
+	crashed    crashState
+	publishing atomic.Bool // an MQTT round is under way
+}
+
+// Berlin Bear ASCII Art
+const berlinBearLogo = `
+    ┌──────────────────────────────────────────────────────────────────┐
+    │                                                                  │
+    │                                    ↑↑↑↑↑                         │
+    │                             ↑↑↑↑↑↑↑↑↑↑↑↑↑↑  ↑↑↑                  │
+    │                           ↑↑↑↑↑↑  ↑ ↑↑↑↑↑↑↑↑↑↑                   │
+    │                            ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │       ↙↙↙↙↑             ↙↙      ↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │      ↙↑↑↑↑↑↑                ↙↙↙↙↙ ↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │       ↗↑↑↑↑↑                → ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │        ↑↑↑↑↑↑                    ↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │         ↑↑↑↑↑↑↑↑               ↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │          ↑↑↑↑↑↑↑↑↑↑↑↑↑      ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │           ↑↑↑↑↑↑↑↑↑↑↑↑↑↑  ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │              ↑↑↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │                ↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │        ↙          ↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │      ↙↙↙↙                ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                     │
+    │    ↙↙↙↑↑↑↑↑          ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                      │
+    │     ↙↑↑↑↑↑↑↑     ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                      │
+    │      ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                      │
+    │        ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                     │
+    │          ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑ ↘↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                     │
+    │               ↑↑↑↑        ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │                           ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │                            ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                    │
+    │                              ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │                          ↑↑↑↑↑↗↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                   │
+    │                       ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │                     ↑↑↑↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                 │
+    │                    ↑↑↑↑↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │                   ↑↑↑↑↑↑↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │                  ↑↑↑↑↑↑↑↑↑↑↑↑↑↑↑ ↑↑↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │                  ↑↑↑↑↑↑↑↑↑↑↑↑↑    ↑↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │                  ↑↑↑↑↑↑↑↑↑↑↑       ↑↑↑↑↑↑↑↑↑↑↑↑                  │
+    │           ↙      ↑↑↑↑↑↑↑↑↑          ↑↑↑↑↑↑↑↑↑↑↑↑                 │
+    │          ↙↙↑↑↑↑   ↑↑↑↑↑↑↑             ↑↑↑↑↑↑↑↑↑↑                 │
+    │         ↙↙↙↑↑↑↑↑↑↑↑↑↑↑↑↑                ↑↑↑↑↑↑↑↑↑                │
+    │           ↙↑↑↑↑↑↑↑↑↑↑↑↑↑                ↑↑↑↑↑↑↑↑                 │
+    │                ↑↑↑↑↑↑↑↑             ↙↑↑↑↑↑↑↑↑↑                   │
+    │                                    ↙↙↑↑↑↑↑↑↑                     │
+    │                                      ↙↑↑↑                        │
+    │                                                                  │
+        -------------------[-][-][yellow]BERRRRLIN ROUTER[-][-][-]-------------------     
+    │                                                                  │
+    └──────────────────────────────────────────────────────────────────┘
+`
+
+// NewApp builds the TUI for the given config
+func NewApp(cfg config.Config, client vbb.TransitClient) *App {
+	cfg.ASCII = !unicodeTerminal()
+	a := &App{
+		app:            tview.NewApplication(),
+		pages:          tview.NewPages(),
+		config:         cfg,
+		client:         client,
+		filters:        make(map[string]bool),
+		sortMode:       "departure",
+		transfers:      vbb.DefaultTransfers,
+		prevJourneyIDs: make(map[string]bool),
+		trips:          newTripCache(),
+		delayHistory:   make(map[string]*DelayHistory),
+		alerts:         alert.NewTracker(),
+		riskAlerted:    make(map[string]bool),
+		getOffAlerted:  make(map[string]bool),
+		tagged:         make(map[string]bool),
+		lineStatus:     make(map[string][]string),
+		history:        history.Load(),
+		diary:          diary.Load(),
+		stops:          stops.Load(cfg.Preset().Name),
+		stopChan:       make(chan struct{}),
+		showSplash:     true,
+		splashFrame:    20, // 2 seconds at 10fps
+	}
+
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+
+	for _, p := range cfg.Preset().Products {
+		a.filters[p.ID] = true
+	}
+
+	a.setupUI()
+	return a
+}
+
+func (a *App) setupUI() {
+	// Header with clock
+	a.header = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+
+	// Pinned line watch between header and journeys
+	a.lineWidget = tview.NewTextView().
+		SetDynamicColors(true)
+
+	// Main list view
+	// One row per line, so a refresh only touches the rows that changed
+	a.list = tview.NewTable().
+		SetSelectable(false, false)
+
+	// Detail view
+	a.detail = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	a.detail.SetBorder(true).SetTitle(" Journey Details ")
+
+	// Search components
+	a.searchInput = tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldWidth(30)
+
+	a.searchList = tview.NewList().
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorBlue)
+
+	searchFlex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.searchInput, 1, 0, true).
+		AddItem(a.searchList, 0, 1, false)
+	searchFlex.SetBorder(true).SetTitle(" Search Station ")
+
+	// Favorites list
+	a.favList = tview.NewList().
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorBlue)
+	a.favList.SetBorder(true).SetTitle(" Favorites (Enter=Load, a=Add current, d=Delete, w=Schedule, Esc=Back) ")
+
+	// Legend bar at bottom
+	a.legend = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	a.legend.SetText("[dim]─────────────────────────────────────────────────────────────────────────[-]\n" +
+		"[dim] Keys:[-] j/k Nav   Enter Detail   s Search   F Favorites   w When   o Sort   P Re-plan   : Command   ^P Launcher   r Refresh   ? Help   q Quit\n" +
+		"[dim] Legend:[-] [green]○ Low [yellow]◐ Med [red]● High Occupancy   [yellow]⏱ Delayed   [yellow]🏃 Leave now   [red]⚡ Tight Connection   [red]✗ At Risk/Cancelled   [red]⚠ Warning   [red]♿ Elevator out   [red]⊘ Ticket not valid   [green]★ New   [cyan]⚑ Pinned   [cyan]✓ Tagged   [cyan]🔖 Bookmarked   [magenta]↻ Re-planned   [green]▲ Better   [blue]☾ Night service")
+
+	// Splash screen
+	splash := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	splash.SetText(berlinBearLogo)
+
+	// Command line, opened with ':'
+	a.cmdLine = a.newCommandLine()
+
+	// Main layout with legend
+	a.mainFlex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.header, 3, 0, false).
+		AddItem(a.lineWidget, 0, 0, false).
+		AddItem(a.list, 0, 1, true).
+		AddItem(a.cmdLine, 0, 0, false).
+		AddItem(a.legend, 3, 0, false)
+
+	// Keep hold of the screen for the terminal bell, and bring the main
+	// screen up to date right before it is drawn
+	a.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		a.screen = screen
+		if a.dirty {
+			a.dirty = false
+			a.renderedAt = time.Now()
+			a.renderHeader()
+			a.renderLineWatch()
+			a.renderList()
+		}
+		return false
+	})
+
+	// Any key may change what the main screen shows. Ctrl-C would make
+	// tview stop without saving, so it goes through shutdown like 'q'.
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlC {
+			a.shutdown()
+			return nil
+		}
+		// Screenshots work on every page, with colors or without
+		switch event.Key() {
+		case tcell.KeyCtrlS:
+			a.dumpScreen(false)
+			return nil
+		case tcell.KeyCtrlX:
+			a.dumpScreen(true)
+			return nil
+		}
+		a.dirty = true
+		return event
+	})
+
+	a.pages.AddPage("splash", splash, true, true)
+	a.pages.AddPage("main", a.mainFlex, true, false)
+	a.pages.AddPage("detail", a.detail, true, false)
+	a.pages.AddPage("search", searchFlex, true, false)
+	a.pages.AddPage("favorites", a.favList, true, false)
+
+	a.setupKeyBindings()
+}
+
+func (a *App) setupKeyBindings() {
+	a.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if a.selectedIdx > 0 {
+				a.selectedIdx--
+				a.routeAnimFrame = 0
+			}
+			return nil
+		case tcell.KeyDown:
+			a.selectNext()
+			return nil
+		case tcell.KeyEnter:
+			if len(a.journeys) > 0 {
+				a.showDetail()
+			}
+			return nil
+		case tcell.KeyCtrlP:
+			a.showLauncher()
+			return nil
+		case tcell.KeyEscape:
+			a.clearTags()
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				if a.selectedIdx > 0 {
+					a.selectedIdx--
+					a.routeAnimFrame = 0
+				}
+				return nil
+			case 'j':
+				a.selectNext()
+				return nil
+			case 'r':
+				a.refresh()
+				return nil
+			case 'H':
+				a.goHome()
+				return nil
+			case 'T':
+				a.planRoundTrip()
+				return nil
+			case 'N':
+				a.planAppointment()
+				return nil
+			case 'L':
+				a.promptLineWatch()
+				return nil
+			case 'R':
+				a.swapRoute()
+				return nil
+			case 's':
+				a.showSearch("origin")
+				return nil
+			case 'F':
+				a.showFavorites()
+				return nil
+			case 'a':
+				a.addFavorite()
+				return nil
+			case 'i':
+				a.exportICS()
+				return nil
+			case 'c':
+				a.showQR()
+				return nil
+			case 'y':
+				a.yankJourney()
+				return nil
+			case 'p':
+				a.togglePin()
+				return nil
+			case 'P':
+				a.replanFromHere()
+				return nil
+			case 'D':
+				a.showDisruptions()
+				return nil
+			case 'S':
+				a.showStats()
+				return nil
+			case 'm':
+				a.markTaken()
+				return nil
+			case 'M':
+				a.showDiary()
+				return nil
+			case 'o':
+				a.cycleSort()
+				return nil
+			case '0', '1', '2', '3':
+				a.setTransfers(int(event.Rune() - '0'))
+				return nil
+			case 'w':
+				a.promptDeparture()
+				return nil
+			case 'O':
+				a.openJourneyMap()
+				return nil
+			case 'G':
+				a.exportGPX()
+				return nil
+			case 'Q':
+				a.quitWithJourney()
+				return nil
+			case 'V':
+				a.showSplit()
+				return nil
+			case 'u':
+				a.undoReverse()
+				return nil
+			case 'E':
+				a.toggleLongDistance()
+				return nil
+			case 'z':
+				a.cycleDensity()
+				return nil
+			case 'd':
+				a.toggleDeparted()
+				return nil
+			case '<':
+				a.setTransfers(a.transfers - 1)
+				return nil
+			case '>':
+				a.setTransfers(a.transfers + 1)
+				return nil
+			case ':':
+				a.showCommandLine()
+				return nil
+			case ' ':
+				a.toggleTag()
+				return nil
+			case 'C':
+				a.showCompare()
+				return nil
+			case 'B':
+				a.toggleBookmark()
+				return nil
+			case 'e':
+				a.exportCSV()
+				return nil
+			case '?':
+				a.showHelp()
+				return nil
+			case 'q':
+				a.shutdown()
+				return nil
+			}
+			if macro, ok := a.config.Macros[string(event.Rune())]; ok {
+				a.runMacro(string(event.Rune()), macro)
+				return nil
+			}
+		}
+		return event
+	})
+
+	a.detail.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'q', 'b':
+				a.pages.SwitchToPage("main")
+				a.app.SetFocus(a.list)
+				return nil
+			case 'i':
+				a.exportICS()
+				return nil
+			case 'c':
+				a.showQR()
+				return nil
+			case 'y':
+				a.yankJourney()
+				return nil
+			case 'x':
+				a.showAvoidStations()
+				return nil
+			case 't':
+				a.showTrips()
+				return nil
+			case 'w':
+				a.openWalkingMap()
+				return nil
+			case 'O':
+				a.openJourneyMap()
+				return nil
+			case 'G':
+				a.exportGPX()
+				return nil
+			case 'Q':
+				a.quitWithJourney()
+				return nil
+			case 'h':
+				a.showHints = !a.showHints
+				a.showDetail()
+				return nil
+			}
+		}
+		return event
+	})
+
+	a.searchInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+		} else if key == tcell.KeyEnter || key == tcell.KeyTab {
+			if a.searchList.GetItemCount() > 0 {
+				a.app.SetFocus(a.searchList)
+			}
+		}
+	})
+
+	a.searchInput.SetChangedFunc(a.queueSearch)
+
+	a.searchList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+}
+
+func (a *App) selectStation(station model.Station) {
+	if a.searchTarget == "origin" {
+		a.config.LastOrigin = station
+		a.searchTarget = "dest"
+		a.searchInput.SetText("")
+		a.searchInput.SetLabel("Destination: ")
+		a.searchList.Clear()
+		a.app.SetFocus(a.searchInput)
+	} else {
+		a.config.LastDest = station
+		slog.Info("route selected", "route", model.RouteName(a.config.LastOrigin, station))
+		config.Save(a.config)
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+		a.refresh()
+	}
+}
+
+func (a *App) showSearch(target string) {
+	a.searchTarget = target
+	a.searchInput.SetText("")
+	if target == "origin" {
+		a.searchInput.SetLabel("Origin: ")
+	} else {
+		a.searchInput.SetLabel("Destination: ")
+	}
+	a.searchList.Clear()
+	a.addLocationItem()
+	a.pages.SwitchToPage("search")
+	a.app.SetFocus(a.searchInput)
+}
+
+func (a *App) showFavorites() {
+	a.favList.Clear()
+
+	if len(a.config.Routes) == 0 {
+		a.favList.AddItem("No favorites saved", "Press 'a' on main screen to add current route", 0, nil)
+	} else {
+		for i, fav := range a.config.Routes {
+			idx := i
+			origin := a.config.Label(fav.Origin)
+			dest := a.config.Label(fav.Dest)
+			schedule := ""
+			if fav.Schedule != "" {
+				schedule = "🕗 " + fav.Schedule
+			}
+			a.favList.AddItem(fmt.Sprintf("%s → %s", origin, dest), schedule, 0, func() {
+				a.loadFavorite(idx)
+			})
+		}
+	}
+
+	a.favList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		case tcell.KeyRune:
+			if event.Rune() == 'd' && len(a.config.Routes) > 0 {
+				idx := a.favList.GetCurrentItem()
+				if idx >= 0 && idx < len(a.config.Routes) {
+					a.config.Routes = append(a.config.Routes[:idx], a.config.Routes[idx+1:]...)
+					config.Save(a.config)
+					a.showFavorites()
+				}
+				return nil
+			}
+			if event.Rune() == 'w' && len(a.config.Routes) > 0 {
+				idx := a.favList.GetCurrentItem()
+				if idx >= 0 && idx < len(a.config.Routes) {
+					a.scheduleFavorite(idx)
+				}
+				return nil
+			}
+		}
+		return event
+	})
+
+	a.pages.SwitchToPage("favorites")
+	a.app.SetFocus(a.favList)
+}
+
+// scheduleFavorite asks when a favorite should be the route the app
+// starts with
+func (a *App) scheduleFavorite(idx int) {
+	fav := a.config.Routes[idx]
+	a.prompt("Schedule", "Show at startup during (05:00-12:00): ", fav.Schedule, func(text string) {
+		text = strings.TrimSpace(text)
+		if err := config.ValidateSchedule(text); err != nil {
+			a.statusMsg = "⚠ " + err.Error()
+			a.statusMsgFrame = 50
+			return
+		}
+		a.config.Routes[idx].Schedule = text
+		config.Save(a.config)
+		if text == "" {
+			a.statusMsg = "Schedule removed"
+		} else {
+			a.statusMsg = "🕗 " + model.RouteName(fav.Origin, fav.Dest) + " at startup during " + text
+		}
+		a.statusMsgFrame = 30
+	})
+}
+
+func (a *App) loadFavorite(idx int) {
+	if idx >= 0 && idx < len(a.config.Routes) {
+		fav := a.config.Routes[idx]
+		a.config.LastOrigin = fav.Origin
+		a.config.LastDest = fav.Dest
+		slog.Info("favorite loaded", "route", model.RouteName(fav.Origin, fav.Dest))
+		config.Save(a.config)
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+		a.refresh()
+	}
+}
+
+func (a *App) addFavorite() {
+	// Check if already exists
+	if a.isFavorite(a.config.LastOrigin, a.config.LastDest) {
+		a.statusMsg = "Already in favorites"
+		a.statusMsgFrame = 30
+		return
+	}
+
+	a.config.Routes = append(a.config.Routes, model.FavoriteRoute{
+		Origin: a.config.LastOrigin,
+		Dest:   a.config.LastDest,
+	})
+	a.rememberView()
+	config.Save(a.config)
+	a.statusMsg = "★ Added to favorites!"
+	a.statusMsgFrame = 30
+}
+
+func (a *App) isFavorite(origin, dest model.Station) bool {
+	for _, fav := range a.config.Routes {
+		if fav.Origin.ID == origin.ID && fav.Dest.ID == dest.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh fetches the journeys for the current route. It must run on the
+// event loop; the fetch itself happens in the background on a snapshot of
+// the config. Only one fetch runs at a time: asking again for the same is
+// dropped, and a result for a route or options changed meanwhile is thrown
+// away and fetched anew.
+func (a *App) refresh() {
+	a.applyFavoriteView()
+	if !a.departAt.IsZero() && a.departAt.Before(time.Now()) {
+		a.departAt = time.Time{}
+	}
+	key := a.refreshKey()
+	if a.refreshing != "" {
+		return
+	}
+	a.refreshing = key
+	a.isLoading = true
+	a.refreshPulse = true
+
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+	mqttCfg, notify, avoid, hookCfg := a.config.MQTT, a.config.Notify, a.config.Avoid, a.config.Hooks
+	transfers, products, departAt := a.transfers, a.products(), a.departAt
+	favorites := append([]model.FavoriteRoute(nil), a.config.Routes...)
+	laterPages := a.laterPages
+	if a.journeysFor != model.RouteName(origin, dest) {
+		laterPages = 0
+	}
+	slog.Debug("refresh", "route", model.RouteName(origin, dest))
+
+	var warm *warmFetch
+	if laterPages == 0 {
+		warm = a.takeWarm(key)
+	}
+
+	a.goSafe(func() {
+		var journeys []model.Journey
+		var laterRef string
+		var err error
+		if warm != nil {
+			<-warm.done
+			journeys, laterRef, err = warm.journeys, warm.laterRef, warm.err
+		}
+		if warm == nil || err != nil {
+			opts := vbb.JourneyOptions{Products: products, Transfers: &transfers, Departure: departAt}.From(origin)
+			journeys, laterRef, err = fetchJourneys(a.ctx, a.client, origin.ID, dest.ID, opts, laterPages)
+		}
+		hidden := 0
+		var alerts []alert.Alert
+		if err == nil {
+			// History keeps everything, the rest only sees what's worth taking
+			a.history.Record(model.RouteName(origin, dest), journeys)
+			journeys, hidden = avoid.Filter(journeys, origin, dest)
+		}
+
+		// Publish the favorite routes for home automation
+		if err == nil && mqttCfg != nil {
+			a.publishFavorites(*mqttCfg, favorites, avoid, model.FavoriteRoute{Origin: origin, Dest: dest}, journeys)
+		}
+		if err == nil {
+			route := model.RouteName(origin, dest)
+			alerts = a.alerts.Check(route, journeys, notify)
+			if len(alerts) > 0 {
+				a.goSafe(func() { alert.Dispatch(notify, alerts) })
+			}
+			if len(hookCfg) > 0 {
+				// The list gets sorted and marked on the event loop meanwhile
+				snapshot := slices.Clone(journeys)
+				a.goSafe(func() { runRefreshHooks(hookCfg, route, snapshot, alerts) })
+			}
+			a.goWrite(func() { a.history.Save() })
+			if a.diary.Update(journeys) {
+				a.goWrite(func() { a.diary.Save() })
+			}
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			a.refreshing = ""
+			if a.refreshKey() != key {
+				slog.Debug("discarding stale refresh", "route", model.RouteName(origin, dest))
+				a.refresh()
+				return
+			}
+			if err != nil {
+				a.refreshFailed(model.RouteName(origin, dest), err)
+				return
+			}
+			if a.refreshErr != nil {
+				slog.Info("refresh recovered", "route", model.RouteName(origin, dest))
+			}
+			a.refreshErr = nil
+			a.config.LastUsed = time.Now()
+			a.config.RememberRoute(origin, dest)
+			slog.Debug("refreshed", "route", model.RouteName(origin, dest), "journeys", len(journeys))
+
+			// Cancelled journeys are demoted by sorting, or dropped, but
+			// the pinned one alerts either way
+			a.checkCancelled(journeys)
+			if a.config.HideCancelled {
+				journeys = dropCancelled(journeys)
+			}
+
+			// Detect new journeys
+			newIDs := make(map[string]bool)
+			hasNew := false
+			for i := range journeys {
+				id := model.JourneyID(journeys[i])
+				newIDs[id] = true
+				if !a.prevJourneyIDs[id] {
+					journeys[i].IsNew = true
+					hasNew = true
+				} else {
+					journeys[i].IsNew = false
+				}
+			}
+			a.prevJourneyIDs = newIDs
+
+			if hasNew {
+				a.newHighlight = 30 // Flash for 30 frames (~3 seconds)
+			}
+
+			// Update delay history for sparklines
+			for _, j := range journeys {
+				for _, leg := range j.Legs {
+					if leg.DepDelay > 0 {
+						if _, ok := a.delayHistory[leg.Line]; !ok {
+							a.delayHistory[leg.Line] = &DelayHistory{Line: leg.Line}
+						}
+						hist := a.delayHistory[leg.Line]
+						hist.Delays = append(hist.Delays, leg.DepDelay/60)
+						if len(hist.Delays) > 20 {
+							hist.Delays = hist.Delays[len(hist.Delays)-20:]
+						}
+						hist.Updated = time.Now()
+					}
+				}
+			}
+
+			lineDelays := a.history.LineDelays()
+			for i := range journeys {
+				journeys[i].Reliability = history.JourneyReliability(journeys[i], lineDelays)
+			}
+			journeys = a.withReplans(journeys, model.RouteName(origin, dest))
+			sortJourneys(journeys, a.sortMode)
+
+			// Keep the selection on the same journey if it is still listed
+			selected := ""
+			if a.selectedIdx < len(a.journeys) && a.journeysFor == model.RouteName(origin, dest) {
+				selected = model.JourneyID(a.journeys[a.selectedIdx])
+			}
+			a.selectedIdx = journeyIndex(journeys, selected)
+
+			if a.journeysFor == model.RouteName(origin, dest) {
+				changes, gone := diffJourneys(append(slices.Clone(a.journeys), a.departed...), journeys, time.Now())
+				a.noteChanges(a.journeysFor, changes, gone)
+			} else {
+				a.changes = nil
+			}
+			current := a.pinnedID
+			if current == "" {
+				current = selected
+			}
+			a.checkBetter(journeys, current)
+			a.noteBookmarks(journeys)
+			if a.journeysFor != model.RouteName(origin, dest) {
+				a.tagged = map[string]bool{}
+			}
+			a.journeys = journeys
+			a.journeysFor = model.RouteName(origin, dest)
+			a.laterRef, a.laterPages = laterRef, laterPages
+			a.departed = nil
+			a.pruneDeparted()
+			a.alarmPlatformChange(alerts)
+			a.hidden = hidden
+			a.lastUpdate = time.Now()
+			a.isLoading = false
+			a.dirty = true
+			a.checkConnectionRisk()
+			a.updateWeather()
+
+			// Stop refresh pulse after a moment
+			time.AfterFunc(500*time.Millisecond, func() {
+				defer a.recoverPanic()
+				a.app.QueueUpdate(func() {
+					a.refreshPulse = false
+					a.dirty = true
+				})
+			})
+		})
+	})
+}
+
+// journeyIndex is where the journey with the given ID is listed, the top
+// when it's gone
+func journeyIndex(journeys []model.Journey, id string) int {
+	for i := range journeys {
+		if model.JourneyID(journeys[i]) == id {
+			return i
+		}
+	}
+	return 0
+}
+
+// refreshFailed shows the error as a banner over the last results, which
+// stay on screen unless they belong to another route
+func (a *App) refreshFailed(route string, err error) {
+	slog.Warn("refresh failed", "route", route, "err", err)
+	if a.refreshErr == nil {
+		a.ring("refresh_fail")
+	}
+	a.refreshErr = err
+	a.isLoading = false
+	a.refreshPulse = false
+	a.dirty = true
+
+	if a.journeysFor != route {
+		a.journeys = nil
+		a.selectedIdx = 0
+	}
+}
+
+// refreshKey identifies what a refresh fetches: the route and the options
+func (a *App) refreshKey() string {
+	return a.routeKey(a.config.LastOrigin, a.config.LastDest, a.products())
+}
+
+// routeKey is refreshKey for another route with the given products and
+// the other current options
+func (a *App) routeKey(origin, dest model.Station, products map[string]bool) string {
+	return fmt.Sprint(origin.ID, origin.Name, "→", dest.ID, a.transfers, products, a.departAt.Unix())
+}
+
+func (a *App) Run() (err error) {
+	if err := alert.ValidateRules(a.config.Notify.Rules); err != nil {
+		a.statusMsg = "⚠ " + err.Error()
+		a.statusMsgFrame = 100
+	}
+	if err := hooks.Validate(a.config.Hooks); err != nil {
+		a.statusMsg = "⚠ " + err.Error()
+		a.statusMsgFrame = 100
+	}
+	if err := a.config.ValidateProducts(); err != nil {
+		a.statusMsg = "⚠ " + err.Error()
+		a.statusMsgFrame = 100
+	}
+	if a.config.Icons != "" && a.config.ASCII {
+		a.statusMsg = "This terminal can't show " + a.config.Icons + " icons, using text tags"
+		a.statusMsgFrame = 50
+	}
+	if err := validateMacros(a.config.Macros); err != nil {
+		a.statusMsg = "⚠ " + err.Error()
+		a.statusMsgFrame = 100
+	}
+	if err := validateColumns(a.config.Columns); err != nil {
+		a.statusMsg = "⚠ " + err.Error()
+		a.statusMsgFrame = 100
+	}
+	if text := a.config.Templates.Header; text != "" {
+		if tmpl, err := statusline.Parse("header", text); err != nil {
+			a.statusMsg = "⚠ header template: " + err.Error()
+			a.statusMsgFrame = 100
+		} else {
+			a.headerTmpl = tmpl
+		}
+	}
+	a.restoreBookmarks()
+	a.applyFavoriteView()
+	a.isLoading = true // Show loading spinner after splash
+	a.warmStart()
+	a.startAnimationLoop()
+	a.syncStops()
+
+	// tview finalizes the screen before re-panicking from its event loop
+	defer func() {
+		if p := recover(); p != nil {
+			err = &Crash{Value: p, Stack: debug.Stack()}
+		}
+	}()
+	defer a.handleSignals()()
+	if err := a.app.SetRoot(a.pages, true).EnableMouse(true).Run(); err != nil {
+		return err
+	}
+	a.shutdown()
+	a.flush()
+	if a.crashed.crash != nil {
+		return a.crashed.crash
+	}
+	return nil
+}
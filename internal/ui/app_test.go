@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestRefreshKey(t *testing.T) {
+	base := func() *App {
+		a := &App{transfers: 3, filters: map[string]bool{"bus": true, "express": true}}
+		a.config.LastOrigin = model.Station{ID: "900180001", Name: "S Köpenick (Berlin)"}
+		a.config.LastDest = model.Station{ID: "900100041", Name: "Brunnenstr./Invalidenstr. (Berlin)"}
+		return a
+	}
+	key := base().refreshKey()
+
+	tests := []struct {
+		name   string
+		change func(a *App)
+		same   bool
+	}{
+		{"nothing", func(a *App) {}, true},
+		{"selection", func(a *App) { a.selectedIdx = 4 }, true},
+		{"origin", func(a *App) {
+			a.config.LastOrigin = model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+		}, false},
+		{"address origin", func(a *App) {
+			a.config.LastOrigin = model.AddressAt("Torstraße 1", model.Coordinates{Latitude: 52.5, Longitude: 13.4})
+		}, false},
+		{"destination", func(a *App) { a.config.LastDest = model.Station{ID: "900023201"} }, false},
+		{"transfers", func(a *App) { a.transfers = 1 }, false},
+		{"products", func(a *App) { a.filters = map[string]bool{"bus": false, "express": true} }, false},
+		{"departure", func(a *App) { a.departAt = time.Date(2026, 10, 17, 8, 0, 0, 0, model.DisplayZone) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := base()
+			tt.change(a)
+			if got := a.refreshKey(); (got == key) != tt.same {
+				t.Errorf("key %q against %q, want same %v", got, key, tt.same)
+			}
+		})
+	}
+}
+
+// routeClient reports the origin of each journey request, then fails it
+type routeClient struct {
+	vbb.TransitClient
+	origins chan string
+}
+
+func (c *routeClient) Journeys(ctx context.Context, originID, destID string, opts vbb.JourneyOptions) ([]model.Journey, error) {
+	c.origins <- originID
+	return nil, errors.New("offline")
+}
+
+func TestRefreshSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client := &routeClient{origins: make(chan string)}
+	var cfg config.Config
+	cfg.LastOrigin = model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	cfg.LastDest = model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+	a := NewApp(cfg, client)
+	defer a.cancel()
+
+	// The fetch runs in the background on what the route was when it started
+	a.refresh()
+	a.config.LastOrigin = model.Station{ID: "900100003", Name: "S+U Alexanderplatz (Berlin)"}
+	if got := <-client.origins; got != "900120004" {
+		t.Errorf("fetched from %s, want the origin at the time of the refresh", got)
+	}
+}
+
+func TestRefreshFailed(t *testing.T) {
+	journeys := []model.Journey{{LeaveAt: time.Now(), Legs: []model.Leg{{Line: "S5"}}}}
+
+	tests := []struct {
+		name     string
+		before   error // banner already up
+		shownFor string
+		route    string
+		kept     bool
+		rings    bool
+	}{
+		{"same route", nil, "A → B", "A → B", true, true},
+		{"failing again", errors.New("timeout"), "A → B", "A → B", true, false},
+		{"another route", nil, "A → B", "A → C", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{journeys: journeys, journeysFor: tt.shownFor, refreshErr: tt.before, isLoading: true}
+			a.config.Bell.RefreshFail = config.BellVisual
+			err := errors.New("offline")
+			a.refreshFailed(tt.route, err)
+
+			if a.refreshErr != err || a.isLoading || !a.dirty {
+				t.Errorf("error %v, loading %v, dirty %v after a failed refresh", a.refreshErr, a.isLoading, a.dirty)
+			}
+			if kept := len(a.journeys) > 0; kept != tt.kept {
+				t.Errorf("kept the journeys: %v, want %v", kept, tt.kept)
+			}
+			if rang := a.visualBellFrame > 0; rang != tt.rings {
+				t.Errorf("rang: %v, want %v", rang, tt.rings)
+			}
+		})
+	}
+}
+
+func TestJourneyIndex(t *testing.T) {
+	journeys := []model.Journey{
+		{RefreshToken: "a"},
+		{RefreshToken: "b"},
+		{RefreshToken: "c"},
+	}
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{"b", 1},
+		{"c", 2},
+		{"gone", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := journeyIndex(journeys, tt.id); got != tt.want {
+			t.Errorf("journeyIndex(%q) = %d, want %d", tt.id, got, tt.want)
+		}
+	}
+}
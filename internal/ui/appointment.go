@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/calendar"
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// appointmentRadius is how far from an appointment the stop to head for may be
+const appointmentRadius = 1000
+
+// appointmentPlan is the way to the next calendar event
+type appointmentPlan struct {
+	event    calendar.Event
+	stop     model.Station
+	arriveBy time.Time
+	journeys []model.Journey
+}
+
+// planAppointment reads the next event from the configured calendar, finds
+// the stop closest to where it is and plans to arrive a little early
+func (a *App) planAppointment() {
+	cal := a.config.Calendar
+	if cal == nil || cal.Source == "" {
+		a.statusMsg = "⚠ No calendar configured, set calendar.source in " + config.Path()
+		a.statusMsgFrame = 50
+		return
+	}
+	source, early := cal.Source, cal.Early()
+	origin := a.config.LastOrigin
+
+	a.statusMsg = "📅 Reading the calendar…"
+	a.statusMsgFrame = 30
+	a.goSafe(func() {
+		plan, err := a.fetchAppointment(a.ctx, source, early, origin)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				slog.Warn("appointment planning failed", "err", err)
+				a.statusMsg = "⚠ " + err.Error()
+				a.statusMsgFrame = 50
+				return
+			}
+			a.showAppointment(plan)
+		})
+	})
+}
+
+func (a *App) fetchAppointment(ctx context.Context, source string, early time.Duration, origin model.Station) (appointmentPlan, error) {
+	events, err := calendar.Fetch(ctx, source, model.DisplayZone)
+	if err != nil {
+		return appointmentPlan{}, fmt.Errorf("reading calendar: %w", err)
+	}
+	ev, ok := calendar.Next(events, time.Now())
+	if !ok {
+		return appointmentPlan{}, errors.New("no upcoming appointment with a location")
+	}
+
+	stop, err := a.stopNear(ctx, ev.Location)
+	if err != nil {
+		return appointmentPlan{}, fmt.Errorf("%s: %w", ev.Location, err)
+	}
+	plan := appointmentPlan{event: ev, stop: stop, arriveBy: ev.Start.Add(-early)}
+	if stop.ID == origin.ID {
+		return plan, nil
+	}
+	plan.journeys, err = a.client.Journeys(ctx, origin.ID, stop.ID, vbb.JourneyOptions{Arrival: plan.arriveBy}.From(origin))
+	if err != nil {
+		return appointmentPlan{}, err
+	}
+	return plan, nil
+}
+
+// stopNear is the stop closest to a free-text location. Clients that can't
+// geocode, or addresses they can't place, fall back to a station search.
+func (a *App) stopNear(ctx context.Context, location string) (model.Station, error) {
+	if geocoder, ok := a.client.(vbb.Geocoder); ok {
+		at, err := geocoder.Geocode(ctx, location)
+		if err == nil {
+			stops, err := a.client.Nearby(ctx, at, appointmentRadius)
+			if err == nil && len(stops) > 0 {
+				return stops[0].Station, nil
+			}
+		}
+	}
+	stations, err := a.client.SearchStations(ctx, location)
+	if err != nil {
+		return model.Station{}, err
+	}
+	if len(stations) == 0 {
+		return model.Station{}, errors.New("no stop nearby")
+	}
+	return stations[0], nil
+}
+
+func (a *App) showAppointment(plan appointmentPlan) {
+	ev := plan.event
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[yellow::b]%s[-:-:-]  [dim]%s[-]\n",
+		tview.Escape(ev.Summary), ev.Start.In(model.DisplayZone).Format("Mon 2 Jan ")+model.FormatTime(ev.Start)))
+	sb.WriteString(fmt.Sprintf("%s\n", tview.Escape(ev.Location)))
+	sb.WriteString(fmt.Sprintf("[dim]Nearest stop:[-] %s\n\n", model.CleanStation(plan.stop.Name)))
+
+	switch {
+	case plan.stop.ID == a.config.LastOrigin.ID:
+		sb.WriteString("[green]It's right where you are[-]\n")
+	case len(plan.journeys) == 0:
+		sb.WriteString("[dim]No connections found[-]\n")
+	default:
+		sb.WriteString(fmt.Sprintf("[yellow::b]Arriving by %s[-:-:-]\n", model.FormatTime(plan.arriveBy)))
+		// The latest journeys that still make it are the useful ones
+		shown := plan.journeys
+		if len(shown) > 3 {
+			shown = shown[len(shown)-3:]
+		}
+		for _, j := range shown {
+			sb.WriteString(a.journeySummary(j) + "\n")
+		}
+		last := shown[len(shown)-1]
+		sb.WriteString(fmt.Sprintf("\n[green::b]Leave at %s[-:-:-], %s before it starts\n",
+			model.FormatTime(last.LeaveAt), formatSpan(ev.Start.Sub(last.LeaveAt))))
+	}
+	sb.WriteString("\n[dim]Press Enter to make it the current route, ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Next Appointment ")
+	view.SetText(sb.String())
+	dismiss := func() {
+		a.pages.RemovePage("appointment")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+	}
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEnter:
+			dismiss()
+			if plan.stop.ID != a.config.LastOrigin.ID {
+				a.config.LastDest = plan.stop
+				config.Save(a.config)
+				slog.Info("route selected", "route", model.RouteName(a.config.LastOrigin, plan.stop))
+				a.refresh()
+			}
+			return nil
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q':
+			dismiss()
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("appointment", view, true, false)
+	a.pages.SwitchToPage("appointment")
+	a.app.SetFocus(view)
+}
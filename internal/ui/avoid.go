@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"log/slog"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// showAvoidStations lists the stations of the selected journey so one can
+// be put on, or taken off, the avoid list
+func (a *App) showAvoidStations() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+
+	var stations []model.Station
+	for _, s := range j.Stations() {
+		if s.ID != "" && s.ID != origin.ID && s.ID != dest.ID {
+			stations = append(stations, s)
+		}
+	}
+
+	list := tview.NewList().
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorBlue)
+	list.SetBorder(true).SetTitle(" Avoid station (Enter=Toggle, Esc=Back) ")
+
+	back := func() {
+		a.pages.RemovePage("avoid")
+		a.pages.SwitchToPage("detail")
+		a.app.SetFocus(a.detail)
+	}
+	label := func(s model.Station) string {
+		if a.config.Avoid.AvoidsStation(s) {
+			return "[red]✗[-] " + model.CleanStation(s.Name) + " [dim](avoided)[-]"
+		}
+		return "  " + model.CleanStation(s.Name)
+	}
+
+	if len(stations) == 0 {
+		list.AddItem("[dim]No changes or stops to avoid on this journey[-]", "", 0, nil)
+	}
+	for _, s := range stations {
+		list.AddItem(label(s), "", 0, nil)
+	}
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		if i >= len(stations) {
+			return
+		}
+		s := stations[i]
+		avoided := a.config.Avoid.ToggleStation(s)
+		config.Save(a.config)
+		slog.Info("avoid station", "station", s.Name, "avoided", avoided)
+		list.SetItemText(i, label(s), "")
+		if avoided {
+			a.statusMsg = "✗ Avoiding " + model.CleanStation(s.Name)
+		} else {
+			a.statusMsg = "No longer avoiding " + model.CleanStation(s.Name)
+		}
+		a.statusMsgFrame = 30
+		a.refresh()
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'b' {
+			back()
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("avoid", list, true, false)
+	a.pages.SwitchToPage("avoid")
+	a.app.SetFocus(list)
+}
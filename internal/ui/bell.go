@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// ring signals a critical event with the bell configured for it
+func (a *App) ring(event string) {
+	switch a.config.Bell.Mode(event) {
+	case config.BellAudible:
+		if a.screen != nil {
+			a.screen.Beep()
+		}
+	case config.BellVisual:
+		a.visualBellFrame = 6
+		a.dirty = true
+	}
+}
+
+// checkDepartureBell rings once when the tracked journey is about to leave
+func (a *App) checkDepartureBell() {
+	j, ok := a.trackedJourney()
+	if !ok {
+		return
+	}
+	until := time.Until(j.LeaveAt)
+	id := model.JourneyID(j)
+	if until > 0 && until < 2*time.Minute && a.departureRungFor != id {
+		a.departureRungFor = id
+		a.ring("departure")
+	}
+}
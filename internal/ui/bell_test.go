@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestCheckDepartureBell(t *testing.T) {
+	tests := []struct {
+		name    string
+		leaveIn time.Duration
+		want    bool
+	}{
+		{"about to leave", 90 * time.Second, true},
+		{"plenty of time", 5 * time.Minute, false},
+		{"gone", -30 * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := model.Journey{LeaveAt: time.Now().Add(tt.leaveIn), Legs: []model.Leg{{Line: "S5", TripID: "1|S5"}}}
+			a := &App{journeys: []model.Journey{j}}
+			a.config.Bell.Departure = config.BellVisual
+
+			a.checkDepartureBell()
+			if got := a.visualBellFrame > 0; got != tt.want {
+				t.Fatalf("rang %v, want %v", got, tt.want)
+			}
+
+			// Once per journey
+			a.visualBellFrame = 0
+			a.checkDepartureBell()
+			if a.visualBellFrame > 0 {
+				t.Error("rang twice")
+			}
+		})
+	}
+}
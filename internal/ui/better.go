@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/model"
+)
+
+// beats reports whether j is better than current in both ways: it leaves
+// later and still arrives sooner
+func beats(j, current model.Journey) bool {
+	return !j.Cancelled() && j.LeaveAt.After(current.LeaveAt) && j.ArriveAt.Before(current.ArriveAt)
+}
+
+// checkBetter marks the journeys that beat the pinned one, or the selected
+// one without a pin, and points out the best of them once
+func (a *App) checkBetter(journeys []model.Journey, currentID string) {
+	a.better = nil
+	var current *model.Journey
+	for i := range journeys {
+		if model.JourneyID(journeys[i]) == currentID {
+			current = &journeys[i]
+		}
+	}
+	if current == nil {
+		return
+	}
+	var best *model.Journey
+	for i, j := range journeys {
+		if !beats(j, *current) {
+			continue
+		}
+		if a.better == nil {
+			a.better = make(map[string]bool)
+		}
+		a.better[model.JourneyID(j)] = true
+		if best == nil || j.ArriveAt.Before(best.ArriveAt) {
+			best = &journeys[i]
+		}
+	}
+	if best == nil {
+		return
+	}
+	key := currentID + "|better|" + model.JourneyID(*best)
+	if a.riskAlerted[key] {
+		return
+	}
+	a.riskAlerted[key] = true
+
+	msg := fmt.Sprintf("Leave at %s on the %s instead and arrive at %s, %d min sooner",
+		model.FormatTime(best.LeaveAt), best.Legs[0].Line, model.FormatTime(best.ArriveAt),
+		int(current.ArriveAt.Sub(best.ArriveAt).Minutes()))
+	a.ring("better")
+	a.statusMsg = "▲ " + msg
+	a.statusMsgFrame = 100
+	// Only a pinned journey is worth a notification, the selection moves too casually
+	if notify := a.config.Notify; notify.BetterJourney && currentID == a.pinnedID {
+		alerts := []alert.Alert{{
+			Kind:    "better",
+			Title:   "Better journey",
+			Message: msg,
+			Line:    best.Legs[0].Line,
+			Route:   model.RouteName(a.config.LastOrigin, a.config.LastDest),
+			Time:    time.Now(),
+		}}
+		a.goSafe(func() { alert.Dispatch(notify, alerts) })
+	}
+}
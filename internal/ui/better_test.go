@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestBeats(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	journey := func(leave, arrive int, legs ...model.Leg) model.Journey {
+		return model.Journey{
+			LeaveAt:  now.Add(time.Duration(leave) * time.Minute),
+			ArriveAt: now.Add(time.Duration(arrive) * time.Minute),
+			Legs:     legs,
+		}
+	}
+	current := journey(5, 35)
+
+	tests := []struct {
+		name string
+		j    model.Journey
+		want bool
+	}{
+		{"later and sooner", journey(8, 30), true},
+		{"later, same arrival", journey(8, 35), false},
+		{"same departure, sooner", journey(5, 30), false},
+		{"earlier and sooner", journey(2, 30), false},
+		{"later and later", journey(10, 40), false},
+		{"cancelled", journey(8, 30, model.Leg{Cancelled: true}), false},
+		{"itself", current, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := beats(tt.j, current); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
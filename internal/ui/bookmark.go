@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// bookmarkMaxAge is how often bookmarked journeys are fetched again by
+// their refresh token
+const bookmarkMaxAge = time.Minute
+
+// toggleBookmark keeps the selected journey for the rest of its day, or
+// lets it go
+func (a *App) toggleBookmark() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	id := model.JourneyID(j)
+	if a.config.Bookmarked(id) {
+		a.config.Bookmarks = slices.DeleteFunc(a.config.Bookmarks, func(b config.Bookmark) bool { return b.ID == id })
+		a.statusMsg = "Bookmark removed"
+	} else {
+		a.config.Bookmarks = append(a.config.Bookmarks, config.Bookmark{
+			ID:      id,
+			Route:   model.RouteName(a.config.LastOrigin, a.config.LastDest),
+			Journey: j,
+		})
+		a.statusMsg = fmt.Sprintf("🔖 Bookmarked %s %s for today", j.Legs[0].Line, model.FormatTime(j.LeaveAt))
+	}
+	config.Save(a.config)
+	a.statusMsgFrame = 30
+}
+
+// restoreBookmarks drops yesterday's bookmarks at startup and pins the
+// next bookmarked journey, so alarms follow it again
+func (a *App) restoreBookmarks() {
+	a.config.DropOldBookmarks(time.Now())
+	if b, ok := a.nextBookmark(time.Now()); ok && a.pinnedID == "" {
+		a.pinnedID = b.ID
+	}
+}
+
+// nextBookmark is the bookmarked journey arriving soonest that hasn't
+// arrived yet
+func (a *App) nextBookmark(now time.Time) (config.Bookmark, bool) {
+	var next config.Bookmark
+	found := false
+	for _, b := range a.config.Bookmarks {
+		if b.Journey.ArriveAt.After(now) && (!found || b.Journey.ArriveAt.Before(next.Journey.ArriveAt)) {
+			next, found = b, true
+		}
+	}
+	return next, found
+}
+
+// noteBookmarks takes the latest state of bookmarked journeys that came
+// along with the list
+func (a *App) noteBookmarks(journeys []model.Journey) {
+	for i, b := range a.config.Bookmarks {
+		for _, j := range journeys {
+			if model.JourneyID(j) == b.ID {
+				a.config.Bookmarks[i].Journey = j
+			}
+		}
+	}
+}
+
+// pollBookmarks fetches the bookmarked journeys not yet arrived again by
+// their refresh tokens, wherever the list is
+func (a *App) pollBookmarks() {
+	refresher, ok := a.client.(vbb.Refresher)
+	if !ok || a.bookmarksFetching || time.Since(a.bookmarksFetched) < bookmarkMaxAge {
+		return
+	}
+	now := time.Now()
+	tokens := map[string]string{}
+	for _, b := range a.config.Bookmarks {
+		if b.Journey.RefreshToken != "" && b.Journey.ArriveAt.After(now.Add(-time.Hour)) {
+			tokens[b.ID] = b.Journey.RefreshToken
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	a.bookmarksFetching = true
+	a.goSafe(func() {
+		fresh := map[string]model.Journey{}
+		for id, token := range tokens {
+			j, err := refresher.RefreshJourney(a.ctx, token)
+			if err != nil {
+				slog.Warn("bookmark refresh failed", "journey", id, "err", err)
+				continue
+			}
+			fresh[id] = j
+		}
+		a.app.QueueUpdateDraw(func() {
+			a.bookmarksFetching = false
+			a.bookmarksFetched = time.Now()
+			for i, b := range a.config.Bookmarks {
+				if j, ok := fresh[b.ID]; ok {
+					a.config.Bookmarks[i].Journey = j
+				}
+			}
+		})
+	})
+}
+
+// bookmarkStatus is how the next bookmarked journey is doing, for the header
+func (a *App) bookmarkStatus(now time.Time) string {
+	b, ok := a.nextBookmark(now)
+	if !ok {
+		return ""
+	}
+	j := b.Journey
+	first := j.Legs[0]
+	label := fmt.Sprintf("🔖 %s %s", first.Line, model.FormatTime(j.LeaveAt))
+	switch {
+	case j.Cancelled():
+		return fmt.Sprintf("  [red]%s ✗ cancelled[-]", label)
+	case now.After(j.LeaveAt):
+		return fmt.Sprintf("  [cyan]%s[-] [dim]arrives %s[-]", label, model.FormatTime(j.ArriveAt))
+	}
+	status := fmt.Sprintf("  [cyan]%s[-] %s", label, formatCountdown(j.LeaveAt.Sub(now)))
+	if first.DepDelay >= 60 {
+		status += fmt.Sprintf(" [yellow]+%d[-]", first.DepDelay/60)
+	}
+	if first.DepPlatform != "" {
+		status += fmt.Sprintf(" [dim]Pl. %s[-]", first.DepPlatform)
+	}
+	return status
+}
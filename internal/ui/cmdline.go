@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxCommandHistory is how many command lines Up and Down go back through
+const maxCommandHistory = 50
+
+// newCommandLine is the ':' line above the legend, hidden until opened.
+// Tab completes command names, Up and Down go through earlier commands.
+func (a *App) newCommandLine() *tview.InputField {
+	input := tview.NewInputField().SetLabel(":")
+	input.SetAutocompleteFunc(func(text string) []string {
+		if text == "" || strings.Contains(text, " ") {
+			return nil
+		}
+		var names []string
+		for _, name := range commandNames() {
+			if strings.HasPrefix(name, text) {
+				names = append(names, name+" ")
+			}
+		}
+		return names
+	})
+
+	browse := -1
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if browse+1 < len(a.cmdHistory) {
+				browse++
+				input.SetText(a.cmdHistory[len(a.cmdHistory)-1-browse])
+			}
+			return nil
+		case tcell.KeyDown:
+			if browse > 0 {
+				browse--
+				input.SetText(a.cmdHistory[len(a.cmdHistory)-1-browse])
+			} else {
+				browse = -1
+				input.SetText("")
+			}
+			return nil
+		}
+		return event
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		line := strings.TrimSpace(input.GetText())
+		browse = -1
+		a.hideCommandLine()
+		if key != tcell.KeyEnter || line == "" {
+			return
+		}
+		if n := len(a.cmdHistory); n == 0 || a.cmdHistory[n-1] != line {
+			a.cmdHistory = append(a.cmdHistory, line)
+			if len(a.cmdHistory) > maxCommandHistory {
+				a.cmdHistory = a.cmdHistory[1:]
+			}
+		}
+		slog.Info("command", "line", line)
+		if err := a.runCommand(line); err != nil {
+			a.statusMsg = "⚠ " + err.Error()
+			a.statusMsgFrame = 50
+		}
+	})
+	return input
+}
+
+// showCommandLine opens the ':' line
+func (a *App) showCommandLine() {
+	a.cmdLine.SetText("")
+	a.mainFlex.ResizeItem(a.cmdLine, 1, 0)
+	a.app.SetFocus(a.cmdLine)
+}
+
+func (a *App) hideCommandLine() {
+	a.mainFlex.ResizeItem(a.cmdLine, 0, 0)
+	a.app.SetFocus(a.list)
+	a.dirty = true
+}
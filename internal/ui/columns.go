@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Columns a journey row can show, configured with "columns" in that order.
+// The badges (reliability, fares, delays, warnings) always follow them.
+var columnNames = []string{"leave", "arrive", "duration", "wait", "walk", "transfers", "countdown", "occupancy", "lines"}
+
+// defaultColumns is the row as it has always looked, the lines on a
+// second row below it
+var defaultColumns = []string{"leave", "arrive", "duration", "wait", "walk", "countdown", "occupancy"}
+
+// plainColumns take the row's status color; the others bring their own
+var plainColumns = []string{"leave", "arrive", "duration", "wait", "walk", "transfers"}
+
+// columns is the configured row layout without unknown names, else the
+// default one
+func (a *App) columns() []string {
+	var cols []string
+	for _, c := range a.config.Columns {
+		if slices.Contains(columnNames, c) {
+			cols = append(cols, c)
+		}
+	}
+	if len(cols) == 0 {
+		return defaultColumns
+	}
+	return cols
+}
+
+// validateColumns rejects unknown column names, listing the known ones
+func validateColumns(cols []string) error {
+	for _, c := range cols {
+		if !slices.Contains(columnNames, c) {
+			return fmt.Errorf("unknown column %q in config, use %s", c, strings.Join(columnNames, ", "))
+		}
+	}
+	return nil
+}
+
+// columnLabels head the columns in the list's sticky header row
+var columnLabels = map[string]string{
+	"leave":     "Leave",
+	"arrive":    "Arrive",
+	"duration":  "Dur",
+	"wait":      "Wait",
+	"walk":      "Walk",
+	"transfers": "Chg",
+	"countdown": "Status",
+	"occupancy": "Occ",
+	"lines":     "Lines",
+}
+
+// columnSeparator goes before a column unless it starts the row, blank
+// when the column is
+func columnSeparator(col string, filled bool) string {
+	if col == "arrive" && filled {
+		return " → "
+	}
+	if col == "arrive" {
+		return "   "
+	}
+	return "  "
+}
+
+// padCell fills a cell up to its column's width
+func padCell(cell string, width int) string {
+	return cell + strings.Repeat(" ", max(width-tview.TaggedStringWidth(cell), 0))
+}
+
+// columnHeader is the header row over the journeys, each label as wide
+// as its column. digits is how wide the journey numbers get.
+func columnHeader(columns []string, widths map[string]int, digits int) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]" + strings.Repeat(" ", 3+digits+2))
+	for ci, col := range columns {
+		label := columnLabels[col]
+		if ci > 0 {
+			sb.WriteString(columnSeparator(col, false))
+		}
+		if ci < len(columns)-1 {
+			sb.WriteString(padCell(label, widths[col]))
+		} else {
+			sb.WriteString(label)
+		}
+	}
+	sb.WriteString("[-:-:-]")
+	return sb.String()
+}
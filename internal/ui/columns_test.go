@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"go-commute/internal/config"
+)
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured []string
+		want       []string
+	}{
+		{"none", nil, defaultColumns},
+		{"own order", []string{"lines", "leave", "transfers"}, []string{"lines", "leave", "transfers"}},
+		{"unknown ones left out", []string{"leave", "platform", "arrive"}, []string{"leave", "arrive"}},
+		{"only unknown ones", []string{"platform"}, defaultColumns},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{config: config.Config{Columns: tt.configured}}
+			if got := a.columns(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	tests := []struct {
+		cols []string
+		bad  string // the column named in the error, empty when valid
+	}{
+		{nil, ""},
+		{columnNames, ""},
+		{[]string{"leave", "Arrive"}, `"Arrive"`},
+		{[]string{"platform", "leave"}, `"platform"`},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.cols, ","), func(t *testing.T) {
+			err := validateColumns(tt.cols)
+			if (err == nil) != (tt.bad == "") {
+				t.Fatalf("err = %v, want one about %s", err, tt.bad)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.bad) {
+				t.Errorf("err = %v, want one about %s", err, tt.bad)
+			}
+		})
+	}
+}
+
+func TestColumnSeparator(t *testing.T) {
+	tests := []struct {
+		col    string
+		filled bool
+		want   string
+	}{
+		{"arrive", true, " → "},
+		{"arrive", false, "   "},
+		{"duration", true, "  "},
+		{"lines", false, "  "},
+	}
+	for _, tt := range tests {
+		if got := columnSeparator(tt.col, tt.filled); got != tt.want {
+			t.Errorf("columnSeparator(%q, %v) = %q, want %q", tt.col, tt.filled, got, tt.want)
+		}
+	}
+}
+
+func TestColumnHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		widths  map[string]int
+		digits  int
+		want    string
+	}{
+		{"default", []string{"leave", "arrive", "duration"}, map[string]int{"leave": 5, "arrive": 5, "duration": 6}, 1,
+			"[::b]      Leave   Arrive  Dur[-:-:-]"},
+		{"wide cells", []string{"leave", "arrive", "duration"}, map[string]int{"leave": 8, "arrive": 8, "duration": 6}, 1,
+			"[::b]      Leave      Arrive    Dur[-:-:-]"},
+		{"ten journeys and more", []string{"leave", "duration"}, map[string]int{"leave": 5, "duration": 6}, 2,
+			"[::b]       Leave  Dur[-:-:-]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnHeader(tt.columns, tt.widths, tt.digits); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/provider"
+	"go-commute/internal/vbb"
+)
+
+// command is an internal action that macros and the command line can run
+// by name, with what it takes after the name
+type command struct {
+	args string
+	help string
+	run  func(a *App, arg string) error
+}
+
+var commands = map[string]command{
+	"favorite": {"N|NAME", "load favorite N or the one whose name matches", (*App).favoriteCommand},
+	"transfers": {"N", "limit the changes, 0 for direct only", func(a *App, arg string) error {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("transfers takes a number, not %q", arg)
+		}
+		a.setTransfers(n)
+		return nil
+	}},
+	"sort": {"MODE", "sort by " + strings.Join(sortModes, ", "), func(a *App, arg string) error {
+		if !slices.Contains(sortModes, arg) {
+			return fmt.Errorf("unknown sort %q (available: %v)", arg, sortModes)
+		}
+		a.setSort(arg)
+		a.rememberView()
+		return nil
+	}},
+	"from": {"STATION", "plan from a station, alias or LAT,LON", func(a *App, arg string) error {
+		return a.routeCommand("origin", arg)
+	}},
+	"to": {"STATION", "plan to a station or alias", func(a *App, arg string) error {
+		return a.routeCommand("dest", arg)
+	}},
+	"depart": {"WHEN", "leave at 08:30, tomorrow 8:00, next mon or now", func(a *App, arg string) error {
+		at, err := parseWhen(arg, time.Now())
+		if err != nil {
+			return err
+		}
+		a.setDeparture(at)
+		return nil
+	}},
+	"filter":       {"[+|-]PRODUCT...", "include or leave out products, only the bare ones given, or all", (*App).filterCommand},
+	"theme":        {"NAME", "color the products by " + strings.Join(config.PaletteNames(), ", ") + " or default", (*App).themeCommand},
+	"refresh":      {"", "fetch the journeys again", noArgs((*App).refresh)},
+	"swap":         {"", "swap origin and destination", noArgs((*App).swapRoute)},
+	"home":         {"", "plan home from here", noArgs((*App).goHome)},
+	"pin":          {"", "pin or unpin the selected journey", noArgs((*App).togglePin)},
+	"longdistance": {"", "hide or include long-distance trains", noArgs((*App).toggleLongDistance)},
+	"departed":     {"", "show or put away journeys that have left", noArgs((*App).toggleDeparted)},
+	"split":        {"", "the departures and arrivals side by side", noArgs((*App).showSplit)},
+}
+
+func noArgs(f func(a *App)) func(a *App, arg string) error {
+	return func(a *App, arg string) error {
+		if arg != "" {
+			return fmt.Errorf("takes no argument, got %q", arg)
+		}
+		f(a)
+		return nil
+	}
+}
+
+// commandNames lists the commands for error messages and help
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseCommand splits "transfers 1" into the command and its argument
+func parseCommand(line string) (command, string, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	cmd, ok := commands[name]
+	if !ok {
+		return command{}, "", fmt.Errorf("unknown command %q (available: %v)", name, commandNames())
+	}
+	return cmd, strings.TrimSpace(arg), nil
+}
+
+// runCommand runs one command line on the event loop
+func (a *App) runCommand(line string) error {
+	cmd, arg, err := parseCommand(line)
+	if err != nil {
+		return err
+	}
+	if err := cmd.run(a, arg); err != nil {
+		name, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// favoriteCommand loads a favorite by its number, counting from 1, or by
+// a case-insensitive part of its name as the favorites page shows it
+func (a *App) favoriteCommand(arg string) error {
+	if arg == "" {
+		return fmt.Errorf("which favorite?")
+	}
+	if n, err := strconv.Atoi(arg); err == nil {
+		if n < 1 || n > len(a.config.Routes) {
+			return fmt.Errorf("no favorite %d", n)
+		}
+		a.loadFavorite(n - 1)
+		return nil
+	}
+	match := -1
+	for i, fav := range a.config.Routes {
+		name := a.config.Label(fav.Origin) + " → " + a.config.Label(fav.Dest)
+		if !strings.Contains(strings.ToLower(name), strings.ToLower(arg)) {
+			continue
+		}
+		if match >= 0 {
+			return fmt.Errorf("%q matches more than one favorite", arg)
+		}
+		match = i
+	}
+	if match < 0 {
+		return fmt.Errorf("no favorite matches %q", arg)
+	}
+	a.loadFavorite(match)
+	return nil
+}
+
+// swapRoute plans the way back
+func (a *App) swapRoute() {
+	if a.config.LastOrigin.IsAddress() {
+		a.statusMsg = "Can't plan to an address, only from one"
+		a.statusMsgFrame = 30
+		return
+	}
+	a.config.LastOrigin, a.config.LastDest = a.config.LastDest, a.config.LastOrigin
+	config.Save(a.config)
+	a.refresh()
+}
+
+// routeCommand sets the origin or the destination like picking it in the
+// search does, looking stations up in the background like --from does
+func (a *App) routeCommand(target, query string) error {
+	if query == "" {
+		return fmt.Errorf("which station?")
+	}
+	set := func(station model.Station) {
+		if target == "origin" {
+			a.config.LastOrigin = station
+		} else {
+			a.config.LastDest = station
+		}
+		slog.Info("route selected", "route", model.RouteName(a.config.LastOrigin, a.config.LastDest))
+		config.Save(a.config)
+		a.refresh()
+	}
+
+	name, ok := a.config.AliasTarget(query)
+	if !ok {
+		name = query
+	}
+	if at, err := model.ParseCoordinates(name); err == nil {
+		if target != "origin" {
+			return fmt.Errorf("journeys can only start at an address, not end there")
+		}
+		set(model.AddressAt(query, at))
+		return nil
+	}
+	a.goSafe(func() {
+		station, err := vbb.Resolve(a.ctx, a.client, name)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.statusMsg = fmt.Sprintf("⚠ %s: %v", query, err)
+				a.statusMsgFrame = 50
+				return
+			}
+			set(station)
+		})
+	})
+	return nil
+}
+
+// filterCommand switches products on with +ID and off with -ID. Bare IDs
+// keep only those, and "all" lets every product back in.
+func (a *App) filterCommand(arg string) error {
+	preset := a.config.Preset()
+	filters, err := filterProducts(preset, a.filters, arg)
+	if err != nil {
+		return err
+	}
+
+	a.filters = filters
+	var off []string
+	longDistance, longDistanceOn := false, false
+	for _, p := range preset.Products {
+		if !filters[p.ID] {
+			off = append(off, p.ID)
+		}
+		if p.LongDistance {
+			longDistance = true
+			longDistanceOn = longDistanceOn || filters[p.ID]
+		}
+	}
+	a.localOnly = longDistance && !longDistanceOn
+	if len(off) == 0 {
+		a.statusMsg = "All products included"
+	} else {
+		a.statusMsg = "Leaving out " + strings.Join(off, ", ")
+	}
+	a.statusMsgFrame = 30
+	a.rememberView()
+	a.refresh()
+	return nil
+}
+
+// filterProducts applies a filter command's argument to the product
+// filters, leaving the ones passed in as they are
+func filterProducts(preset provider.Preset, filters map[string]bool, arg string) (map[string]bool, error) {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("which products?")
+	}
+	filters = maps.Clone(filters)
+	only := false
+	for _, f := range fields {
+		if f == "all" {
+			for _, p := range preset.Products {
+				filters[p.ID] = true
+			}
+			continue
+		}
+		on := !strings.HasPrefix(f, "-")
+		id := strings.TrimLeft(f, "+-")
+		if _, ok := preset.Product(id); !ok {
+			var ids []string
+			for _, p := range preset.Products {
+				ids = append(ids, p.ID)
+			}
+			return nil, fmt.Errorf("unknown product %q, %s has %s", id, preset.Name, strings.Join(ids, ", "))
+		}
+		if !strings.ContainsAny(f[:1], "+-") && !only {
+			// The first bare product turns off everything not named
+			only = true
+			for _, p := range preset.Products {
+				filters[p.ID] = false
+			}
+		}
+		filters[id] = on
+	}
+	return filters, nil
+}
+
+// themeCommand recolors the products with a palette and keeps it
+func (a *App) themeCommand(arg string) error {
+	if arg == "default" {
+		arg = ""
+	} else if !slices.Contains(config.PaletteNames(), arg) {
+		return fmt.Errorf("unknown theme %q (available: %v)", arg, append(config.PaletteNames(), "default"))
+	}
+	a.config.Palette = arg
+	config.Save(a.config)
+	if arg == "" {
+		a.statusMsg = "Theme: default"
+	} else {
+		a.statusMsg = "Theme: " + arg
+	}
+	a.statusMsgFrame = 30
+	return nil
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"go-commute/internal/provider"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		arg  string
+		ok   bool
+	}{
+		{"transfers 1", "1", true},
+		{"  from   Alexanderplatz  ", "Alexanderplatz", true},
+		{"depart tomorrow 8:00", "tomorrow 8:00", true},
+		{"refresh", "", true},
+		{"", "", false},
+		{"Refresh", "", false},
+		{"reload", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			_, arg, err := parseCommand(tt.line)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if arg != tt.arg {
+				t.Errorf("arg = %q, want %q", arg, tt.arg)
+			}
+		})
+	}
+}
+
+func TestFilterProducts(t *testing.T) {
+	preset, err := provider.Lookup("vbb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := map[string]bool{}
+	for _, p := range preset.Products {
+		all[p.ID] = true
+	}
+	without := func(filters map[string]bool, ids ...string) map[string]bool {
+		filters = maps.Clone(filters)
+		for _, id := range ids {
+			filters[id] = false
+		}
+		return filters
+	}
+	only := func(ids ...string) map[string]bool {
+		filters := map[string]bool{}
+		for id := range all {
+			filters[id] = slices.Contains(ids, id)
+		}
+		return filters
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]bool
+		arg     string
+		want    map[string]bool // nil for an error
+	}{
+		{"leave out", all, "-bus -tram", without(all, "bus", "tram")},
+		{"let back in", without(all, "bus"), "+bus", all},
+		{"only the bare ones", all, "suburban subway", only("suburban", "subway")},
+		{"bare and signed", without(all, "bus"), "suburban -subway +bus", only("suburban", "bus")},
+		{"all", only("bus"), "all", all},
+		{"all but", only("bus"), "all -express", without(all, "express")},
+		{"unknown product", all, "-zeppelin", nil},
+		{"nothing", all, " ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := maps.Clone(tt.filters)
+			got, err := filterProducts(preset, tt.filters, tt.arg)
+			if (err == nil) != (tt.want != nil) {
+				t.Fatalf("err = %v, want ok %v", err, tt.want != nil)
+			}
+			if tt.want != nil && !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.filters, before) {
+				t.Errorf("changed the filters passed in to %v", tt.filters)
+			}
+		})
+	}
+}
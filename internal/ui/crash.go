@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Crash is returned by Run when a goroutine panicked. The application has
+// been stopped by then, so the terminal is usable for printing the trace.
+type Crash struct {
+	Value any
+	Stack []byte
+}
+
+func (c *Crash) Error() string {
+	return fmt.Sprintf("panic: %v", c.Value)
+}
+
+// crashState keeps the first panic; later ones are usually fallout from it
+type crashState struct {
+	once  sync.Once
+	crash *Crash
+}
+
+// recoverPanic must be deferred directly by every goroutine the App starts.
+// It records the panic and stops tview, which restores the terminal.
+func (a *App) recoverPanic() {
+	if p := recover(); p != nil {
+		a.crashed.once.Do(func() {
+			a.crashed.crash = &Crash{Value: p, Stack: debug.Stack()}
+		})
+		a.app.Stop()
+	}
+}
+
+// goSafe runs f in a goroutine that stops the app instead of killing the
+// process with the terminal still in raw mode
+func (a *App) goSafe(f func()) {
+	go func() {
+		defer a.recoverPanic()
+		f()
+	}()
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// connectionDanger is the change at the end of the leg being ridden when its
+// latest expected arrival leaves less than the transfer margin, with the
+// next departure of the same line that can still be made there
+type connectionDanger struct {
+	key     string // journey and the leg changed into
+	leg     int
+	arrive  time.Time // expected arrival at the change
+	departs time.Time // the next leg's departure
+
+	alt      *model.Departure
+	fetched  time.Time
+	fetching bool
+}
+
+// message spells out the arithmetic, and the way out once it's looked up
+func (d *connectionDanger) message(next model.Leg) string {
+	msg := fmt.Sprintf("Connection in danger: arriving %s, %s departs %s",
+		model.FormatTime(d.arrive), next.Line, model.FormatTime(d.departs))
+	if d.alt != nil {
+		msg += fmt.Sprintf(", next %s at %s", d.alt.Line, model.FormatTime(d.alt.When))
+		if d.alt.Platform != "" {
+			msg += " from Pl. " + d.alt.Platform
+		}
+	}
+	return msg
+}
+
+// checkConnectionDanger recomputes the arrival at the next change from
+// the trip of the leg being ridden, and warns once when it no longer leaves
+// enough time to change
+func (a *App) checkConnectionDanger() {
+	j, ok := a.trackedJourney()
+	if !ok {
+		a.danger = nil
+		return
+	}
+	now := time.Now()
+	cur := -1
+	for i, leg := range j.Legs {
+		if !now.Before(leg.Departure) && now.Before(leg.Arrival) {
+			cur = i
+			break
+		}
+	}
+	if cur < 0 || cur == len(j.Legs)-1 {
+		a.danger = nil
+		return
+	}
+
+	leg, next := j.Legs[cur], j.Legs[cur+1]
+	arrive, delay := a.expectedArrival(leg)
+	buffer := a.config.TransferMargin()
+	if delay <= 0 || next.Departure.Sub(arrive) >= buffer {
+		a.danger = nil
+		return
+	}
+
+	key := fmt.Sprintf("%s|%d", model.JourneyID(j), cur+1)
+	if a.danger == nil || a.danger.key != key {
+		a.danger = &connectionDanger{key: key, leg: cur + 1}
+	}
+	d := a.danger
+	if !d.arrive.Equal(arrive) {
+		d.arrive, d.departs = arrive, next.Departure
+		a.dirty = true
+	}
+	if !d.fetching && time.Since(d.fetched) > tripMaxAge {
+		a.fetchAlternative(d, next, buffer)
+	}
+
+	if !a.riskAlerted[key+"|eta"] {
+		a.riskAlerted[key+"|eta"] = true
+		a.ring("risk")
+		a.alarmFrame = 50
+		a.statusMsg = "⚠ " + d.message(next)
+		a.statusMsgFrame = 100
+	}
+}
+
+// expectedArrival is when the leg gets to its last stop by the freshest
+// data, its trip's stopovers once fetched, and how late that is
+func (a *App) expectedArrival(leg model.Leg) (time.Time, int) {
+	if leg.TripID != "" {
+		trip, _ := a.cachedTrip(leg.TripID, tripMaxAge)
+		for _, s := range trip.Stopovers {
+			if s.Station.ID == leg.ToID && !s.Arrival.IsZero() {
+				return s.Arrival, s.ArrDelay
+			}
+		}
+	}
+	return leg.Arrival, leg.ArrDelay
+}
+
+// fetchAlternative looks up the first departure of the next leg's line in
+// the same direction that leaves time enough to change
+func (a *App) fetchAlternative(d *connectionDanger, next model.Leg, buffer time.Duration) {
+	if next.FromID == "" {
+		return
+	}
+	d.fetching = true
+	a.goSafe(func() {
+		deps, err := a.client.Departures(a.ctx, next.FromID)
+		a.app.QueueUpdateDraw(func() {
+			d.fetching = false
+			d.fetched = time.Now()
+			if err != nil {
+				slog.Warn("alternative lookup failed", "stop", next.FromID, "err", err)
+				return
+			}
+			prev := d.alt
+			d.alt = alternative(deps, next, d.arrive.Add(buffer))
+			if d.alt != nil && a.danger == d && (prev == nil || !prev.When.Equal(d.alt.When)) {
+				a.statusMsg = "⚠ " + d.message(next)
+				a.statusMsgFrame = 100
+			}
+		})
+	})
+}
+
+// alternative is the first departure of the next leg's line in its
+// direction, other than the leg's own trip, that leaves no earlier than after
+func alternative(deps []model.Departure, next model.Leg, after time.Time) *model.Departure {
+	for i, dep := range deps {
+		if dep.Cancelled || dep.Line != next.Line || dep.TripID == next.TripID ||
+			(next.Direction != "" && dep.Direction != next.Direction) {
+			continue
+		}
+		if !dep.When.Before(after) {
+			return &deps[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestAlternative(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2026, 10, 16, 8, minute, 0, 0, model.DisplayZone)
+	}
+	next := model.Leg{Line: "U2", TripID: "1|U2", Direction: "Ruhleben", Departure: at(14)}
+	u2 := func(trip string, minute int) model.Departure {
+		return model.Departure{TripID: trip, Line: "U2", Direction: "Ruhleben", When: at(minute)}
+	}
+	cancelled := u2("3|U2", 19)
+	cancelled.Cancelled = true
+	otherWay := u2("4|U2", 19)
+	otherWay.Direction = "Pankow"
+	u5 := model.Departure{TripID: "5|U5", Line: "U5", Direction: "Hönow", When: at(19)}
+
+	tests := []struct {
+		name  string
+		deps  []model.Departure
+		after time.Time
+		want  string // trip, empty for none
+	}{
+		{"the next one", []model.Departure{u2("1|U2", 14), u2("2|U2", 19), u2("6|U2", 24)}, at(17), "2|U2"},
+		{"just made", []model.Departure{u2("2|U2", 19)}, at(19), "2|U2"},
+		{"not the missed trip itself", []model.Departure{u2("1|U2", 19), u2("2|U2", 24)}, at(17), "2|U2"},
+		{"skips cancelled, other lines and the other way",
+			[]model.Departure{cancelled, otherWay, u5, u2("6|U2", 24)}, at(17), "6|U2"},
+		{"none late enough", []model.Departure{u2("1|U2", 14), u2("2|U2", 16)}, at(17), ""},
+		{"no departures", nil, at(17), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alternative(tt.deps, next, tt.after)
+			switch {
+			case got == nil && tt.want != "":
+				t.Errorf("got none, want %s", tt.want)
+			case got != nil && got.TripID != tt.want:
+				t.Errorf("got %s, want %q", got.TripID, tt.want)
+			}
+		})
+	}
+
+	// Without a direction on the leg any direction does
+	if got := alternative([]model.Departure{otherWay}, model.Leg{Line: "U2"}, at(17)); got == nil {
+		t.Error("a leg without a direction takes no departure")
+	}
+}
+
+func TestDangerMessage(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2026, 10, 16, 8, minute, 0, 0, model.DisplayZone)
+	}
+	next := model.Leg{Line: "U2", Departure: at(14)}
+	tests := []struct {
+		name string
+		alt  *model.Departure
+		want string
+	}{
+		{"not looked up yet", nil, "Connection in danger: arriving 08:13, U2 departs 08:14"},
+		{"next one", &model.Departure{Line: "U2", When: at(19)},
+			"Connection in danger: arriving 08:13, U2 departs 08:14, next U2 at 08:19"},
+		{"with its platform", &model.Departure{Line: "U2", When: at(19), Platform: "2"},
+			"Connection in danger: arriving 08:13, U2 departs 08:14, next U2 at 08:19 from Pl. 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &connectionDanger{arrive: at(13), departs: at(14), alt: tt.alt}
+			if got := d.message(next); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
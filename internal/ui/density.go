@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"slices"
+
+	"go-commute/internal/config"
+)
+
+// densities trade the route diagram and separators for more journeys on
+// screen: low draws both, medium drops the separators, high leaves each
+// journey a single row
+var densities = []string{"low", "medium", "high"}
+
+// density is the configured list density, low if unset or unknown
+func (a *App) density() string {
+	if slices.Contains(densities, a.config.Density) {
+		return a.config.Density
+	}
+	return densities[0]
+}
+
+// cycleDensity switches to the next density and remembers it
+func (a *App) cycleDensity() {
+	next := densities[(slices.Index(densities, a.density())+1)%len(densities)]
+	a.config.Density = next
+	config.Save(a.config)
+	a.statusMsg = "Density: " + next
+	a.statusMsgFrame = 30
+	a.renderList()
+}
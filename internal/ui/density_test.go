@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"testing"
+
+	"go-commute/internal/config"
+)
+
+func TestDensity(t *testing.T) {
+	tests := []struct{ configured, want string }{
+		{"", "low"},
+		{"low", "low"},
+		{"medium", "medium"},
+		{"high", "high"},
+		{"High", "low"},
+		{"compact", "low"},
+	}
+	for _, tt := range tests {
+		a := &App{config: config.Config{Density: tt.configured}}
+		if got := a.density(); got != tt.want {
+			t.Errorf("density with %q configured = %q, want %q", tt.configured, got, tt.want)
+		}
+	}
+}
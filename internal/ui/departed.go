@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// departedGrace is how long a journey stays listed after it left, for the
+// one running late or the last-second dash
+const departedGrace = 2 * time.Minute
+
+// departedCutoff is the time journeys leaving earlier are put away at,
+// zero when the config keeps them all: they've left, or with
+// hide_uncatchable, the stop can't be reached in time anymore
+func (a *App) departedCutoff(now time.Time) time.Time {
+	switch {
+	case a.config.HideUncatchable && a.config.WalkTime() > 0:
+		return now.Add(a.config.WalkTime())
+	case a.config.Departed != "":
+		return now.Add(-departedGrace)
+	}
+	return time.Time{}
+}
+
+// pruneDeparted moves the journeys that have left out of the list when
+// the config hides or collapses them. The pinned one stays.
+func (a *App) pruneDeparted() {
+	cutoff := a.departedCutoff(time.Now())
+	if cutoff.IsZero() || a.showDeparted {
+		return
+	}
+	var kept []model.Journey
+	for _, j := range a.journeys {
+		if j.LeaveAt.Before(cutoff) && model.JourneyID(j) != a.pinnedID {
+			a.departed = append(a.departed, j)
+		} else {
+			kept = append(kept, j)
+		}
+	}
+	if len(kept) == len(a.journeys) {
+		return
+	}
+	a.replaceJourneys(kept)
+}
+
+// toggleDeparted brings the departed journeys back into the list, or
+// puts them away again
+func (a *App) toggleDeparted() {
+	if a.departedCutoff(time.Now()).IsZero() {
+		return
+	}
+	a.showDeparted = !a.showDeparted
+	if a.showDeparted {
+		journeys := append(a.departed, a.journeys...)
+		a.departed = nil
+		sortJourneys(journeys, a.sortMode)
+		a.replaceJourneys(journeys)
+		a.statusMsg = "Showing departed journeys"
+	} else {
+		a.pruneDeparted()
+		a.statusMsg = "Departed journeys put away"
+	}
+	a.statusMsgFrame = 30
+}
+
+// replaceJourneys swaps the list, keeping the selection on the same
+// journey where it's still listed
+func (a *App) replaceJourneys(journeys []model.Journey) {
+	selected := ""
+	if a.selectedIdx < len(a.journeys) {
+		selected = model.JourneyID(a.journeys[a.selectedIdx])
+	}
+	a.journeys = journeys
+	a.selectedIdx = 0
+	for i := range journeys {
+		if model.JourneyID(journeys[i]) == selected {
+			a.selectedIdx = i
+		}
+	}
+	a.dirty = true
+}
+
+// departedLine is the collapsed departed journeys' row, empty when there
+// are none or they're hidden outright
+func (a *App) departedLine() string {
+	if a.config.Departed != "collapse" || a.showDeparted || len(a.departed) == 0 {
+		return ""
+	}
+	noun := "journeys"
+	if len(a.departed) == 1 {
+		noun = "journey"
+	}
+	return fmt.Sprintf(" [dim]⤴ %d departed %s · 'd' shows them[-]\n", len(a.departed), noun)
+}
+
+// catchBadge marks a journey that has to be left for right now, or can't
+// be made anymore, given the walk to the stop
+func (a *App) catchBadge(j model.Journey, now time.Time) string {
+	walk := a.config.WalkTime()
+	if walk == 0 || j.Cancelled() {
+		return ""
+	}
+	switch slack := j.LeaveAt.Sub(now) - walk; {
+	case slack < 0:
+		return " [dim]too late[-]"
+	case slack < 2*time.Minute:
+		return " [yellow]🏃[-]"
+	}
+	return ""
+}
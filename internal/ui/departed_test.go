@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestPruneDeparted(t *testing.T) {
+	// The first three S5 left two and a half minutes ago or earlier, the
+	// fourth leaves in four and a half
+	all, err := vbb.NewFake(time.Now().Add(-27*time.Minute - 30*time.Second)).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := journeyIDs(all)
+
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		shown    bool
+		pinned   string
+		departed []string
+		line     string
+	}{
+		{name: "kept", cfg: config.Config{}},
+		{name: "hidden", cfg: config.Config{Departed: "hide"}, departed: ids[:3]},
+		{name: "collapsed", cfg: config.Config{Departed: "collapse"}, departed: ids[:3],
+			line: " [dim]⤴ 3 departed journeys · 'd' shows them[-]\n"},
+		{name: "pinned stays", cfg: config.Config{Departed: "collapse"}, pinned: ids[1], departed: []string{ids[0], ids[2]},
+			line: " [dim]⤴ 2 departed journeys · 'd' shows them[-]\n"},
+		{name: "brought back", cfg: config.Config{Departed: "collapse"}, shown: true},
+		{name: "out of reach", cfg: config.Config{HideUncatchable: true, WalkMin: 5}, departed: ids[:4]},
+		{name: "out of reach, walk unknown", cfg: config.Config{HideUncatchable: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{config: tt.cfg, journeys: slices.Clone(all), selectedIdx: 5, showDeparted: tt.shown, pinnedID: tt.pinned}
+			a.pruneDeparted()
+
+			if got := journeyIDs(a.departed); !slices.Equal(got, tt.departed) {
+				t.Errorf("departed %v, want %v", got, tt.departed)
+			}
+			if len(a.journeys)+len(a.departed) != len(all) {
+				t.Errorf("%d listed and %d departed of %d", len(a.journeys), len(a.departed), len(all))
+			}
+			if got := model.JourneyID(a.journeys[a.selectedIdx]); got != ids[5] {
+				t.Errorf("selection moved to %s", got)
+			}
+			if got := a.departedLine(); got != tt.line {
+				t.Errorf("departed line %q, want %q", got, tt.line)
+			}
+		})
+	}
+}
+
+func TestCatchBadge(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	leaving := func(in time.Duration, legs ...model.Leg) model.Journey {
+		return model.Journey{LeaveAt: now.Add(in), Legs: legs}
+	}
+	home := model.Station{ID: "900180001", Name: "S Köpenick (Berlin)"}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		j    model.Journey
+		want string
+	}{
+		{"no walk configured", config.Config{LastOrigin: home}, leaving(time.Minute), ""},
+		{"plenty of time", config.Config{LastOrigin: home, WalkMin: 5}, leaving(10 * time.Minute), ""},
+		{"run", config.Config{LastOrigin: home, WalkMin: 5}, leaving(6 * time.Minute), " [yellow]🏃[-]"},
+		{"too late", config.Config{LastOrigin: home, WalkMin: 5}, leaving(4 * time.Minute), " [dim]too late[-]"},
+		{"cancelled", config.Config{LastOrigin: home, WalkMin: 5}, leaving(6*time.Minute, model.Leg{Cancelled: true}), ""},
+		{"from an address", config.Config{LastOrigin: model.AddressAt("Torstraße 1", model.Coordinates{Latitude: 52.5, Longitude: 13.4}), WalkMin: 5}, leaving(time.Minute), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{config: tt.cfg}
+			if got := a.catchBadge(tt.j, now); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/diary"
+	"go-commute/internal/model"
+)
+
+// markTaken logs the selected journey in the commute diary
+func (a *App) markTaken() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	if a.diary.Add(diary.NewEntry(model.RouteName(a.config.LastOrigin, a.config.LastDest), j)) {
+		a.statusMsg = "✓ Logged in commute diary"
+	} else {
+		a.statusMsg = "✓ Updated diary entry"
+	}
+	a.statusMsgFrame = 30
+	a.goWrite(func() { a.diary.Save() })
+}
+
+// showDiary summarizes the real door-to-door times per route and week
+func (a *App) showDiary() {
+	entries := a.diary.Snapshot()
+
+	var sb strings.Builder
+	if len(entries) == 0 {
+		sb.WriteString("\n [dim]No journeys logged yet. Press 'm' on a journey you take.[-]\n")
+	}
+
+	byRoute := make(map[string][]diary.Entry)
+	var routes []string
+	for _, e := range entries {
+		if _, ok := byRoute[e.Route]; !ok {
+			routes = append(routes, e.Route)
+		}
+		byRoute[e.Route] = append(byRoute[e.Route], e)
+	}
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		es := byRoute[route]
+		var planned, actual, worst time.Duration
+		for _, e := range es {
+			planned += e.PlannedDuration()
+			actual += e.ActualDuration()
+			if late := e.ActualArrival.Sub(e.PlannedArrival); late > worst {
+				worst = late
+			}
+		}
+		n := time.Duration(len(es))
+		sb.WriteString(fmt.Sprintf("[yellow::b]%s[-:-:-]  %d trips\n", route, len(es)))
+		sb.WriteString(fmt.Sprintf("  Planned %dm  |  Actual %dm  |  Avg late %+.1fm  |  Worst +%dm\n",
+			int((planned / n).Minutes()), int((actual / n).Minutes()),
+			(actual-planned).Minutes()/float64(len(es)), int(worst.Minutes())))
+
+		// Weekly breakdown
+		type week struct {
+			label string
+			total time.Duration
+			trips int
+		}
+		weeks := make(map[string]*week)
+		for _, e := range es {
+			y, w := e.PlannedDeparture.ISOWeek()
+			key := fmt.Sprintf("%d-%02d", y, w)
+			if weeks[key] == nil {
+				weeks[key] = &week{label: fmt.Sprintf("%d W%02d", y, w)}
+			}
+			weeks[key].total += e.ActualDuration()
+			weeks[key].trips++
+		}
+		var keys []string
+		for k := range weeks {
+			keys = append(keys, k)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+		if len(keys) > 8 {
+			keys = keys[:8]
+		}
+		for _, k := range keys {
+			w := weeks[k]
+			sb.WriteString(fmt.Sprintf("  [dim]%s[-]  %2d trips  avg %dm\n",
+				w.label, w.trips, int((w.total / time.Duration(w.trips)).Minutes())))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(entries) > 0 {
+		sb.WriteString("[::b]Recent[-:-:-]\n")
+		for i := len(entries) - 1; i >= 0 && i >= len(entries)-10; i-- {
+			e := entries[i]
+			late := e.ActualArrival.Sub(e.PlannedArrival)
+			lateStr := "[green]on time[-]"
+			if late >= time.Minute {
+				lateStr = fmt.Sprintf("[yellow]+%dm[-]", int(late.Minutes()))
+			}
+			sb.WriteString(fmt.Sprintf("  %s  %s → %s  %s  %s\n",
+				e.PlannedDeparture.In(model.DisplayZone).Format("Mon 02.01."), model.FormatTime(e.PlannedDeparture),
+				model.FormatTime(e.ActualArrival), strings.Join(e.Lines, " › "), lateStr))
+		}
+	}
+	sb.WriteString("\n[dim]Press ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Commute Diary ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("diary")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("diary", view, true, false)
+	a.pages.SwitchToPage("diary")
+	a.app.SetFocus(view)
+}
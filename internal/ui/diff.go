@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+)
+
+// journeyChange is what a refresh changed about a journey listed before
+type journeyChange struct {
+	Delay     int      // minutes the departure moved since the last refresh
+	Platforms []string // legs whose platform changed, like "S3 2→4"
+}
+
+// diffJourneys compares a refresh with the one before: how the journeys
+// still listed changed, and which ones that hadn't left yet are gone
+func diffJourneys(prev, next []model.Journey, now time.Time) (map[string]journeyChange, []model.Journey) {
+	before := make(map[string]model.Journey, len(prev))
+	for _, j := range prev {
+		before[model.JourneyID(j)] = j
+	}
+	changes := make(map[string]journeyChange)
+	for _, j := range next {
+		id := model.JourneyID(j)
+		old, ok := before[id]
+		delete(before, id)
+		if !ok || len(old.Legs) != len(j.Legs) {
+			continue
+		}
+		var c journeyChange
+		c.Delay = (j.Legs[0].DepDelay - old.Legs[0].DepDelay) / 60
+		for i, leg := range j.Legs {
+			was := old.Legs[i].DepPlatform
+			if was != "" && leg.DepPlatform != "" && was != leg.DepPlatform {
+				c.Platforms = append(c.Platforms, fmt.Sprintf("%s %s→%s", leg.Line, was, leg.DepPlatform))
+			}
+		}
+		if c.Delay != 0 || len(c.Platforms) > 0 {
+			changes[id] = c
+		}
+	}
+	var gone []model.Journey
+	for _, j := range prev {
+		if _, missing := before[model.JourneyID(j)]; missing && j.LeaveAt.After(now) {
+			gone = append(gone, j)
+		}
+	}
+	return changes, gone
+}
+
+// noteChanges logs what a refresh changed and sums it up in the status line
+func (a *App) noteChanges(route string, changes map[string]journeyChange, gone []model.Journey) {
+	a.changes = changes
+	for id, c := range changes {
+		slog.Info("journey changed", "route", route, "journey", id, "delay_min", c.Delay, "platforms", c.Platforms)
+	}
+	var lines []string
+	for _, j := range gone {
+		lines = append(lines, j.Legs[0].Line+" "+model.FormatTime(j.LeaveAt))
+		slog.Info("journey gone", "route", route, "journey", model.JourneyID(j), "leave", j.LeaveAt)
+	}
+	switch {
+	case len(gone) > 0:
+		a.statusMsg = "No longer listed: " + strings.Join(lines, ", ")
+		a.statusMsgFrame = 50
+	case len(changes) == 1:
+		a.statusMsg = "1 journey changed since the last check"
+		a.statusMsgFrame = 30
+	case len(changes) > 1:
+		a.statusMsg = fmt.Sprintf("%d journeys changed since the last check", len(changes))
+		a.statusMsgFrame = 30
+	}
+}
+
+// changeBadge annotates a row with what the last refresh changed
+func (a *App) changeBadge(j model.Journey) string {
+	c, ok := a.changes[model.JourneyID(j)]
+	if !ok {
+		return ""
+	}
+	var s string
+	switch {
+	case c.Delay > 0:
+		s += fmt.Sprintf(" [yellow]+%d min since last check[-]", c.Delay)
+	case c.Delay < 0:
+		s += fmt.Sprintf(" [green]%d min since last check[-]", c.Delay)
+	}
+	for _, p := range c.Platforms {
+		s += fmt.Sprintf(" [magenta]Pl. %s[-]", tview.Escape(p))
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestDiffJourneys(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	prev, err := vbb.NewFake(now).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev = prev[:6]
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		refresh func(next []model.Journey) []model.Journey
+		changes map[string]journeyChange
+		gone    []string
+	}{
+		{
+			name:    "nothing changed",
+			now:     now,
+			refresh: func(next []model.Journey) []model.Journey { return next },
+		},
+		{
+			name: "later departure",
+			now:  now,
+			refresh: func(next []model.Journey) []model.Journey {
+				next[1].Legs[0].DepDelay += 180
+				return next
+			},
+			changes: map[string]journeyChange{vbb.S5ID(1): {Delay: 3}},
+		},
+		{
+			name: "other platform",
+			now:  now,
+			refresh: func(next []model.Journey) []model.Journey {
+				next[2].Legs[0].DepPlatform = "9"
+				return next
+			},
+			changes: map[string]journeyChange{vbb.S5ID(2): {Platforms: []string{"S5 1→9"}}},
+		},
+		{
+			name: "dropped before leaving",
+			now:  now,
+			refresh: func(next []model.Journey) []model.Journey {
+				return slices.Delete(next, 3, 4)
+			},
+			gone: []string{vbb.S5ID(3)},
+		},
+		{
+			name: "dropped once left",
+			now:  now.Add(5 * time.Minute),
+			refresh: func(next []model.Journey) []model.Journey {
+				return next[1:]
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := slices.Clone(prev)
+			for i := range next {
+				next[i].Legs = slices.Clone(next[i].Legs)
+			}
+			changes, gone := diffJourneys(prev, tt.refresh(next), tt.now)
+			if tt.changes == nil {
+				tt.changes = map[string]journeyChange{}
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("changes = %v, want %v", changes, tt.changes)
+			}
+			if got := journeyIDs(gone); !slices.Equal(got, tt.gone) {
+				t.Errorf("gone = %v, want %v", got, tt.gone)
+			}
+		})
+	}
+}
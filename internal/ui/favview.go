@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"slices"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// favoriteIndex is the favorite for a route, or -1
+func (a *App) favoriteIndex(origin, dest model.Station) int {
+	for i, fav := range a.config.Routes {
+		if fav.Origin.ID == origin.ID && fav.Dest.ID == dest.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyFavoriteView brings back the product filters and sort mode last
+// used on the route, once each time the route changes. Favorites that
+// never had them changed get the defaults, other routes keep what's set.
+func (a *App) applyFavoriteView() {
+	route := model.RouteName(a.config.LastOrigin, a.config.LastDest)
+	if a.viewFor == route {
+		return
+	}
+	a.viewFor = route
+	i := a.favoriteIndex(a.config.LastOrigin, a.config.LastDest)
+	if i < 0 {
+		return
+	}
+	fav := a.config.Routes[i]
+
+	a.filters = a.viewProducts(fav.Origin, fav.Dest)
+	a.localOnly = false
+	hasLongDistance := false
+	for _, p := range a.config.Preset().Products {
+		if p.LongDistance {
+			hasLongDistance = true
+			a.localOnly = a.localOnly || a.filters[p.ID]
+		}
+	}
+	a.localOnly = hasLongDistance && !a.localOnly
+
+	a.sortMode = sortModes[0]
+	if slices.Contains(sortModes, fav.Sort) {
+		a.sortMode = fav.Sort
+	}
+}
+
+// viewProducts is the product filter the route gets once it's loaded: its
+// favorite's saved one, or the current one for other routes
+func (a *App) viewProducts(origin, dest model.Station) map[string]bool {
+	products := a.products()
+	i := a.favoriteIndex(origin, dest)
+	if i < 0 {
+		return products
+	}
+	for _, p := range a.config.Preset().Products {
+		products[p.ID] = !slices.Contains(a.config.Routes[i].Without, p.ID)
+	}
+	return products
+}
+
+// rememberView keeps the current product filters and sort mode with the
+// route's favorite, if it is one
+func (a *App) rememberView() {
+	i := a.favoriteIndex(a.config.LastOrigin, a.config.LastDest)
+	if i < 0 {
+		return
+	}
+	var without []string
+	for _, p := range a.config.Preset().Products {
+		if !a.filters[p.ID] {
+			without = append(without, p.ID)
+		}
+	}
+	mode := a.sortMode
+	if mode == sortModes[0] {
+		mode = ""
+	}
+	a.config.Routes[i].Without, a.config.Routes[i].Sort = without, mode
+	config.Save(a.config)
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestFavoriteView(t *testing.T) {
+	home := model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	work := model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+	gym := model.Station{ID: "900100003", Name: "S+U Alexanderplatz (Berlin)"}
+
+	all := map[string]bool{}
+	for _, p := range (config.Config{}).Preset().Products {
+		all[p.ID] = true
+	}
+	without := func(ids ...string) map[string]bool {
+		filters := maps.Clone(all)
+		for _, id := range ids {
+			filters[id] = false
+		}
+		return filters
+	}
+
+	tests := []struct {
+		name      string
+		fav       model.FavoriteRoute
+		origin    model.Station
+		dest      model.Station
+		filters   map[string]bool // before the route is loaded
+		want      map[string]bool
+		sort      string
+		localOnly bool
+	}{
+		{"saved view", model.FavoriteRoute{Origin: home, Dest: work, Without: []string{"bus", "express"}, Sort: "reliability"},
+			home, work, all, without("bus", "express"), "reliability", true},
+		{"never changed gets the defaults", model.FavoriteRoute{Origin: home, Dest: work},
+			home, work, without("tram"), all, "departure", false},
+		{"unknown sort mode", model.FavoriteRoute{Origin: home, Dest: work, Sort: "vibes"},
+			home, work, all, all, "departure", false},
+		{"the way back isn't the favorite", model.FavoriteRoute{Origin: home, Dest: work, Without: []string{"bus"}},
+			work, home, without("tram"), without("tram"), "price", false},
+		{"other route keeps what's set", model.FavoriteRoute{Origin: home, Dest: work, Without: []string{"bus"}},
+			home, gym, without("tram"), without("tram"), "price", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{filters: maps.Clone(tt.filters), sortMode: "price"}
+			a.config.Routes = []model.FavoriteRoute{tt.fav}
+			a.config.LastOrigin, a.config.LastDest = tt.origin, tt.dest
+
+			if got := a.viewProducts(tt.origin, tt.dest); !maps.Equal(got, tt.want) {
+				t.Errorf("viewProducts = %v, want %v", got, tt.want)
+			}
+			a.applyFavoriteView()
+			if !maps.Equal(a.filters, tt.want) || a.sortMode != tt.sort || a.localOnly != tt.localOnly {
+				t.Errorf("got %v sorted by %s, local only %v; want %v sorted by %s, local only %v",
+					a.filters, a.sortMode, a.localOnly, tt.want, tt.sort, tt.localOnly)
+			}
+
+			// Only once per route, so changes made on it stick
+			a.sortMode = "price"
+			a.applyFavoriteView()
+			if a.sortMode != "price" {
+				t.Error("applied again on the same route")
+			}
+		})
+	}
+}
+
+func TestRememberView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	home := model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	work := model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+
+	all := map[string]bool{}
+	for _, p := range (config.Config{}).Preset().Products {
+		all[p.ID] = true
+	}
+	a := &App{filters: maps.Clone(all), sortMode: "reliability"}
+	a.filters["bus"] = false
+	a.config.Routes = []model.FavoriteRoute{{Origin: home, Dest: work}}
+	a.config.LastOrigin, a.config.LastDest = home, work
+
+	a.rememberView()
+	if fav := a.config.Routes[0]; !slices.Equal(fav.Without, []string{"bus"}) || fav.Sort != "reliability" {
+		t.Fatalf("remembered %v sorted by %q", fav.Without, fav.Sort)
+	}
+
+	a.filters, a.sortMode = maps.Clone(all), sortModes[0]
+	a.rememberView()
+	if fav := a.config.Routes[0]; fav.Without != nil || fav.Sort != "" {
+		t.Errorf("the defaults are remembered as %v sorted by %q, want nothing", fav.Without, fav.Sort)
+	}
+
+	a.config.LastOrigin, a.config.LastDest = work, home
+	a.filters["tram"] = false
+	a.rememberView()
+	if fav := a.config.Routes[0]; fav.Without != nil {
+		t.Errorf("the way back changed the favorite to %v", fav.Without)
+	}
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/model"
+)
+
+// tripMaxAge is how long a fetched trip's stopovers are trusted while riding it
+const tripMaxAge = time.Minute
+
+// checkGetOff warns once per leg when the stop to get off at is close,
+// by minutes or by stops left, so dozing off doesn't mean missing it
+func (a *App) checkGetOff() {
+	minutes, stops := a.config.GetOff.Minutes, a.config.GetOff.Stops
+	if minutes <= 0 && stops <= 0 {
+		return
+	}
+	j, ok := a.trackedJourney()
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	for i, leg := range j.Legs {
+		if leg.TripID == "" || now.Before(leg.Departure) || !now.Before(leg.Arrival) {
+			continue
+		}
+		key := fmt.Sprintf("%s|%d", model.JourneyID(j), i)
+		if a.getOffAlerted[key] {
+			return
+		}
+
+		until := leg.Arrival.Sub(now)
+		left := -1
+		if stops > 0 {
+			left = a.stopsLeft(leg, now)
+		}
+		if !(minutes > 0 && until <= time.Duration(minutes)*time.Minute) && !(left >= 0 && left <= stops) {
+			return
+		}
+		a.getOffAlerted[key] = true
+
+		title := "Get off soon"
+		msg := fmt.Sprintf("%s arrives at %s at %s", leg.Line, model.CleanStation(leg.To), model.FormatTime(leg.Arrival))
+		if left == 1 {
+			msg += ", next stop"
+		} else if left > 1 {
+			msg += fmt.Sprintf(", %d stops left", left)
+		}
+		if i < len(j.Legs)-1 {
+			next := j.Legs[i+1]
+			title = "Change soon"
+			msg += fmt.Sprintf(", change to %s at %s", next.Line, model.FormatTime(next.Departure))
+		}
+
+		a.ring("get_off")
+		a.alarmFrame = 100
+		a.statusMsg = "🔔 " + title + ": " + msg
+		a.statusMsgFrame = 100
+		if a.config.GetOff.Desktop && !a.config.Notify.QuietHours.Active(now) {
+			a.goSafe(func() { alert.SendDesktop(title, msg) })
+		}
+		return
+	}
+}
+
+// stopsLeft counts the stops until the leg's last one, that one included,
+// or -1 while the trip's stopovers are still being fetched
+func (a *App) stopsLeft(leg model.Leg, now time.Time) int {
+	trip, ok := a.cachedTrip(leg.TripID, tripMaxAge)
+	if !ok {
+		return -1
+	}
+	return tripStopsLeft(trip, leg, now)
+}
+
+// tripStopsLeft counts the trip's stops still ahead after now between
+// where the leg boards and where it gets off, that one included, or -1
+// when the leg's stops aren't on the trip
+func tripStopsLeft(trip model.Trip, leg model.Leg, now time.Time) int {
+	left := 0
+	boarded := false
+	for _, s := range trip.Stopovers {
+		if !boarded {
+			boarded = s.Station.ID == leg.FromID
+			continue
+		}
+		if !s.Cancelled && s.Arrival.After(now) {
+			left++
+		}
+		if s.Station.ID == leg.ToID {
+			return left
+		}
+	}
+	return -1
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestTripStopsLeft(t *testing.T) {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	f := vbb.NewFake(start)
+	journeys, err := f.S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first S5: Warschauer Str. 08:02, Alexanderplatz 08:08, Berlin
+	// Hauptbahnhof 08:13, Zoo 08:21
+	leg := journeys[0].Legs[0]
+	trip, err := f.Trip(context.Background(), leg.TripID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+	alex, hbf := trip.Stopovers[1].Station.ID, trip.Stopovers[2].Station.ID
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		to        string // where the leg gets off, when not at Zoo
+		cancelled string
+		want      int
+	}{
+		{name: "just left", now: at(3), want: 3},
+		{name: "past one stop", now: at(9), want: 2},
+		{name: "next stop", now: at(14), want: 1},
+		{name: "getting off earlier", now: at(3), to: hbf, want: 2},
+		{name: "cancelled stop", now: at(3), cancelled: alex, want: 2},
+		{name: "arrived", now: at(25), want: 0},
+		{name: "not on the trip", now: at(3), to: "900000000", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, tr := leg, trip
+			if tt.to != "" {
+				l.ToID = tt.to
+			}
+			tr.Stopovers = append([]model.Stopover(nil), trip.Stopovers...)
+			for i := range tr.Stopovers {
+				if tr.Stopovers[i].Station.ID == tt.cancelled {
+					tr.Stopovers[i].Cancelled = true
+				}
+			}
+			if got := tripStopsLeft(tr, l, tt.now); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
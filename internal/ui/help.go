@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyHelp is one line of the help page: the keys and what they do
+type keyHelp struct {
+	keys, what string
+}
+
+var mainKeys = []keyHelp{
+	{"j/k", "move through the journeys"},
+	{"Enter", "journey details"},
+	{"r", "refresh"},
+	{"s", "search a station"},
+	{"F / a", "favorites / add the route to them"},
+	{"H / T / N", "plan home / the round trip / to the next appointment"},
+	{"R / u", "reverse the route / undo that"},
+	{"w", "when to leave"},
+	{"0-3 / < >", "limit the changes / one less or more"},
+	{"E", "long-distance trains"},
+	{"o", "sort order"},
+	{"z", "density"},
+	{"d", "journeys that have left"},
+	{"p / P", "pin the journey / re-plan from where it gets to"},
+	{"Space / C / Esc", "tag journeys / compare the tagged / untag"},
+	{"B", "bookmark the journey"},
+	{"m / M", "took this one / the diary"},
+	{"L", "watch a line"},
+	{"D", "disruptions"},
+	{"S", "delay statistics"},
+	{"V", "departure and arrival boards"},
+	{"i / G / e", "export iCal / GPX / CSV"},
+	{"c / y", "QR code / copy the journey"},
+	{"O", "the journey on a map"},
+	{": / ^P", "command line / launcher"},
+	{"^S / ^X", "screenshot, plain or in color"},
+	{"?", "this help"},
+	{"q / Q", "quit / quit and print the journey"},
+}
+
+var detailKeys = []keyHelp{
+	{"i / G", "export iCal / GPX"},
+	{"c / y", "QR code / copy the journey"},
+	{"x", "stations to avoid"},
+	{"t", "the trips' stops"},
+	{"w / O", "walking map / journey map"},
+	{"h", "hints"},
+	{"Q", "quit and print the journey"},
+	{"b / Esc", "back"},
+}
+
+func init() {
+	// Not in the commands' literal: the help lists them
+	commands["help"] = command{"", "every key, command and macro", noArgs((*App).showHelp)}
+}
+
+// showHelp lists every key, command and macro, the legend only has room
+// for the most used keys
+func (a *App) showHelp() {
+	var sb strings.Builder
+	section := func(title string, keys []keyHelp) {
+		sb.WriteString("[::b]" + title + "[-:-:-]\n")
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf("  [yellow]%-16s[-] %s\n", k.keys, k.what))
+		}
+		sb.WriteString("\n")
+	}
+	section("Journeys", mainKeys)
+	section("Journey details", detailKeys)
+
+	sb.WriteString("[::b]Commands[-:-:-] [dim](after ':' or in macros)[-]\n")
+	for _, name := range commandNames() {
+		cmd := commands[name]
+		sb.WriteString(fmt.Sprintf("  [yellow]%-16s[-] %s\n", strings.TrimSpace(name+" "+cmd.args), cmd.help))
+	}
+
+	if len(a.config.Macros) > 0 {
+		keys := make([]string, 0, len(a.config.Macros))
+		for key := range a.config.Macros {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sb.WriteString("\n[::b]Macros[-:-:-]\n")
+		for _, key := range keys {
+			sb.WriteString(fmt.Sprintf("  [yellow]%-16s[-] %s\n", key, strings.Join(a.config.Macros[key], "; ")))
+		}
+	}
+	sb.WriteString("\n[dim]Press ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Help ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' || event.Rune() == '?' {
+			a.pages.RemovePage("help")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("help", view, true, false)
+	a.pages.SwitchToPage("help")
+	a.app.SetFocus(view)
+}
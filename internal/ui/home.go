@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"log/slog"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// homeRadius is how far from the configured location the nearest stop may be
+const homeRadius = 1000
+
+// goHome plans from the current position to the home station without going through
+// search. With a location in the config that's the closest stop to it,
+// otherwise the current origin, or the destination when the origin already
+// is home.
+func (a *App) goHome() {
+	home := a.config.HomeStation()
+
+	origin := a.config.LastOrigin
+	if origin.ID == home.ID {
+		origin = a.config.LastDest
+	}
+
+	loc := a.config.Location
+	if loc == nil {
+		a.planHome(origin, home)
+		return
+	}
+
+	a.statusMsg = "⌂ Finding the nearest stop…"
+	a.statusMsgFrame = 30
+	at := *loc
+	a.goSafe(func() {
+		stops, err := a.client.Nearby(a.ctx, at, homeRadius)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				slog.Warn("nearest stop lookup failed", "err", err)
+			} else if len(stops) > 0 {
+				origin = stops[0].Station
+			}
+			a.planHome(origin, home)
+		})
+	})
+}
+
+func (a *App) planHome(origin, home model.Station) {
+	a.dirty = true
+	if origin.ID == home.ID {
+		a.statusMsg = "⌂ You're already home"
+		a.statusMsgFrame = 30
+		return
+	}
+	a.config.LastOrigin, a.config.LastDest = origin, home
+	config.Save(a.config)
+	slog.Info("heading home", "route", model.RouteName(origin, home))
+	a.statusMsg = "⌂ Heading home from " + model.CleanStation(origin.Name)
+	a.statusMsgFrame = 30
+	a.refresh()
+}
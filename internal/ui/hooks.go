@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"go-commute/internal/alert"
+	"go-commute/internal/hooks"
+	"go-commute/internal/model"
+)
+
+// runRefreshHooks feeds a finished refresh to the user's hooks: the whole
+// list to refresh_done, and each new delay with its journey to
+// delay_detected. It runs in the background and blocks until they're done.
+func runRefreshHooks(hookCfg map[string][]string, route string, journeys []model.Journey, alerts []alert.Alert) {
+	hooks.Run(hookCfg, hooks.Payload{Event: "refresh_done", Route: route, Journeys: journeys})
+	for _, al := range alerts {
+		if al.Kind != "delay" {
+			continue
+		}
+		al := al
+		p := hooks.Payload{Event: "delay_detected", Route: route, Alert: &al}
+		if j, ok := delayedJourney(journeys, al.Line); ok {
+			p.Journey = &j
+		}
+		hooks.Run(hookCfg, p)
+	}
+}
+
+// delayedJourney is the first journey with a delayed leg on the line
+func delayedJourney(journeys []model.Journey, line string) (model.Journey, bool) {
+	for _, j := range journeys {
+		for _, leg := range j.Legs {
+			if leg.Line == line && leg.DepDelay > 0 {
+				return j, true
+			}
+		}
+	}
+	return model.Journey{}, false
+}
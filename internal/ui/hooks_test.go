@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestDelayedJourney(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	journeys, err := vbb.NewFake(now).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string
+		want string // journey ID, empty for none
+	}{
+		{"S5", vbb.S5ID(2)},
+		{"U2", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			j, ok := delayedJourney(journeys, tt.line)
+			if ok != (tt.want != "") || (ok && model.JourneyID(j) != tt.want) {
+				t.Errorf("got %s, %v, want %q", model.JourneyID(j), ok, tt.want)
+			}
+		})
+	}
+}
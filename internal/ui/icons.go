@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// unicodeTerminal guesses whether the terminal can show emoji and Nerd
+// Font icons: a UTF-8 locale, and not the Linux console, whose font has
+// neither. On Windows only Windows Terminal can.
+func unicodeTerminal() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != ""
+	}
+	if os.Getenv("TERM") == "linux" {
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
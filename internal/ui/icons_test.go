@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestUnicodeTerminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("goes by WT_SESSION on Windows")
+	}
+	tests := []struct {
+		name                       string
+		term, lcAll, lcCtype, lang string
+		want                       bool
+	}{
+		{"UTF-8 locale", "xterm-256color", "", "", "de_DE.UTF-8", true},
+		{"utf8 spelled short", "xterm-256color", "", "", "en_US.utf8", true},
+		{"Latin-1 locale", "xterm-256color", "", "", "de_DE.ISO-8859-1", false},
+		{"LC_ALL first", "xterm-256color", "C", "", "en_US.UTF-8", false},
+		{"LC_CTYPE before LANG", "xterm-256color", "", "en_US.UTF-8", "C", true},
+		{"Linux console", "linux", "", "", "en_US.UTF-8", false},
+		{"no locale", "xterm-256color", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_CTYPE", tt.lcCtype)
+			t.Setenv("LANG", tt.lang)
+			if got := unicodeTerminal(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
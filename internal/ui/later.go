@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// selectNext moves the selection down, loading later journeys when it's
+// already on the last one
+func (a *App) selectNext() {
+	if a.selectedIdx < len(a.journeys)-1 {
+		a.selectedIdx++
+		a.routeAnimFrame = 0
+		return
+	}
+	a.loadLater()
+}
+
+// loadLater fetches the page of journeys after the listed ones and adds
+// it to the list. Refreshes fetch as many pages again, so they stay.
+func (a *App) loadLater() {
+	pager, ok := a.client.(vbb.Pager)
+	if !ok || a.laterRef == "" || a.loadingLater || a.isLoading {
+		return
+	}
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+	route, ref, avoid := model.RouteName(origin, dest), a.laterRef, a.config.Avoid
+	transfers := a.transfers
+	opts := vbb.JourneyOptions{Products: a.products(), Transfers: &transfers, LaterThan: ref}.From(origin)
+	a.loadingLater = true
+	a.dirty = true
+
+	a.goSafe(func() {
+		more, next, err := pager.JourneysPage(a.ctx, origin.ID, dest.ID, opts)
+		if err == nil {
+			a.history.Record(route, more)
+			more, _ = avoid.Filter(more, origin, dest)
+		}
+		a.app.QueueUpdateDraw(func() {
+			a.loadingLater = false
+			if a.journeysFor != route || a.laterRef != ref {
+				return // refreshed or switched routes meanwhile
+			}
+			if err != nil {
+				slog.Warn("loading later journeys failed", "route", route, "err", err)
+				a.statusMsg = "Couldn't load later journeys: " + err.Error()
+				a.statusMsgFrame = 50
+				return
+			}
+			if a.config.HideCancelled {
+				more = dropCancelled(more)
+			}
+			lineDelays := a.history.LineDelays()
+			for i := range more {
+				more[i].IsNew = false
+				more[i].Reliability = history.JourneyReliability(more[i], lineDelays)
+				a.prevJourneyIDs[model.JourneyID(more[i])] = true
+			}
+
+			atEnd := a.selectedIdx == len(a.journeys)-1
+			selected := ""
+			if a.selectedIdx < len(a.journeys) {
+				selected = model.JourneyID(a.journeys[a.selectedIdx])
+			}
+			a.journeys = appendNewJourneys(a.journeys, more)
+			sortJourneys(a.journeys, a.sortMode)
+			for i := range a.journeys {
+				if model.JourneyID(a.journeys[i]) == selected {
+					a.selectedIdx = i
+				}
+			}
+			if atEnd && a.selectedIdx < len(a.journeys)-1 {
+				a.selectedIdx++
+			}
+			a.laterRef = next
+			a.laterPages++
+			a.dirty = true
+		})
+	})
+}
+
+// fetchJourneys is the first page of journeys plus as many later pages,
+// and the reference for the page after those. A later page failing
+// leaves the list shorter, not failed.
+func fetchJourneys(ctx context.Context, client vbb.TransitClient, originID, destID string, opts vbb.JourneyOptions, pages int) ([]model.Journey, string, error) {
+	pager, ok := client.(vbb.Pager)
+	if !ok {
+		journeys, err := client.Journeys(ctx, originID, destID, opts)
+		return journeys, "", err
+	}
+	journeys, ref, err := pager.JourneysPage(ctx, originID, destID, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	for ; pages > 0 && ref != ""; pages-- {
+		opts.LaterThan = ref
+		more, next, err := pager.JourneysPage(ctx, originID, destID, opts)
+		if err != nil {
+			slog.Warn("refreshing later journeys failed", "err", err)
+			return journeys, "", nil
+		}
+		journeys, ref = appendNewJourneys(journeys, more), next
+	}
+	return journeys, ref, nil
+}
+
+// appendNewJourneys adds the journeys not listed yet, as pages overlap
+func appendNewJourneys(journeys, more []model.Journey) []model.Journey {
+	seen := make(map[string]bool, len(journeys))
+	for _, j := range journeys {
+		seen[model.JourneyID(j)] = true
+	}
+	for _, j := range more {
+		if !seen[model.JourneyID(j)] {
+			journeys = append(journeys, j)
+		}
+	}
+	return journeys
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestAppendNewJourneys(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	all, err := vbb.NewFake(now).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := journeyIDs(all)
+
+	tests := []struct {
+		name           string
+		journeys, more []model.Journey
+		want           []string
+	}{
+		{"next page", all[:4], all[4:8], ids[:8]},
+		{"overlapping pages", all[:4], all[2:6], ids[:6]},
+		{"nothing new", all[:4], all[1:3], ids[:4]},
+		{"empty page", all[:4], nil, ids[:4]},
+		{"first page", nil, all[:3], ids[:3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := appendNewJourneys(slices.Clone(tt.journeys), tt.more)
+			if !slices.Equal(journeyIDs(got), tt.want) {
+				t.Errorf("got %v, want %v", journeyIDs(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/stops"
+)
+
+// launcherStations is how many stops the launcher offers to plan to
+const launcherStations = 5
+
+// launchItem is one thing the launcher can jump to
+type launchItem struct {
+	label string
+	hint  string
+	run   func()
+}
+
+// showLauncher opens a fuzzy finder over the favorites, the recent routes,
+// the commands and the stops, so "gy" is enough to get to the gym
+func (a *App) showLauncher() {
+	input := tview.NewInputField().SetLabel("> ")
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorBlue)
+	box := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(list, 0, 1, false)
+	box.SetBorder(true).SetTitle(" Go to (Enter=Go, Esc=Back) ")
+
+	var items []launchItem
+	dismiss := func() {
+		a.pages.RemovePage("launcher")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+		a.dirty = true
+	}
+	fill := func(query string) {
+		items = a.launchItems(query)
+		list.Clear()
+		for _, item := range items {
+			text := item.label
+			if item.hint != "" {
+				text += "  [dim]" + tview.Escape(item.hint) + "[-]"
+			}
+			list.AddItem(text, "", 0, nil)
+		}
+	}
+
+	input.SetChangedFunc(fill)
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyCtrlK:
+			if i := list.GetCurrentItem(); i > 0 {
+				list.SetCurrentItem(i - 1)
+			}
+			return nil
+		case tcell.KeyDown, tcell.KeyCtrlJ:
+			if i := list.GetCurrentItem(); i < list.GetItemCount()-1 {
+				list.SetCurrentItem(i + 1)
+			}
+			return nil
+		case tcell.KeyEscape:
+			dismiss()
+			return nil
+		case tcell.KeyEnter:
+			i := list.GetCurrentItem()
+			dismiss()
+			if i >= 0 && i < len(items) {
+				items[i].run()
+			}
+			return nil
+		}
+		return event
+	})
+
+	fill("")
+	a.pages.AddPage("launcher", box, true, false)
+	a.pages.SwitchToPage("launcher")
+	a.app.SetFocus(input)
+}
+
+// launchItems are the favorites, recent routes and commands matching the
+// query, best match first, then the stops it finds
+func (a *App) launchItems(query string) []launchItem {
+	type scored struct {
+		launchItem
+		score int
+	}
+	var found []scored
+	add := func(item launchItem, text string) {
+		if score, ok := stops.Score(query, text); ok {
+			found = append(found, scored{item, score})
+		}
+	}
+
+	for i, fav := range a.config.Routes {
+		idx := i
+		name := a.config.Label(fav.Origin) + " → " + a.config.Label(fav.Dest)
+		add(launchItem{label: "★ " + tview.Escape(name), hint: "favorite", run: func() { a.loadFavorite(idx) }}, name)
+	}
+	for _, r := range a.config.Recent {
+		if a.isFavorite(r.Origin, r.Dest) {
+			continue
+		}
+		route := r
+		name := a.config.Label(route.Origin) + " → " + a.config.Label(route.Dest)
+		add(launchItem{label: "↺ " + tview.Escape(name), hint: "recent", run: func() { a.loadRoute(route) }}, name)
+	}
+	for _, name := range commandNames() {
+		name, cmd := name, commands[name]
+		run := func() {
+			if err := a.runCommand(name); err != nil {
+				a.statusMsg = "⚠ " + err.Error()
+				a.statusMsgFrame = 50
+			}
+		}
+		if cmd.args != "" {
+			// Commands taking an argument open the command line with it
+			run = func() {
+				a.showCommandLine()
+				a.cmdLine.SetText(name + " ")
+			}
+		}
+		add(launchItem{label: ":" + name, hint: cmd.help, run: run}, name+" "+cmd.help)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	items := make([]launchItem, 0, len(found)+launcherStations)
+	for _, f := range found {
+		items = append(items, f.launchItem)
+	}
+	if query != "" {
+		for _, s := range a.stops.Search(query, launcherStations) {
+			station := s
+			items = append(items, launchItem{
+				label: "⌖ " + tview.Escape(model.CleanStation(station.Name)),
+				hint:  "plan there from " + a.config.Label(a.config.LastOrigin),
+				run:   func() { a.loadRoute(model.FavoriteRoute{Origin: a.config.LastOrigin, Dest: station}) },
+			})
+		}
+	}
+	return items
+}
+
+// loadRoute plans a route that isn't necessarily a favorite
+func (a *App) loadRoute(r model.FavoriteRoute) {
+	a.config.LastOrigin, a.config.LastDest = r.Origin, r.Dest
+	config.Save(a.config)
+	a.refresh()
+	a.statusMsg = fmt.Sprintf("→ %s", model.RouteName(r.Origin, r.Dest))
+	a.statusMsgFrame = 30
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// lineWatchMaxAge is how often the pinned line's departures are fetched again
+const lineWatchMaxAge = 30 * time.Second
+
+// lineWatchShown is how many departures the widget lists
+const lineWatchShown = 3
+
+// promptLineWatch pins "next S3 towards Erkner" at the current origin above
+// the journeys. An empty answer unpins it.
+func (a *App) promptLineWatch() {
+	stop := a.config.LastOrigin
+	initial := ""
+	if w := a.config.LineWatch; w != nil {
+		initial = strings.TrimSpace(w.Line + " " + w.Direction)
+	}
+	label := fmt.Sprintf("At %s, line and direction (S3 Erkner): ", model.CleanStation(stop.Name))
+	a.prompt("Line watch", label, initial, func(text string) {
+		line, direction, _ := strings.Cut(strings.TrimSpace(text), " ")
+		if line == "" {
+			a.config.LineWatch = nil
+			a.statusMsg = "Line watch removed"
+		} else {
+			a.config.LineWatch = &config.LineWatch{Stop: stop, Line: line, Direction: strings.TrimSpace(direction)}
+			a.statusMsg = "📌 Watching " + a.config.LineWatch.Label()
+		}
+		a.statusMsgFrame = 30
+		config.Save(a.config)
+		a.lineDepartures = nil
+		a.lineWatchFetched = time.Time{}
+		a.pollLineWatch()
+		a.dirty = true
+	})
+}
+
+// pollLineWatch fetches the pinned line's next departures when they're stale
+func (a *App) pollLineWatch() {
+	w := a.config.LineWatch
+	if w == nil || a.lineWatchFetching || time.Since(a.lineWatchFetched) < lineWatchMaxAge {
+		return
+	}
+	watch := *w
+	a.lineWatchFetching = true
+	a.goSafe(func() {
+		deps, err := a.client.Departures(a.ctx, watch.Stop.ID)
+		a.app.QueueUpdateDraw(func() {
+			a.lineWatchFetching = false
+			a.lineWatchFetched = time.Now()
+			if err != nil {
+				slog.Warn("line watch failed", "watch", watch.Label(), "err", err)
+				a.lineWatchErr = err
+				a.dirty = true
+				return
+			}
+			a.lineWatchErr = nil
+			a.lineDepartures = a.lineDepartures[:0]
+			for _, d := range deps {
+				if watch.Matches(d) {
+					a.lineDepartures = append(a.lineDepartures, d)
+				}
+			}
+			a.dirty = true
+		})
+	})
+}
+
+// renderLineWatch fills the widget above the journeys, or hides it
+func (a *App) renderLineWatch() {
+	w := a.config.LineWatch
+	if w == nil {
+		a.mainFlex.ResizeItem(a.lineWidget, 0, 0)
+		return
+	}
+	a.mainFlex.ResizeItem(a.lineWidget, 1, 0)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(" [cyan]📌 %s[-]  ", w.Label()))
+	now := time.Now()
+	shown := 0
+	for _, d := range a.lineDepartures {
+		if d.Cancelled || d.When.Before(now) {
+			continue
+		}
+		if shown == lineWatchShown {
+			break
+		}
+		shown++
+		sb.WriteString(fmt.Sprintf(" %s [dim]%s[-]", formatCountdown(d.When.Sub(now)), model.FormatTime(d.When)))
+		if mins := d.Delay / 60; mins > 0 {
+			sb.WriteString(fmt.Sprintf(" [yellow]+%d[-]", mins))
+		}
+	}
+	switch {
+	case shown > 0:
+	case a.lineWatchErr != nil:
+		sb.WriteString("[red]departures unavailable[-]")
+	case a.lineWatchFetched.IsZero():
+		sb.WriteString("[dim]loading…[-]")
+	default:
+		sb.WriteString("[dim]no departures soon[-]")
+	}
+	a.lineWidget.SetText(sb.String())
+}
@@ -0,0 +1,27 @@
+package ui
+
+import "maps"
+
+// toggleLongDistance leaves intercity and express trains out of the
+// journeys, or lets them back in, and fetches them again
+func (a *App) toggleLongDistance() {
+	a.localOnly = !a.localOnly
+	for _, p := range a.config.Preset().Products {
+		if p.LongDistance {
+			a.filters[p.ID] = !a.localOnly
+		}
+	}
+	if a.localOnly {
+		a.statusMsg = "Long-distance trains hidden"
+	} else {
+		a.statusMsg = "Long-distance trains included"
+	}
+	a.statusMsgFrame = 30
+	a.rememberView()
+	a.refresh()
+}
+
+// products is a copy of the enabled products for a background fetch
+func (a *App) products() map[string]bool {
+	return maps.Clone(a.filters)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// runMacro runs a macro's commands in order, stopping at the first that
+// fails. The commands refreshing on their own is fine, refresh only ever
+// runs one fetch at a time.
+func (a *App) runMacro(key string, lines []string) {
+	slog.Info("macro", "key", key, "commands", lines)
+	for _, line := range lines {
+		if err := a.runCommand(line); err != nil {
+			a.statusMsg = fmt.Sprintf("⚠ Macro %s: %v", key, err)
+			a.statusMsgFrame = 50
+			return
+		}
+	}
+}
+
+// builtinKeys are the main screen's own keys, which macros can't take
+const builtinKeys = "kjrHTNLRsFaicypPDSmMo0123wOGQVuEzd<>: CBe?q"
+
+// validateMacros checks that each macro sits on a single key of its own
+// and only runs known commands
+func validateMacros(macros map[string][]string) error {
+	keys := make([]string, 0, len(macros))
+	for key := range macros {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if utf8.RuneCountInString(key) != 1 {
+			return fmt.Errorf("macro key %q must be a single character", key)
+		}
+		if strings.Contains(builtinKeys, key) {
+			return fmt.Errorf("macro key %q is already taken by a built-in key", key)
+		}
+		for _, line := range macros[key] {
+			if _, _, err := parseCommand(line); err != nil {
+				return fmt.Errorf("macro %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
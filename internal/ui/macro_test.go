@@ -0,0 +1,25 @@
+package ui
+
+import "testing"
+
+func TestValidateMacros(t *testing.T) {
+	tests := []struct {
+		name   string
+		macros map[string][]string
+		ok     bool
+	}{
+		{"none", nil, true},
+		{"known commands", map[string][]string{"g": {"favorite 1", "transfers 0"}, "x": {"sort departure"}}, true},
+		{"longer key", map[string][]string{"gg": {"refresh"}}, false},
+		{"no key", map[string][]string{"": {"refresh"}}, false},
+		{"built-in key", map[string][]string{"r": {"favorite 1", "refresh"}}, false},
+		{"unknown command", map[string][]string{"g": {"favorite 1", "teleport"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMacros(tt.macros); (err == nil) != tt.ok {
+				t.Errorf("err = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
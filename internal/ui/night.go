@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// lastCallFrom is the hour after which the last S- or U-Bahn of the night
+// is pointed out
+const lastCallFrom = 21
+
+// lastCallGap is how long a line has to be missing from the list after a
+// train before that train counts as its last. Even late at night S- and
+// U-Bahn run more often than that.
+const lastCallGap = time.Hour
+
+// lastRegular finds, per journey, an S- or U-Bahn leg that is the last of
+// its line from that stop in the list, late in the evening. It only counts
+// when the list reaches lastCallGap past it, so the line really stopped
+// rather than the list running out; after it only the night network is left.
+func lastRegular(journeys []model.Journey) map[string]string {
+	type service struct{ line, from string }
+	last := map[service]time.Time{}
+	var latest time.Time
+	for _, j := range journeys {
+		if j.LeaveAt.After(latest) {
+			latest = j.LeaveAt
+		}
+		for _, leg := range j.Legs {
+			if !regularRail(leg) || leg.Cancelled {
+				continue
+			}
+			key := service{leg.Line, leg.FromID}
+			if leg.Departure.After(last[key]) {
+				last[key] = leg.Departure
+			}
+		}
+	}
+
+	hints := map[string]string{}
+	for _, j := range journeys {
+		for _, leg := range j.Legs {
+			if !regularRail(leg) || leg.Cancelled || !lateEvening(leg.Departure) {
+				continue
+			}
+			if !leg.Departure.Equal(last[service{leg.Line, leg.FromID}]) || latest.Before(leg.Departure.Add(lastCallGap)) {
+				continue
+			}
+			hints[model.JourneyID(j)] = leg.Line
+			break
+		}
+	}
+	return hints
+}
+
+func regularRail(leg model.Leg) bool {
+	return (leg.Product == "suburban" || leg.Product == "subway") && !model.IsNightLine(leg.Line)
+}
+
+func lateEvening(t time.Time) bool {
+	return t.In(model.DisplayZone).Hour() >= lastCallFrom || model.IsLateNight(t)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestLastRegular(t *testing.T) {
+	evening := time.Date(2026, 10, 16, 22, 0, 0, 0, model.DisplayZone)
+	afternoon := time.Date(2026, 10, 16, 16, 0, 0, 0, model.DisplayZone)
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		s5s   int           // how many of the fixture's S5 are listed
+		night time.Duration // a night bus this long after the last S5 listed, if any
+		want  map[string]string
+	}{
+		{"list ends while the line runs", evening, 12, 0, map[string]string{}},
+		{"night bus an hour after the last", evening, 12, 70 * time.Minute, map[string]string{vbb.S5ID(11): "S5"}},
+		{"list cut short", evening, 6, 70 * time.Minute, map[string]string{vbb.S5ID(5): "S5"}},
+		{"night bus too soon to tell", evening, 12, 30 * time.Minute, map[string]string{}},
+		{"not late enough", afternoon, 12, 70 * time.Minute, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journeys, err := vbb.NewFake(tt.now).S5Journeys()
+			if err != nil {
+				t.Fatal(err)
+			}
+			journeys = journeys[:tt.s5s]
+			if tt.night > 0 {
+				last := journeys[len(journeys)-1].Legs[0]
+				at := last.Departure.Add(tt.night)
+				journeys = append(journeys, model.Journey{
+					LeaveAt:  at,
+					ArriveAt: at.Add(30 * time.Minute),
+					Legs: []model.Leg{{
+						Line: "N5", Product: "bus", TripID: "fake|N5|0",
+						FromID: last.FromID, Departure: at, Arrival: at.Add(30 * time.Minute),
+					}},
+				})
+			}
+			if got := lastRegular(journeys); !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"go-commute/internal/alert"
+	"go-commute/internal/model"
+)
+
+// alarmPlatformChange sounds the alarm in the TUI when a refresh's alerts
+// include a platform change on the pinned journey: the notifications go
+// out for every journey, but this is the train being run for
+func (a *App) alarmPlatformChange(alerts []alert.Alert) {
+	if a.pinnedID == "" {
+		return
+	}
+	j, ok := a.trackedJourney()
+	if !ok || model.JourneyID(j) != a.pinnedID {
+		return
+	}
+	for _, al := range alerts {
+		if al.Kind != "platform" {
+			continue
+		}
+		for _, leg := range j.Legs {
+			if leg.TripID != "" && leg.TripID == al.TripID {
+				a.ring("risk")
+				a.alarmFrame = 30
+				a.statusMsg = "⚠ " + al.Message
+				a.statusMsgFrame = 100
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// positionMaxAge is how often the trip of a leg shown in transit is
+// fetched again for where the vehicle is
+const positionMaxAge = 20 * time.Second
+
+// legProgress is how far along a leg in progress the vehicle is, from 0
+// to 1, and where it is in words. It goes by the trip's realtime stopovers
+// and position once fetched, and until then by the clock between the
+// leg's times, with an empty where.
+func (a *App) legProgress(leg model.Leg, now time.Time) (float64, string) {
+	linear := float64(now.Sub(leg.Departure)) / float64(leg.Arrival.Sub(leg.Departure))
+	if leg.TripID == "" {
+		return clamp01(linear), ""
+	}
+	trip, ok := a.cachedTrip(leg.TripID, positionMaxAge)
+	if !ok || len(trip.Stopovers) == 0 {
+		return clamp01(linear), ""
+	}
+	if progress, where, ok := tripProgress(trip, leg, now); ok {
+		return progress, where
+	}
+	return clamp01(linear), ""
+}
+
+// tripProgress places the vehicle on the leg's stretch of the trip: at a
+// stop while it's standing there, otherwise between the last stop it left
+// and the next, by its reported position or else by the realtime times
+func tripProgress(trip model.Trip, leg model.Leg, now time.Time) (float64, string, bool) {
+	var stops []model.Stopover
+	for _, s := range trip.Stopovers {
+		if len(stops) == 0 && s.Station.ID != leg.FromID {
+			continue
+		}
+		if !s.Cancelled {
+			stops = append(stops, s)
+		}
+		if s.Station.ID == leg.ToID {
+			break
+		}
+	}
+	if len(stops) < 2 || stops[len(stops)-1].Station.ID != leg.ToID {
+		return 0, "", false
+	}
+	arr := func(s model.Stopover) time.Time {
+		if s.Arrival.IsZero() {
+			return s.Departure
+		}
+		return s.Arrival
+	}
+	dep := func(s model.Stopover) time.Time {
+		if s.Departure.IsZero() {
+			return s.Arrival
+		}
+		return s.Departure
+	}
+	start, end := dep(stops[0]), arr(stops[len(stops)-1])
+	total := end.Sub(start)
+	if total <= 0 {
+		return 0, "", false
+	}
+	along := func(t time.Time) float64 { return clamp01(float64(t.Sub(start)) / float64(total)) }
+	late := func(s model.Stopover) string {
+		if s.ArrDelay >= 60 {
+			return fmt.Sprintf(", +%d min", s.ArrDelay/60)
+		}
+		return ""
+	}
+
+	if now.Before(start) {
+		return 0, fmt.Sprintf("at %s, leaves %s%s", model.CleanStation(stops[0].Station.Name),
+			model.FormatTime(start), late(stops[0])), true
+	}
+	for i, s := range stops[1:] {
+		prev := stops[i]
+		if now.Before(arr(s)) {
+			frac := float64(now.Sub(dep(prev))) / float64(arr(s).Sub(dep(prev)))
+			if f, ok := between(trip.Position, prev.Station.Location, s.Station.Location); ok {
+				frac = f
+			}
+			progress := along(dep(prev)) + clamp01(frac)*(along(arr(s))-along(dep(prev)))
+			return progress, fmt.Sprintf("between %s and %s%s", model.CleanStation(prev.Station.Name),
+				model.CleanStation(s.Station.Name), late(s)), true
+		}
+		if now.Before(dep(s)) && i+1 < len(stops)-1 {
+			return along(arr(s)), fmt.Sprintf("at %s%s", model.CleanStation(s.Station.Name), late(s)), true
+		}
+	}
+	return 1, "arriving at " + model.CleanStation(stops[len(stops)-1].Station.Name), true
+}
+
+// between is how far pos is on the way from one stop to the next, when
+// all three are known
+func between(pos, from, to *model.Coordinates) (float64, bool) {
+	if pos == nil || from == nil || to == nil {
+		return 0, false
+	}
+	done, left := model.Distance(*from, *pos), model.Distance(*pos, *to)
+	if done+left == 0 {
+		return 0, false
+	}
+	return float64(done) / float64(done+left), true
+}
+
+func clamp01(f float64) float64 {
+	return max(0, min(f, 1))
+}
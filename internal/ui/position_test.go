@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestTripProgress(t *testing.T) {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	f := vbb.NewFake(start)
+	journeys, err := f.S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The third S5 runs 2 min late: Warschauer Str. 08:24, Alexanderplatz
+	// 08:30, Berlin Hauptbahnhof 08:35, Zoo 08:43
+	leg := journeys[2].Legs[0]
+	at := func(minutes float64) time.Time {
+		return start.Add(time.Duration(minutes * float64(time.Minute)))
+	}
+	const alex, hbf = 1, 2
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		change   func(trip *model.Trip)
+		leg      func(leg *model.Leg)
+		progress float64
+		where    string
+		ok       bool
+	}{
+		{name: "not left yet", now: at(23), progress: 0, where: "at Warschauer Str., leaves 08:24, +2 min", ok: true},
+		{name: "halfway to the next stop", now: at(27), progress: 3.0 / 19, where: "between Warschauer Str. and Alexanderplatz, +2 min", ok: true},
+		{name: "passing a stop", now: at(30), progress: 6.0 / 19, where: "between Alexanderplatz and Berlin Hauptbahnhof, +2 min", ok: true},
+		{
+			name: "standing at a stop", now: at(30.5),
+			change:   func(trip *model.Trip) { trip.Stopovers[alex].Departure = at(31) },
+			progress: 6.0 / 19, where: "at Alexanderplatz, +2 min", ok: true,
+		},
+		{
+			name: "by position", now: at(31),
+			change: func(trip *model.Trip) {
+				for i := range trip.Stopovers {
+					c := f.Coords[trip.Stopovers[i].Station.ID]
+					trip.Stopovers[i].Station.Location = &c
+				}
+				a, h := f.Coords[trip.Stopovers[alex].Station.ID], f.Coords[trip.Stopovers[hbf].Station.ID]
+				trip.Position = &model.Coordinates{Latitude: (a.Latitude + h.Latitude) / 2, Longitude: (a.Longitude + h.Longitude) / 2}
+			},
+			progress: 8.5 / 19, where: "between Alexanderplatz and Berlin Hauptbahnhof, +2 min", ok: true,
+		},
+		{
+			name: "cancelled stop passed by", now: at(36),
+			change:   func(trip *model.Trip) { trip.Stopovers[hbf].Cancelled = true },
+			progress: 12.0 / 19, where: "between Alexanderplatz and Zoologischer Garten, +2 min", ok: true,
+		},
+		{name: "arriving", now: at(50), progress: 1, where: "arriving at Zoologischer Garten", ok: true},
+		{name: "not on the trip", now: at(27), leg: func(leg *model.Leg) { leg.ToID = "900000000" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip, err := f.Trip(context.Background(), leg.TripID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			trip.Stopovers = append([]model.Stopover(nil), trip.Stopovers...)
+			if tt.change != nil {
+				tt.change(&trip)
+			}
+			l := leg
+			if tt.leg != nil {
+				tt.leg(&l)
+			}
+			progress, where, ok := tripProgress(trip, l, tt.now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if math.Abs(progress-tt.progress) > 0.01 || where != tt.where {
+				t.Errorf("got %.3f %q, want %.3f %q", progress, where, tt.progress, tt.where)
+			}
+		})
+	}
+}
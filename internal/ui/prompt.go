@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// prompt asks for one line of text over the main screen. done runs on the
+// event loop after Enter, with the main screen back in front; Esc cancels.
+func (a *App) prompt(title, label, initial string, done func(text string)) {
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(initial).
+		SetFieldWidth(30)
+
+	box := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true)
+	box.SetBorder(true).SetTitle(" " + title + " ")
+
+	dismiss := func() {
+		a.pages.RemovePage("prompt")
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.list)
+		a.dirty = true
+	}
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			dismiss()
+		case tcell.KeyEnter:
+			text := input.GetText()
+			dismiss()
+			done(text)
+		}
+	})
+
+	a.pages.AddPage("prompt", box, true, false)
+	a.pages.SwitchToPage("prompt")
+	a.app.SetFocus(input)
+}
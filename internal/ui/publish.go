@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"log/slog"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/mqtt"
+	"go-commute/internal/vbb"
+)
+
+// publishFavorites publishes every favorite route over MQTT on a refresh:
+// the route just refreshed with its journeys when it's a favorite, the
+// others fetched alongside like the daemon does. A round still under way
+// skips the next one.
+func (a *App) publishFavorites(cfg config.MQTT, favorites []model.FavoriteRoute, avoid model.Avoidance, shown model.FavoriteRoute, journeys []model.Journey) {
+	if !a.publishing.CompareAndSwap(false, true) {
+		return
+	}
+	publish := func(r model.FavoriteRoute, journeys []model.Journey) {
+		if err := mqtt.Publish(cfg, mqtt.BuildMessages(cfg, r.Origin, r.Dest, journeys)); err != nil {
+			slog.Warn("mqtt publish failed", "route", model.RouteName(r.Origin, r.Dest), "err", err)
+		}
+	}
+	a.goSafe(func() {
+		defer a.publishing.Store(false)
+		var others []model.FavoriteRoute
+		for _, r := range favorites {
+			if r.Origin.ID == shown.Origin.ID && r.Dest.ID == shown.Dest.ID {
+				publish(r, journeys)
+			} else {
+				others = append(others, r)
+			}
+		}
+		for _, res := range vbb.FetchRoutes(a.ctx, a.client, others) {
+			if res.Err != nil {
+				slog.Warn("mqtt fetch failed", "route", model.RouteName(res.Route.Origin, res.Route.Dest), "err", res.Err)
+				continue
+			}
+			journeys, _ := avoid.Filter(res.Journeys, res.Route.Origin, res.Route.Dest)
+			publish(res.Route, journeys)
+		}
+	})
+}
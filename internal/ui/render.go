@@ -0,0 +1,795 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/fare"
+	"go-commute/internal/footprint"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+	"go-commute/internal/statusline"
+	"go-commute/internal/vbb"
+)
+
+// DelayHistory tracks delay trends for sparklines
+type DelayHistory struct {
+	Line    string
+	Delays  []int
+	Updated time.Time
+}
+
+// Spinner frames for loading animation
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// productColor is the tview color for a product, the provider's unless
+// the config has its own
+func (a *App) productColor(product string) string {
+	if p, ok := a.config.Preset().Product(product); ok {
+		return p.Color
+	}
+	return "white"
+}
+
+// productIcon is the tag for a product, the provider's unless the config
+// has its own
+func (a *App) productIcon(product string) string {
+	if p, ok := a.config.Preset().Product(product); ok {
+		return p.Icon
+	}
+	return "[ ]"
+}
+
+// formatCountdown formats duration as countdown with color
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		return "[red::b]GONE[-:-:-]"
+	}
+	mins := int(d.Minutes())
+	secs := int(d.Seconds()) % 60
+	if mins < 1 {
+		return fmt.Sprintf("[red::b]%ds[-:-:-]", secs)
+	} else if mins < 5 {
+		return fmt.Sprintf("[yellow]%d:%02d[-]", mins, secs)
+	}
+	return fmt.Sprintf("[green]%d:%02d[-]", mins, secs)
+}
+
+// formatRemark colors a remark by severity, leading with its summary
+func formatRemark(r model.Remark) string {
+	text := r.Text
+	if r.Summary != "" && r.Summary != r.Text {
+		text = fmt.Sprintf("[::b]%s[::-] %s", tview.Escape(r.Summary), tview.Escape(r.Text))
+	} else {
+		text = tview.Escape(text)
+	}
+	switch r.Type {
+	case "warning":
+		return "[red]⚠ " + text + "[-]"
+	case "status":
+		return "[yellow]ℹ " + text + "[-]"
+	}
+	return "[dim]· " + text + "[-]"
+}
+
+// formatDistance renders meters as "450 m" or "1.2 km"
+func formatDistance(m int) string {
+	if m < 1000 {
+		return fmt.Sprintf("%d m", m)
+	}
+	return fmt.Sprintf("%.1f km", float64(m)/1000)
+}
+
+// formatGrams shows small amounts of CO2 in grams, larger ones in kg
+func formatGrams(g int) string {
+	if g < 1000 {
+		return fmt.Sprintf("%d g", g)
+	}
+	return fmt.Sprintf("%.1f kg", float64(g)/1000)
+}
+
+// walkingLabel is the time on foot for a journey row, empty without any
+func walkingLabel(j model.Journey) string {
+	mins := int(j.Walking.Minutes())
+	if mins < 1 {
+		return ""
+	}
+	return fmt.Sprintf("walk:%dm", mins)
+}
+
+// shortDirection keeps a headsign short enough for the route diagram
+func shortDirection(direction string) string {
+	d := []rune(model.CleanStation(direction))
+	if len(d) > 12 {
+		return string(d[:11]) + "…"
+	}
+	return string(d)
+}
+
+// sparkline generates a mini graph from delay values
+func sparkline(values []int, width int) string {
+	if len(values) == 0 {
+		return strings.Repeat("▁", width)
+	}
+
+	blocks := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+	minVal, maxVal := values[0], values[0]
+	for _, v := range values {
+		if v < minVal {
+			minVal = v
+		}
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+
+	result := ""
+	step := len(values) / width
+	if step < 1 {
+		step = 1
+	}
+
+	for i := 0; i < width && i*step < len(values); i++ {
+		v := values[i*step]
+		idx := 0
+		if maxVal > minVal {
+			idx = int(float64(v-minVal) / float64(maxVal-minVal) * 7)
+		}
+		if idx > 7 {
+			idx = 7
+		}
+		result += string(blocks[idx])
+	}
+
+	for len(result) < width {
+		result += "▁"
+	}
+
+	return result
+}
+
+// occupancyBar generates static occupancy display
+func occupancyBar(level string, frame int) string {
+	switch level {
+	case "low":
+		return "[green]▓░░░░[-]"
+	case "medium":
+		return "[yellow]▓▓▓░░[-]"
+	case "high":
+		return "[red]▓▓▓▓▓[-]"
+	default:
+		return "[dim]░░░░░[-]"
+	}
+}
+
+func (a *App) showDetail() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	a.renderDetail()
+	a.pages.SwitchToPage("detail")
+	a.app.SetFocus(a.detail)
+}
+
+// renderDetail fills in the detail view of the selected journey, again
+// every second while one of its legs is under way
+func (a *App) renderDetail() {
+	a.detailLive = false
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+
+	j := a.journeys[a.selectedIdx]
+	var sb strings.Builder
+
+	countdown := time.Until(j.LeaveAt)
+	countdownStr := formatCountdown(countdown)
+
+	sb.WriteString(fmt.Sprintf("[yellow::b]Journey: %s → %s[-:-:-]  Departs in: %s\n",
+		model.FormatTime(j.LeaveAt), model.FormatTime(j.ArriveAt), countdownStr))
+	sb.WriteString(fmt.Sprintf("Duration: %dmin  |  Total wait: %dmin  |  Connections made: %s\n",
+		int(j.Duration.Minutes()), int(j.TotalWait.Minutes()), reliabilityBadge(j.Reliability)))
+	if j.Walking > 0 {
+		sb.WriteString(fmt.Sprintf("Walking: %dmin, %s in total\n", int(j.Walking.Minutes()), formatDistance(j.WalkingDistance)))
+	}
+	if a.config.Footprint {
+		if e, ok := footprint.Journey(j); ok {
+			sb.WriteString(fmt.Sprintf("CO₂: ~%s over %s  |  [dim]by car ~%s,[-] [green]%s saved[-]\n",
+				formatGrams(e.Grams), formatDistance(e.Meters), formatGrams(e.CarGrams), formatGrams(e.Saved())))
+		}
+	}
+	var fareInfo []string
+	if a.config.Preset().FareZones {
+		fareInfo = append(fareInfo, fmt.Sprintf("Fare zones: %s [dim](from where you board and change)[-]", fare.JourneyZones(j)))
+	}
+	if j.Price != nil {
+		fareInfo = append(fareInfo, fmt.Sprintf("Price: %s", j.Price))
+	}
+	if a.config.Preset().FareZones && fare.Known(a.config.Ticket) {
+		if why := fare.Uncovered(a.config.Ticket, j); why != "" {
+			fareInfo = append(fareInfo, fmt.Sprintf("[red::b]⊘ Not covered by your %s ticket: %s[-:-:-]", a.config.Ticket, why))
+		} else {
+			fareInfo = append(fareInfo, fmt.Sprintf("[green]✓ Covered by your %s ticket[-]", a.config.Ticket))
+		}
+	}
+	if len(fareInfo) > 0 {
+		sb.WriteString(strings.Join(fareInfo, "  |  ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("─", 55) + "\n\n")
+
+	if w := j.Walk; w != nil {
+		sb.WriteString(fmt.Sprintf("[::b]🚶 Walk %s, %dmin[-:-:-] %s → %s\n",
+			formatDistance(w.Distance), int(w.Duration.Minutes()),
+			model.FormatTime(w.Departure), tview.Escape(model.CleanStation(w.To))))
+		sb.WriteString(fmt.Sprintf("  [dim]Leave %s by %s[-]\n\n", tview.Escape(w.From), model.FormatTime(w.Departure)))
+	}
+
+	now := time.Now()
+
+	buffer := a.config.TransferMargin()
+	lineDelays := a.history.LineDelays()
+
+	for i, leg := range j.Legs {
+		// Wait time with tight connection warning, and how often such a
+		// change has been missed
+		dot := ""
+		if i > 0 {
+			dot = " " + missDot(history.TransferMiss(j, i, lineDelays))
+		}
+		if d := a.danger; d != nil && d.leg == i && d.key == fmt.Sprintf("%s|%d", model.JourneyID(j), i) {
+			sb.WriteString(fmt.Sprintf("[red::b]  ⚠ %s[-:-:-]%s\n", tview.Escape(d.message(leg)), dot))
+		} else if model.ConnectionAtRisk(j, i, buffer) {
+			sb.WriteString(fmt.Sprintf("[red::b]  ⚠ CONNECTION AT RISK: %s is %dmin late, %dmin left to change![-:-:-]%s\n",
+				j.Legs[i-1].Line, j.Legs[i-1].ArrDelay/60, int(leg.WaitBefore.Minutes()), dot))
+		} else if leg.WaitBefore > 0 {
+			waitMins := int(leg.WaitBefore.Minutes())
+			if waitMins <= 2 {
+				sb.WriteString(fmt.Sprintf("[red::b]  ⚡ TIGHT CONNECTION: %dmin to change![-:-:-]%s\n", waitMins, dot))
+			} else {
+				sb.WriteString(fmt.Sprintf("[yellow]  ⏱ Wait %dmin[-]%s\n", waitMins, dot))
+			}
+		} else if i > 0 {
+			sb.WriteString(fmt.Sprintf("  [dim]Change[-]%s\n", dot))
+		}
+
+		color := a.productColor(leg.Product)
+
+		// Delay with pulse effect
+		delayStr := ""
+		if leg.DepDelay > 0 {
+			delayStr = fmt.Sprintf(" [red::b]+%dm[-:-:-]", leg.DepDelay/60)
+		}
+
+		// Animated occupancy bar
+		occBar := occupancyBar(leg.Occupancy, a.animFrame)
+
+		cycleStr := ""
+		if leg.Cycle > 0 {
+			cycleStr = fmt.Sprintf(" (every %dm)", leg.Cycle)
+		}
+
+		// Delay sparkline history
+		sparkStr := ""
+		if hist, ok := a.delayHistory[leg.Line]; ok && len(hist.Delays) > 0 {
+			sparkStr = fmt.Sprintf(" [dim]%s[-]", sparkline(hist.Delays, 8))
+		}
+
+		direction := ""
+		if leg.Direction != "" {
+			direction = fmt.Sprintf(" [%s]▸ %s[-]", color, tview.Escape(model.CleanStation(leg.Direction)))
+		}
+		if leg.Night() {
+			direction += " [blue]☾ night service[-]"
+		}
+
+		if leg.Cancelled {
+			sb.WriteString(fmt.Sprintf("[red::bs]%s %s[-:-:-]%s  [red::s]%s → %s[-:-:-]  [red::b]✗ CANCELLED[-:-:-]\n",
+				a.productIcon(leg.Product), leg.Line, direction,
+				model.FormatTime(leg.Departure), model.FormatTime(leg.Arrival)))
+		} else {
+			sb.WriteString(fmt.Sprintf("[%s::b]%s %s[-:-:-]%s  %s → %s%s  %s%s%s\n",
+				color, a.productIcon(leg.Product), leg.Line, direction,
+				model.FormatTime(leg.Departure), model.FormatTime(leg.Arrival),
+				delayStr, occBar, cycleStr, sparkStr))
+		}
+
+		// Vehicle position tracker - show if journey is in progress
+		if now.After(leg.Departure) && now.Before(leg.Arrival) && !leg.Cancelled {
+			a.detailLive = true
+			progress, where := a.legProgress(leg, now)
+			pos := int(progress * 20)
+			if pos > 19 {
+				pos = 19
+			}
+			if where == "" {
+				where = "in transit"
+			}
+			bar := strings.Repeat("─", pos) + "●" + strings.Repeat("─", 19-pos)
+			sb.WriteString(fmt.Sprintf("    [%s]%s[-] [dim]%s[-]\n", color, bar, tview.Escape(where)))
+		}
+
+		// Stations with platforms
+		fromPlt := ""
+		if leg.DepPlatform != "" {
+			fromPlt = fmt.Sprintf(" [cyan][Plt %s][-]", leg.DepPlatform)
+		}
+		toPlt := ""
+		if leg.ArrPlatform != "" {
+			toPlt = fmt.Sprintf(" [cyan][Plt %s][-]", leg.ArrPlatform)
+		}
+
+		sb.WriteString(fmt.Sprintf("    From: %s%s\n", model.CleanStation(leg.From), fromPlt))
+		sb.WriteString(fmt.Sprintf("    To:   %s%s\n", model.CleanStation(leg.To), toPlt))
+
+		// Elevators and escalators, red when one is out
+		for _, note := range leg.Accessibility {
+			color := "blue"
+			if leg.AccessOutage {
+				color = "red"
+			}
+			sb.WriteString(fmt.Sprintf("    [%s]♿ %s[-]\n", color, tview.Escape(note)))
+		}
+
+		// Remarks by severity, hints only when asked for
+		hints := 0
+		for _, typ := range model.RemarkTypes {
+			for _, r := range leg.Remarks {
+				if r.Type != typ || slices.Contains(leg.Accessibility, r.Text) {
+					continue
+				}
+				if typ == "hint" && !a.showHints {
+					hints++
+					continue
+				}
+				sb.WriteString("    " + formatRemark(r) + "\n")
+			}
+		}
+		if hints > 0 {
+			noun := "hints"
+			if hints == 1 {
+				noun = "hint"
+			}
+			sb.WriteString(fmt.Sprintf("    [dim]+%d %s, press 'h' to show[-]\n", hints, noun))
+		}
+
+		if i < len(j.Legs)-1 {
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n\n[dim]Press ESC or 'b' to go back, 'i' to export to calendar, 'c' for a QR code, 'y' to copy, 'x' to avoid a station, 't' for stops & occupancy, 'w' for a walking map, 'O' to open in a map, 'G' for GPX, 'Q' to quit and print it, 'h' for hints[-]")
+
+	a.detail.SetText(sb.String())
+}
+
+func (a *App) renderHeader() {
+	now := time.Now().In(model.DisplayZone)
+	clock := model.FormatClock(now)
+	if zone := model.ZoneLabel(now); zone != "" {
+		clock += " " + zone
+	}
+
+	origin := a.config.Label(a.config.LastOrigin)
+	dest := a.config.Label(a.config.LastDest)
+	var route string
+	if a.headerTmpl != nil {
+		route = statusline.Render(a.headerTmpl, statusline.Collect(origin, dest, a.journeys, now))
+	} else {
+		if len(origin) > 15 {
+			origin = origin[:15]
+		}
+		if len(dest) > 15 {
+			dest = dest[:15]
+		}
+		route = origin + " → " + dest
+	}
+
+	if a.weather != nil && a.weatherFor == a.config.LastOrigin.ID {
+		clock += "  " + a.weather.Summary()
+	}
+
+	spinner := ""
+	if a.isLoading {
+		spinner = fmt.Sprintf(" %s", a.spinner())
+	}
+
+	// Status message display
+	statusDisplay := ""
+	if a.statusMsgFrame > 0 {
+		statusDisplay = fmt.Sprintf("  [green::b]%s[-:-:-]", a.statusMsg)
+	}
+
+	if !a.departAt.IsZero() {
+		statusDisplay += fmt.Sprintf("  [yellow]🕗 %s[-]", formatWhen(a.departAt))
+	}
+	transfersColor := "dim"
+	if a.transfers < vbb.DefaultTransfers {
+		transfersColor = "yellow"
+	}
+	statusDisplay += fmt.Sprintf("  [%s]⇄ %s[-]", transfersColor, transfersLabel(a.transfers))
+	if a.localOnly {
+		statusDisplay += "  [yellow]no IC/ICE[-]"
+	}
+
+	// Pulse effect on refresh
+	borderColor := "yellow"
+	if a.refreshPulse && a.blink() {
+		borderColor = "green"
+	}
+	if age := a.staleFor(); age > 0 {
+		borderColor = "magenta"
+		statusDisplay += fmt.Sprintf("  [white:magenta:b] %dm old [-:-:-]", int(age.Minutes()))
+	}
+	if a.alarmFrame > 0 && a.blink() {
+		borderColor = "red"
+	}
+
+	// Pinned journey countdown
+	if a.pinnedID != "" {
+		if j, ok := a.trackedJourney(); ok {
+			statusDisplay += fmt.Sprintf("  [cyan]⚑ %s %s[-] %s", j.Legs[0].Line, model.FormatTime(j.LeaveAt), formatCountdown(time.Until(j.LeaveAt)))
+		}
+	}
+
+	statusDisplay += a.bookmarkStatus(time.Now())
+
+	if a.visualBellFrame > 0 {
+		borderColor = "white:red"
+	}
+
+	header := fmt.Sprintf("[%s]╔═════════════════════════════════════════════════════════════════════╗[-]\n", borderColor)
+	header += fmt.Sprintf("[%s]   [-] [::b]BERRRRLIN ROUTER [-:-:-]  %s  [cyan]%s[-]%s%s  [%s]  [-]\n",
+		borderColor, route, clock, spinner, statusDisplay, borderColor)
+	header += fmt.Sprintf("[%s]╚═════════════════════════════════════════════════════════════════════╝[-]", borderColor)
+
+	a.header.SetText(header)
+}
+
+// rowCells is what each column shows for a journey
+func (a *App) rowCells(j model.Journey, now time.Time, buffer time.Duration) map[string]string {
+	occupancy := ""
+	occPriority := map[string]int{"low": 1, "medium": 2, "high": 3}
+	for _, leg := range j.Legs {
+		if leg.Occupancy != "" && (occupancy == "" || occPriority[leg.Occupancy] > occPriority[occupancy]) {
+			occupancy = leg.Occupancy
+		}
+	}
+	occStr := ""
+	switch occupancy {
+	case "low":
+		occStr = "[green]○[-]"
+	case "medium":
+		occStr = "[yellow]◐[-]"
+	case "high":
+		occStr = "[red]●[-]"
+	}
+
+	countdownStr := formatCountdown(j.LeaveAt.Sub(now))
+	if j.Cancelled() {
+		countdownStr = "[red::b]✗ CANCELLED[-:-:-]"
+	}
+
+	return map[string]string{
+		"leave":     model.FormatTime(j.LeaveAt),
+		"arrive":    model.FormatTime(j.ArriveAt),
+		"duration":  fmt.Sprintf("(%dm)", int(j.Duration.Minutes())),
+		"wait":      fmt.Sprintf("wait:%dm", int(j.TotalWait.Minutes())),
+		"walk":      walkingLabel(j),
+		"transfers": fmt.Sprintf("⇄%d", max(len(j.Legs)-1, 0)),
+		"countdown": countdownStr,
+		"occupancy": occStr,
+		"lines":     a.lineChain(j, buffer),
+	}
+}
+
+// lineChain draws a journey's legs as colored lines between circles,
+// at-risk transfers in red
+func (a *App) lineChain(j model.Journey, buffer time.Duration) string {
+	var sb strings.Builder
+	if j.Walk != nil {
+		sb.WriteString(fmt.Sprintf("[dim]🚶%dm─[-]", int(j.Walk.Duration.Minutes())))
+	}
+	for li, leg := range j.Legs {
+		color := a.productColor(leg.Product)
+		circle := fmt.Sprintf("[%s]●[-]", color)
+
+		if li == 0 {
+			sb.WriteString(circle)
+		}
+
+		label := leg.Line
+		if leg.Direction != "" {
+			label += "▸" + shortDirection(leg.Direction)
+		}
+		if leg.Cancelled {
+			sb.WriteString(fmt.Sprintf("[red::s]─%s─[-:-:-]", tview.Escape(label)))
+		} else if leg.Night() {
+			sb.WriteString(fmt.Sprintf("[%s::i]─☾%s─[-:-:-]", color, tview.Escape(label)))
+		} else {
+			sb.WriteString(fmt.Sprintf("[%s]─%s─[-]", color, tview.Escape(label)))
+		}
+		if model.ConnectionAtRisk(j, li+1, buffer) {
+			sb.WriteString("[red::b]✗[-:-:-]")
+		} else {
+			sb.WriteString(circle)
+		}
+	}
+	return sb.String()
+}
+
+func (a *App) renderList() {
+	var sb strings.Builder
+
+	sb.WriteString(a.staleBanner())
+	if a.refreshErr != nil {
+		sb.WriteString(fmt.Sprintf("\n [white:red:b] ✗ Refresh failed [-:-:-] [red]%s[-]\n", tview.Escape(a.refreshErr.Error())))
+		if len(a.journeys) > 0 {
+			sb.WriteString(fmt.Sprintf(" [dim]Showing results from %s. Press 'r' to retry.[-]\n", model.FormatTime(a.lastUpdate)))
+		} else {
+			sb.WriteString(" [dim]Press 'r' to retry.[-]\n")
+		}
+	}
+
+	if len(a.journeys) == 0 {
+		if a.isLoading {
+			sb.WriteString(fmt.Sprintf("\n  %s [dim]Loading routes...[-]\n", a.spinner()))
+		} else if a.refreshErr == nil && a.hidden > 0 {
+			sb.WriteString(fmt.Sprintf("\n [dim]All %d journeys use avoided stations or lines. Check \"avoid\" in the config.[-]\n", a.hidden))
+		} else if a.refreshErr == nil {
+			sb.WriteString("\n [dim]No journeys found. Press 'r' to refresh.[-]\n")
+		}
+		sb.WriteString(a.departedLine())
+		a.list.SetFixed(0, 0)
+		a.setListRows(sb.String())
+		return
+	}
+
+	if a.hidden > 0 {
+		noun := "journeys"
+		if a.hidden == 1 {
+			noun = "journey"
+		}
+		sb.WriteString(fmt.Sprintf(" [dim]%d %s hidden by your avoid list[-]\n", a.hidden, noun))
+	}
+	sb.WriteString(a.departedLine())
+
+	now := time.Now()
+	buffer := a.config.TransferMargin()
+	firstRow := strings.Count(sb.String(), "\n")
+	cheapest := cheapestID(a.journeys)
+	lastCalls := lastRegular(a.journeys)
+	columns := a.columns()
+	density := a.density()
+	showChain := !slices.Contains(columns, "lines") && density != "high"
+	showSeparator := density == "low"
+	rowHeight := 1
+	if showChain {
+		rowHeight++
+	}
+	if showSeparator {
+		rowHeight++
+	}
+
+	// Every row's cells first, so the columns line up under the header
+	rows := make([]map[string]string, len(a.journeys))
+	widths := make(map[string]int)
+	for _, col := range columns {
+		widths[col] = len(columnLabels[col])
+	}
+	for i, j := range a.journeys {
+		rows[i] = a.rowCells(j, now, buffer)
+		for _, col := range columns {
+			widths[col] = max(widths[col], tview.TaggedStringWidth(rows[i][col]))
+		}
+	}
+	digits := len(strconv.Itoa(len(a.journeys)))
+	header := columnHeader(columns, widths, digits)
+
+	for i, j := range a.journeys {
+		waitMins := int(j.TotalWait.Minutes())
+		countdown := j.LeaveAt.Sub(now)
+
+		// Check statuses
+		hasDelay := false
+		hasWarning := false
+		hasTightConnection := false
+		night := false
+
+		for _, leg := range j.Legs {
+			if leg.DepDelay > 0 {
+				hasDelay = true
+			}
+			if leg.Night() {
+				night = true
+			}
+			if len(leg.ServiceStatus) > 0 {
+				hasWarning = true
+			}
+			if leg.WaitBefore > 0 && leg.WaitBefore.Minutes() <= 2 {
+				hasTightConnection = true
+			}
+		}
+
+		isSelected := i == a.selectedIdx
+		selector := "   "
+		headerStyle := ""
+
+		tagged := a.tagged[model.JourneyID(j)]
+		switch {
+		case isSelected && tagged:
+			selector = "[::r] ▸[cyan]✓[-:-:-]"
+		case isSelected:
+			selector = "[::r] ▸ [-:-:-]"
+		case tagged:
+			selector = " [cyan]✓[-] "
+		}
+		if isSelected {
+			headerStyle = "::b"
+		}
+
+		// Color based on status (static)
+		headerColor := "white"
+		if hasDelay {
+			headerColor = "yellow"
+		} else if countdown < 5*time.Minute && countdown > 0 {
+			headerColor = "red"
+		} else if waitMins <= 5 {
+			headerColor = "green"
+		} else if waitMins <= 10 {
+			headerColor = "yellow"
+		}
+		if j.Cancelled() {
+			headerColor = "red"
+			headerStyle = "::s"
+			if isSelected {
+				headerStyle = "::bs"
+			}
+		}
+
+		// New journey indicator (static)
+		newIndicator := ""
+		if j.IsNew && a.newHighlight > 0 {
+			newIndicator = " [green]★[-]"
+		}
+		if a.pinnedID != "" && model.JourneyID(j) == a.pinnedID {
+			newIndicator += " [cyan]⚑[-]"
+		}
+		if a.config.Bookmarked(model.JourneyID(j)) {
+			newIndicator += " [cyan]🔖[-]"
+		}
+		if j.Replanned {
+			newIndicator += " [magenta]↻[-]"
+		}
+
+		// Tight connection indicator (static)
+		tightStr := ""
+		if hasTightConnection {
+			tightStr = " [red]⚡[-]"
+		}
+
+		warnStr := ""
+		if hasWarning {
+			warnStr = " [red]⚠[-]"
+		}
+		if j.AccessOutage() {
+			warnStr += " [red]♿[-]"
+		}
+
+		if night {
+			warnStr += " [blue]☾[-]"
+		}
+		if line, ok := lastCalls[model.JourneyID(j)]; ok {
+			warnStr += fmt.Sprintf(" [magenta::b]last %s today[-:-:-]", tview.Escape(line))
+		}
+
+		delayStr := ""
+		if hasDelay {
+			delayStr = " [yellow]⏱[-]"
+		}
+		delayStr += a.catchBadge(j, now)
+		delayStr += a.changeBadge(j)
+		if a.better[model.JourneyID(j)] {
+			delayStr += " [green::b]▲[-:-:-]"
+		}
+
+		reliability := ""
+		if len(j.Legs) > 1 {
+			reliability = " " + reliabilityBadge(j.Reliability)
+		}
+
+		fareStr := ""
+		if a.config.Preset().FareZones {
+			fareStr = fmt.Sprintf(" [dim]%s[-]", fare.JourneyZones(j))
+			// Tickets are VBB ones, so only journeys with VBB zones are checked
+			if fare.Uncovered(a.config.Ticket, j) != "" {
+				fareStr += " [red]⊘[-]"
+			}
+		}
+		if j.Price != nil {
+			fareStr += fmt.Sprintf(" [dim]%s[-]", j.Price)
+			if model.JourneyID(j) == cheapest {
+				fareStr += " [green::b]cheapest[-:-:-]"
+			}
+		}
+
+		// Header line in the configured columns, the plain ones in the
+		// status color
+		cells := rows[i]
+		sb.WriteString(fmt.Sprintf("%s[%s%s]%*d. ", selector, headerColor, headerStyle, digits, i+1))
+		colored := true
+		for ci, col := range columns {
+			plain := slices.Contains(plainColumns, col)
+			if colored && !plain {
+				sb.WriteString("[-:-:-]")
+			}
+			if ci > 0 {
+				sb.WriteString(columnSeparator(col, cells[col] != ""))
+			}
+			if plain && !colored {
+				sb.WriteString(fmt.Sprintf("[%s%s]", headerColor, headerStyle))
+			}
+			colored = plain
+			if ci < len(columns)-1 {
+				sb.WriteString(padCell(cells[col], widths[col]))
+			} else {
+				sb.WriteString(cells[col])
+			}
+		}
+		if colored {
+			sb.WriteString("[-:-:-]")
+		}
+		sb.WriteString(fmt.Sprintf("%s%s%s%s%s%s\n", reliability, fareStr, delayStr, tightStr, warnStr, newIndicator))
+
+		// Visual route with colored circles (static), unless it's a column
+		if showChain {
+			sb.WriteString("    " + cells["lines"] + "\n")
+		}
+
+		// Separator
+		if showSeparator {
+			sb.WriteString("    [dim]" + strings.Repeat("─", 50) + "[-]\n")
+		}
+	}
+	if a.loadingLater {
+		sb.WriteString(fmt.Sprintf("  %s [dim]Loading later journeys...[-]\n", a.spinner()))
+	}
+
+	a.setListRows(header + "\n" + sb.String())
+	a.list.SetFixed(1, 0)
+
+	// Keep the selected journey's rows in view below the header, which
+	// the table's offset doesn't count
+	_, _, _, height := a.list.GetInnerRect()
+	height--
+	offset, _ := a.list.GetOffset()
+	top := firstRow + rowHeight*a.selectedIdx
+	if a.selectedIdx == 0 {
+		offset = 0
+	} else if top < offset {
+		offset = top
+	} else if top+rowHeight > offset+height {
+		offset = top + rowHeight - height
+	}
+	a.list.SetOffset(offset, 0)
+}
+
+// setListRows puts each line of text into its own table row, updating only
+// rows whose text changed and dropping rows past the end
+func (a *App) setListRows(text string) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if i >= a.list.GetRowCount() {
+			a.list.SetCell(i, 0, tview.NewTableCell(line).SetExpansion(1))
+		} else if cell := a.list.GetCell(i, 0); cell.Text != line {
+			cell.SetText(line)
+		}
+	}
+	for a.list.GetRowCount() > len(lines) {
+		a.list.RemoveRow(a.list.GetRowCount() - 1)
+	}
+}
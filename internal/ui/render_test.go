@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+)
+
+func TestShortDirection(t *testing.T) {
+	tests := []struct {
+		direction string
+		want      string
+	}{
+		{"S Westkreuz (Berlin)", "Westkreuz"},
+		{"S+U Alexanderplatz (Berlin)", "Alexanderpl…"},
+		{"U Hermannstr. (Berlin)", "Hermannstr."},
+		{"Flughafen BER", "Flughafen B…"},
+		{"Straße des 17. Juni", "Straße des …"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			if got := shortDirection(tt.direction); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkingLabel(t *testing.T) {
+	tests := []struct {
+		walking time.Duration
+		want    string
+	}{
+		{0, ""},
+		{40 * time.Second, ""},
+		{time.Minute, "walk:1m"},
+		{10*time.Minute + 30*time.Second, "walk:10m"},
+	}
+	for _, tt := range tests {
+		if got := walkingLabel(model.Journey{Walking: tt.walking}); got != tt.want {
+			t.Errorf("walkingLabel(%s) = %q, want %q", tt.walking, got, tt.want)
+		}
+	}
+}
+
+func TestSetListRows(t *testing.T) {
+	tests := []struct {
+		name         string
+		before, text string
+	}{
+		{"empty", "", "a\nb\n"},
+		{"unchanged", "a\nb\n", "a\nb\n"},
+		{"one row changed", "a\nb\nc\n", "a\nx\nc\n"},
+		{"grows", "a\n", "a\nb\nc\n"},
+		{"shrinks", "a\nb\nc\n", "a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{list: tview.NewTable()}
+			a.setListRows(tt.before)
+			var cells []*tview.TableCell
+			for i := 0; i < a.list.GetRowCount(); i++ {
+				cells = append(cells, a.list.GetCell(i, 0))
+			}
+
+			a.setListRows(tt.text)
+			want := strings.Split(strings.TrimSuffix(tt.text, "\n"), "\n")
+			if n := a.list.GetRowCount(); n != len(want) {
+				t.Fatalf("got %d rows, want %d", n, len(want))
+			}
+			for i, line := range want {
+				cell := a.list.GetCell(i, 0)
+				if cell.Text != line {
+					t.Errorf("row %d = %q, want %q", i, cell.Text, line)
+				}
+				// Rows are updated in place rather than rebuilt
+				if i < len(cells) && cell != cells[i] {
+					t.Errorf("row %d got a new cell", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// replanFromHere plans onwards from where the tracked journey ends up:
+// the station before the first connection at risk or, once on board,
+// the next stop of the current leg. The alternatives are spliced onto the
+// part already ridden and listed with the regular journeys.
+func (a *App) replanFromHere() {
+	j, ok := a.trackedJourney()
+	if !ok {
+		return
+	}
+	dest := a.config.LastDest
+	route := model.RouteName(a.config.LastOrigin, dest)
+
+	// A broken connection strands the rider where the previous leg arrives
+	buffer := a.config.TransferMargin()
+	for i := 1; i < len(j.Legs); i++ {
+		if model.ConnectionAtRisk(j, i, buffer) {
+			prev := j.Legs[i-1]
+			a.replan(route, j.Legs[:i], model.Station{ID: prev.ToID, Name: prev.To}, prev.Arrival)
+			return
+		}
+	}
+
+	now := time.Now()
+	cur := -1
+	for i, leg := range j.Legs {
+		if !now.Before(leg.Departure) && now.Before(leg.Arrival) {
+			cur = i
+			break
+		}
+	}
+	if cur < 0 {
+		if now.Before(j.LeaveAt) {
+			a.statusMsg = "Not on the way yet, nothing to re-plan"
+		} else {
+			a.statusMsg = "No leg of this journey is running right now"
+		}
+		a.statusMsgFrame = 30
+		return
+	}
+
+	leg := j.Legs[cur]
+	ridden := append([]model.Leg(nil), j.Legs[:cur]...)
+	if leg.TripID == "" {
+		a.replan(route, append(ridden, leg), model.Station{ID: leg.ToID, Name: leg.To}, leg.Arrival)
+		return
+	}
+
+	a.statusMsg = "↻ Looking up the next stop…"
+	a.statusMsgFrame = 30
+	a.goSafe(func() {
+		trip, err := a.trip(a.ctx, leg.TripID)
+		a.app.QueueUpdateDraw(func() {
+			stop, ok := nextStopover(trip, leg, now)
+			if err != nil || !ok {
+				// Fall back to where the leg ends
+				a.replan(route, append(ridden, leg), model.Station{ID: leg.ToID, Name: leg.To}, leg.Arrival)
+				return
+			}
+			part := leg
+			part.To, part.ToID = stop.Station.Name, stop.Station.ID
+			part.Arrival, part.ArrDelay = stop.Arrival, stop.ArrDelay
+			part.ArrPlatform = stop.Platform
+			a.replan(route, append(ridden, part), stop.Station, stop.Arrival)
+		})
+	})
+}
+
+// nextStopover is the first stop of the trip after now, between where the
+// leg boards and where it gets off
+func nextStopover(trip model.Trip, leg model.Leg, now time.Time) (model.Stopover, bool) {
+	boarded := false
+	for _, s := range trip.Stopovers {
+		if !boarded {
+			boarded = s.Station.ID == leg.FromID
+			continue
+		}
+		if s.Cancelled || s.Arrival.IsZero() {
+			if s.Station.ID == leg.ToID {
+				break
+			}
+			continue
+		}
+		if s.Arrival.After(now) {
+			return s, true
+		}
+		if s.Station.ID == leg.ToID {
+			break
+		}
+	}
+	return model.Stopover{}, false
+}
+
+// replan asks for journeys from the given stop at the expected arrival
+// there and splices them onto the legs already ridden
+func (a *App) replan(route string, ridden []model.Leg, from model.Station, at time.Time) {
+	if from.ID == "" {
+		a.statusMsg = "⚠ Don't know where this leg stops, can't re-plan"
+		a.statusMsgFrame = 50
+		return
+	}
+	dest := a.config.LastDest
+	if from.ID == dest.ID {
+		a.statusMsg = "This journey already gets you there"
+		a.statusMsgFrame = 30
+		return
+	}
+
+	a.statusMsg = fmt.Sprintf("↻ Re-planning from %s…", model.CleanStation(from.Name))
+	a.statusMsgFrame = 30
+	avoid := a.config.Avoid
+	transfers := a.transfers
+	opts := vbb.JourneyOptions{Products: a.products(), Transfers: &transfers, Departure: at}
+	a.goSafe(func() {
+		alts, err := a.client.Journeys(a.ctx, from.ID, dest.ID, opts)
+		if err == nil {
+			alts, _ = avoid.Filter(alts, from, dest)
+		}
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				slog.Warn("replan failed", "from", from.ID, "err", err)
+				a.statusMsg = "⚠ Re-planning failed: " + err.Error()
+				a.statusMsgFrame = 50
+				return
+			}
+			if len(alts) == 0 {
+				a.statusMsg = fmt.Sprintf("No alternatives from %s", model.CleanStation(from.Name))
+				a.statusMsgFrame = 50
+				return
+			}
+
+			a.replans = a.replans[:0]
+			for _, alt := range alts {
+				a.replans = append(a.replans, splice(ridden, alt))
+			}
+			a.replansFor = route
+			if a.journeysFor == route {
+				a.journeys = a.withReplans(a.journeys, route)
+				sortJourneys(a.journeys, a.sortMode)
+			}
+			for i, j := range a.journeys {
+				if j.Replanned {
+					a.selectedIdx = i
+					break
+				}
+			}
+			slog.Info("replanned", "route", route, "from", from.ID, "alternatives", len(alts))
+			a.statusMsg = fmt.Sprintf("↻ %d alternatives from %s", len(alts), model.CleanStation(from.Name))
+			a.statusMsgFrame = 50
+			a.dirty = true
+		})
+	})
+}
+
+// withReplans swaps the re-planned journeys of the route into the list,
+// dropping those that already arrived
+func (a *App) withReplans(journeys []model.Journey, route string) []model.Journey {
+	out := journeys[:0:0]
+	for _, j := range journeys {
+		if !j.Replanned {
+			out = append(out, j)
+		}
+	}
+	if a.replansFor != route {
+		return out
+	}
+
+	now := time.Now()
+	live := a.replans[:0]
+	for _, j := range a.replans {
+		if j.ArriveAt.After(now) {
+			live = append(live, j)
+		}
+	}
+	a.replans = live
+	return append(out, live...)
+}
+
+// splice continues the legs already ridden with an alternative journey
+func splice(ridden []model.Leg, alt model.Journey) model.Journey {
+	if len(ridden) == 0 || len(alt.Legs) == 0 {
+		alt.Replanned = true
+		return alt
+	}
+	legs := make([]model.Leg, 0, len(ridden)+len(alt.Legs))
+	legs = append(legs, ridden...)
+	legs = append(legs, alt.Legs...)
+	last := len(ridden)
+	legs[last].WaitBefore = max(0, legs[last].Departure.Sub(legs[last-1].Arrival))
+
+	j := model.Journey{
+		LeaveAt:   legs[0].Departure,
+		ArriveAt:  alt.ArriveAt,
+		Duration:  alt.ArriveAt.Sub(legs[0].Departure),
+		Legs:      legs,
+		Replanned: true,
+	}
+	for _, leg := range legs {
+		j.TotalWait += leg.WaitBefore
+	}
+	return j
+}
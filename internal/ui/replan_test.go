@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestNextStopover(t *testing.T) {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	f := vbb.NewFake(start)
+	journeys, err := f.S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The third S5 runs 2 min late: Warschauer Str. 08:24, Alexanderplatz
+	// 08:30, Berlin Hauptbahnhof 08:35, Zoo 08:43
+	leg := journeys[2].Legs[0]
+	trip, err := f.Trip(context.Background(), leg.TripID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+	alex, hbf := trip.Stopovers[1].Station.ID, trip.Stopovers[2].Station.ID
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		from, to  string // the leg's, when not the whole way
+		cancelled string
+		want      string // the stop's name, empty for none
+	}{
+		{name: "before the first stop", now: at(20), want: "S+U Alexanderplatz (Berlin)"},
+		{name: "on the way", now: at(29), want: "S+U Alexanderplatz (Berlin)"},
+		{name: "just arrived", now: at(30), want: "S+U Berlin Hauptbahnhof"},
+		{name: "cancelled stop", now: at(31), cancelled: hbf, want: "S+U Zoologischer Garten (Berlin)"},
+		{name: "boarded later", now: at(20), from: alex, want: "S+U Berlin Hauptbahnhof"},
+		{name: "past where the leg ends", now: at(31), to: alex},
+		{name: "arrived", now: at(50)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, tr := leg, trip
+			if tt.from != "" {
+				l.FromID = tt.from
+			}
+			if tt.to != "" {
+				l.ToID = tt.to
+			}
+			tr.Stopovers = append([]model.Stopover(nil), trip.Stopovers...)
+			for i := range tr.Stopovers {
+				if tr.Stopovers[i].Station.ID == tt.cancelled {
+					tr.Stopovers[i].Cancelled = true
+				}
+			}
+			s, ok := nextStopover(tr, l, tt.now)
+			if ok != (tt.want != "") || s.Station.Name != tt.want {
+				t.Errorf("got %q, %v, want %q", s.Station.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplice(t *testing.T) {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	transfer, err := vbb.NewFake(start).Transfer(0, 3*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first S5 reaches Alexanderplatz at 08:08, the U2 leaves at 08:14
+	s5, u2 := transfer.Legs[0], transfer.Legs[1]
+	u2.WaitBefore = 0
+	alt := model.Journey{LeaveAt: u2.Departure, ArriveAt: u2.Arrival, Duration: u2.Arrival.Sub(u2.Departure), Legs: []model.Leg{u2}}
+	early := u2
+	early.Departure = s5.Arrival.Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		ridden        []model.Leg
+		alt           model.Journey
+		leave, arrive time.Time
+		legs          int
+		wait          time.Duration
+	}{
+		{"nothing ridden", nil, alt, u2.Departure, u2.Arrival, 1, 0},
+		{"after the S5", []model.Leg{s5}, alt, s5.Departure, u2.Arrival, 2, 6 * time.Minute},
+		{"leaving before the S5 is in", []model.Leg{s5}, model.Journey{ArriveAt: u2.Arrival, Legs: []model.Leg{early}}, s5.Departure, u2.Arrival, 2, 0},
+		{"no alternative legs", []model.Leg{s5}, model.Journey{}, time.Time{}, time.Time{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := splice(tt.ridden, tt.alt)
+			if !j.Replanned {
+				t.Error("not marked re-planned")
+			}
+			if !j.LeaveAt.Equal(tt.leave) || !j.ArriveAt.Equal(tt.arrive) || len(j.Legs) != tt.legs {
+				t.Errorf("got %s → %s in %d legs, want %s → %s in %d", j.LeaveAt, j.ArriveAt, len(j.Legs), tt.leave, tt.arrive, tt.legs)
+			}
+			if j.Duration != j.ArriveAt.Sub(j.LeaveAt) {
+				t.Errorf("duration %s", j.Duration)
+			}
+			if j.TotalWait != tt.wait {
+				t.Errorf("waits %s, want %s", j.TotalWait, tt.wait)
+			}
+		})
+	}
+}
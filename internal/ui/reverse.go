@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+// suggestReverse runs once at startup. When the route was last used in
+// the morning of the same day and it's now past the configured hour, the
+// way back is likely wanted: say so, or with switch set, just reverse it
+// and let 'u' undo that.
+func (a *App) suggestReverse() {
+	ar := a.config.AutoReverse
+	if ar == nil || a.config.RouteChosen || a.config.LastUsed.IsZero() || a.config.LastOrigin.IsAddress() {
+		return
+	}
+	if !reverseDue(a.config.LastUsed, time.Now(), ar.Hour()) {
+		return
+	}
+
+	back := model.RouteName(a.config.LastDest, a.config.LastOrigin)
+	if !ar.Switch {
+		a.statusMsg = "↺ Heading back? Press 'R' for " + back
+		a.statusMsgFrame = 100
+		return
+	}
+	a.reverseUndo = &[2]model.Station{a.config.LastOrigin, a.config.LastDest}
+	a.config.LastOrigin, a.config.LastDest = a.config.LastDest, a.config.LastOrigin
+	config.Save(a.config)
+	slog.Info("route reversed for the afternoon", "route", back)
+	a.statusMsg = "↺ Switched to " + back + ", press 'u' to undo"
+	a.statusMsgFrame = 100
+}
+
+// reverseDue reports whether the route was last used before hour on the
+// same day and it's now hour or later, in the display timezone
+func reverseDue(last, now time.Time, hour int) bool {
+	last, now = last.In(model.DisplayZone), now.In(model.DisplayZone)
+	return last.YearDay() == now.YearDay() && last.Year() == now.Year() &&
+		last.Hour() < hour && now.Hour() >= hour
+}
+
+// undoReverse goes back to the route the automatic reversal replaced
+func (a *App) undoReverse() {
+	undo := a.reverseUndo
+	a.reverseUndo = nil
+	// Once another route was picked by hand there's nothing to undo
+	if undo == nil || a.config.LastOrigin.ID != undo[1].ID || a.config.LastDest.ID != undo[0].ID {
+		return
+	}
+	a.config.LastOrigin, a.config.LastDest = undo[0], undo[1]
+	config.Save(a.config)
+	a.statusMsg = "Back to " + model.RouteName(a.config.LastOrigin, a.config.LastDest)
+	a.statusMsgFrame = 30
+	a.refresh()
+}
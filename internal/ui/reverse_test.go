@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestReverseDue(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 30, 0, 0, model.DisplayZone)
+	}
+	tests := []struct {
+		name      string
+		last, now time.Time
+		want      bool
+	}{
+		{"morning, now afternoon", at(10, 16, 8), at(10, 16, 17), true},
+		{"right at the hour", at(10, 16, 8), at(10, 16, 14), true},
+		{"still morning", at(10, 16, 8), at(10, 16, 13), false},
+		{"last used in the afternoon", at(10, 16, 15), at(10, 16, 17), false},
+		{"yesterday morning", at(10, 15, 8), at(10, 16, 17), false},
+		{"a year ago", time.Date(2025, 10, 16, 8, 0, 0, 0, model.DisplayZone), at(10, 16, 17), false},
+		{"in the display timezone", at(10, 16, 8).UTC(), at(10, 16, 15).UTC(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reverseDue(tt.last, tt.now, 14); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
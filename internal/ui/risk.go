@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go-commute/internal/alert"
+	"go-commute/internal/model"
+)
+
+// checkConnectionRisk alerts once per endangered transfer of the tracked journey
+func (a *App) checkConnectionRisk() {
+	j, ok := a.trackedJourney()
+	if !ok {
+		return
+	}
+
+	buffer := a.config.TransferMargin()
+	var alerts []alert.Alert
+	for i := 1; i < len(j.Legs); i++ {
+		if !model.ConnectionAtRisk(j, i, buffer) {
+			continue
+		}
+		key := fmt.Sprintf("%s|%d", model.JourneyID(j), i)
+		if a.riskAlerted[key] {
+			continue
+		}
+		a.riskAlerted[key] = true
+
+		prev, next := j.Legs[i-1], j.Legs[i]
+		alerts = append(alerts, alert.Alert{
+			Kind:  "risk",
+			Title: fmt.Sprintf("Connection to %s at risk", next.Line),
+			Message: fmt.Sprintf("%s arrives %s at %s (+%d min), %s departs %s",
+				prev.Line, model.FormatTime(prev.Arrival), model.CleanStation(next.From), prev.ArrDelay/60,
+				next.Line, model.FormatTime(next.Departure)),
+			Line:  next.Line,
+			Route: model.RouteName(a.config.LastOrigin, a.config.LastDest),
+			Time:  time.Now(),
+		})
+	}
+
+	if len(alerts) > 0 {
+		a.ring("risk")
+		a.statusMsg = "⚠ " + alerts[0].Title
+		a.statusMsgFrame = 100
+		notify := a.config.Notify
+		a.goSafe(func() { alert.Dispatch(notify, alerts) })
+	}
+}
+
+// checkCancelled alerts once when the pinned journey gets called off
+func (a *App) checkCancelled(journeys []model.Journey) {
+	if a.pinnedID == "" {
+		return
+	}
+	for _, j := range journeys {
+		if model.JourneyID(j) != a.pinnedID || !j.Cancelled() {
+			continue
+		}
+		key := a.pinnedID + "|cancelled"
+		if a.riskAlerted[key] {
+			return
+		}
+		a.riskAlerted[key] = true
+
+		var line string
+		for _, leg := range j.Legs {
+			if leg.Cancelled {
+				line = leg.Line
+				break
+			}
+		}
+		alerts := []alert.Alert{{
+			Kind:  "cancelled",
+			Title: fmt.Sprintf("%s cancelled", line),
+			Message: fmt.Sprintf("Your pinned journey at %s from %s is cancelled",
+				model.FormatTime(j.LeaveAt), model.CleanStation(j.Legs[0].From)),
+			Line:  line,
+			Route: model.RouteName(a.config.LastOrigin, a.config.LastDest),
+			Time:  time.Now(),
+		}}
+		a.ring("risk")
+		a.statusMsg = "✗ " + alerts[0].Title + ", press 'P' to re-plan"
+		a.statusMsgFrame = 100
+		notify := a.config.Notify
+		a.goSafe(func() { alert.Dispatch(notify, alerts) })
+		return
+	}
+}
+
+// dropCancelled leaves out journeys with a cancelled leg
+func dropCancelled(journeys []model.Journey) []model.Journey {
+	kept := journeys[:0]
+	for _, j := range journeys {
+		if !j.Cancelled() {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestCheckConnectionRisk(t *testing.T) {
+	now := time.Now()
+	journey := func(arrDelay int, wait time.Duration) model.Journey {
+		return model.Journey{LeaveAt: now.Add(5 * time.Minute), Legs: []model.Leg{
+			{Line: "S5", TripID: "1|S5", Departure: now.Add(5 * time.Minute), Arrival: now.Add(11 * time.Minute), ArrDelay: arrDelay},
+			{Line: "U2", TripID: "1|U2", From: "S+U Alexanderplatz (Berlin)", Departure: now.Add(11*time.Minute + wait), WaitBefore: wait},
+		}}
+	}
+	tests := []struct {
+		name   string
+		j      model.Journey
+		buffer int
+		want   string // the status, empty for no alert
+	}{
+		{"on time", journey(0, time.Minute), 0, ""},
+		{"late with time to spare", journey(120, 3*time.Minute), 0, ""},
+		{"late and tight", journey(120, time.Minute), 0, "⚠ Connection to U2 at risk"},
+		{"own buffer", journey(120, 3*time.Minute), 5, "⚠ Connection to U2 at risk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{journeys: []model.Journey{tt.j}, riskAlerted: map[string]bool{}}
+			a.config.TransferBuffer = tt.buffer
+			a.checkConnectionRisk()
+			if a.statusMsg != tt.want {
+				t.Fatalf("status %q, want %q", a.statusMsg, tt.want)
+			}
+
+			// Once per transfer
+			a.statusMsg = ""
+			a.checkConnectionRisk()
+			if a.statusMsg != "" {
+				t.Errorf("alerted twice: %q", a.statusMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// defaultStay is what 'T' suggests when the config has no round_trip_stay
+const defaultStay = "2h"
+
+// planRoundTrip takes the selected journey as the way there and asks how
+// long the stay is, or when to head back, to plan the way home
+func (a *App) planRoundTrip() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	out := a.journeys[a.selectedIdx]
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+	if origin.IsAddress() {
+		a.statusMsg = "Can't plan back to an address, only from one"
+		a.statusMsgFrame = 30
+		return
+	}
+
+	initial := a.config.RoundTripStay
+	if initial == "" {
+		initial = defaultStay
+	}
+	a.prompt("Round trip", "Stay (2h30m) or head back at (18:30): ", initial, func(text string) {
+		leave, err := parseStay(text, out.ArriveAt)
+		if err != nil {
+			a.statusMsg = "⚠ " + err.Error()
+			a.statusMsgFrame = 50
+			return
+		}
+		if !strings.Contains(text, ":") && text != a.config.RoundTripStay {
+			a.config.RoundTripStay = text
+			config.Save(a.config)
+		}
+
+		a.statusMsg = "⇄ Planning the way back…"
+		a.statusMsgFrame = 30
+		a.goSafe(func() {
+			back, err := a.client.Journeys(a.ctx, dest.ID, origin.ID, vbb.JourneyOptions{Departure: leave})
+			a.app.QueueUpdateDraw(func() {
+				a.showRoundTrip(out, back, leave, err)
+			})
+		})
+	})
+}
+
+// parseStay turns "2h30m" into arrival plus the stay, and "18:30" into that
+// time after arrival
+func parseStay(text string, arrival time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if at, err := time.ParseInLocation("15:04", text, model.DisplayZone); err == nil {
+		local := arrival.In(model.DisplayZone)
+		leave := time.Date(local.Year(), local.Month(), local.Day(), at.Hour(), at.Minute(), 0, 0, model.DisplayZone)
+		if leave.Before(arrival) {
+			leave = leave.AddDate(0, 0, 1)
+		}
+		return leave, nil
+	}
+	stay, err := time.ParseDuration(text)
+	if err != nil || stay < 0 {
+		return time.Time{}, fmt.Errorf("%q is neither a stay like 2h30m nor a time like 18:30", text)
+	}
+	return arrival.Add(stay), nil
+}
+
+func (a *App) showRoundTrip(out model.Journey, back []model.Journey, leave time.Time, err error) {
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[yellow::b]There: %s → %s[-:-:-]\n",
+		model.CleanStation(origin.Name), model.CleanStation(dest.Name)))
+	sb.WriteString(a.journeySummary(out) + "\n\n")
+
+	sb.WriteString(fmt.Sprintf("[yellow::b]Back: %s → %s[-:-:-]  [dim]from %s on[-]\n",
+		model.CleanStation(dest.Name), model.CleanStation(origin.Name), model.FormatTime(leave)))
+	switch {
+	case err != nil:
+		sb.WriteString(fmt.Sprintf("[red]%s[-]\n", tview.Escape(err.Error())))
+	case len(back) == 0:
+		sb.WriteString("[dim]No connections found[-]\n")
+	default:
+		for i, j := range back {
+			if i == 3 {
+				break
+			}
+			sb.WriteString(a.journeySummary(j) + "\n")
+		}
+		first := back[0]
+		sb.WriteString(fmt.Sprintf("\n[green::b]Back by %s[-:-:-], %s away in total\n",
+			model.FormatTime(first.ArriveAt), formatSpan(first.ArriveAt.Sub(out.LeaveAt))))
+	}
+	sb.WriteString("\n[dim]Press ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Round Trip ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("roundtrip")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("roundtrip", view, true, false)
+	a.pages.SwitchToPage("roundtrip")
+	a.app.SetFocus(view)
+}
+
+// journeySummary is a one-line overview: times, duration and the lines
+func (a *App) journeySummary(j model.Journey) string {
+	var lines []string
+	for _, leg := range j.Legs {
+		lines = append(lines, fmt.Sprintf("[%s]%s[-]", a.productColor(leg.Product), leg.Line))
+	}
+	return fmt.Sprintf("  %s → %s  %3dmin  %s",
+		model.FormatTime(j.LeaveAt), model.FormatTime(j.ArriveAt), int(j.Duration.Minutes()),
+		strings.Join(lines, " › "))
+}
+
+// formatSpan renders a duration as "3h05m" or "45min"
+func formatSpan(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dmin", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
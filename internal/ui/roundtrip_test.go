@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestParseStay(t *testing.T) {
+	arrival := time.Date(2026, 10, 16, 9, 15, 0, 0, model.DisplayZone)
+	tests := []struct {
+		text string
+		want time.Time
+		ok   bool
+	}{
+		{"2h30m", arrival.Add(150 * time.Minute), true},
+		{" 45m ", arrival.Add(45 * time.Minute), true},
+		{"0s", arrival, true},
+		{"18:30", time.Date(2026, 10, 16, 18, 30, 0, 0, model.DisplayZone), true},
+		{"08:00", time.Date(2026, 10, 17, 8, 0, 0, 0, model.DisplayZone), true},
+		{"-1h", time.Time{}, false},
+		{"after lunch", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parseStay(tt.text, arrival)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// dumpScreen writes what's on screen right now to a text file in the
+// working directory, for bug reports and pasting into a chat. With ansi
+// the colors come along as escape codes.
+func (a *App) dumpScreen(ansi bool) {
+	if a.screen == nil {
+		return
+	}
+	width, height := a.screen.Size()
+	lines := make([]string, 0, height)
+	for y := 0; y < height; y++ {
+		var line strings.Builder
+		var last tcell.Style
+		for x := 0; x < width; {
+			r, combining, style, w := a.screen.GetContent(x, y)
+			if ansi && (x == 0 || style != last) {
+				line.WriteString(sgr(style))
+				last = style
+			}
+			if r == 0 {
+				r = ' '
+			}
+			line.WriteRune(r)
+			for _, c := range combining {
+				line.WriteRune(c)
+			}
+			x += max(w, 1)
+		}
+		if ansi {
+			lines = append(lines, line.String()+"\x1b[0m")
+		} else {
+			lines = append(lines, strings.TrimRight(line.String(), " "))
+		}
+	}
+
+	ext := "txt"
+	if ansi {
+		ext = "ans"
+	} else {
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	name := fmt.Sprintf("berrrr-screen-%s.%s", time.Now().Format("20060102-150405"), ext)
+	if err := os.WriteFile(name, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		a.statusMsg = "Screenshot failed: " + err.Error()
+	} else {
+		a.statusMsg = "Saved " + name
+	}
+	a.statusMsgFrame = 30
+}
+
+// sgr is the escape sequence switching the terminal to a cell's style
+func sgr(style tcell.Style) string {
+	fg, bg, attr := style.Decompose()
+	codes := []string{"0"}
+	for _, a := range []struct {
+		mask tcell.AttrMask
+		code string
+	}{
+		{tcell.AttrBold, "1"}, {tcell.AttrDim, "2"}, {tcell.AttrItalic, "3"}, {tcell.AttrUnderline, "4"},
+		{tcell.AttrBlink, "5"}, {tcell.AttrReverse, "7"}, {tcell.AttrStrikeThrough, "9"},
+	} {
+		if attr&a.mask != 0 {
+			codes = append(codes, a.code)
+		}
+	}
+	if fg.Valid() {
+		r, g, b := fg.RGB()
+		codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	if bg.Valid() {
+		r, g, b := bg.RGB()
+		codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// textScreen shows fixed lines in one style
+type textScreen struct {
+	tcell.Screen
+	lines []string
+	style tcell.Style
+}
+
+func (s textScreen) Size() (int, int) { return 6, len(s.lines) }
+
+func (s textScreen) GetContent(x, y int) (rune, []rune, tcell.Style, int) {
+	line := []rune(s.lines[y])
+	if x >= len(line) {
+		return ' ', nil, tcell.StyleDefault, 1
+	}
+	return line[x], nil, s.style, 1
+}
+
+func TestSGR(t *testing.T) {
+	red, navy := tcell.NewRGBColor(255, 0, 0), tcell.NewRGBColor(0, 0, 128)
+	tests := []struct {
+		name  string
+		style tcell.Style
+		want  string
+	}{
+		{"default", tcell.StyleDefault, "\x1b[0m"},
+		{"bold", tcell.StyleDefault.Bold(true), "\x1b[0;1m"},
+		{"colors", tcell.StyleDefault.Foreground(red).Background(navy), "\x1b[0;38;2;255;0;0;48;2;0;0;128m"},
+		{"dim and reverse", tcell.StyleDefault.Dim(true).Reverse(true).Foreground(red), "\x1b[0;2;7;38;2;255;0;0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sgr(tt.style); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDumpScreen(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	screen := textScreen{lines: []string{"S5", "08:02", "", ""}, style: tcell.StyleDefault.Bold(true)}
+	tests := []struct {
+		name string
+		ansi bool
+		ext  string
+		want string
+	}{
+		{"text", false, ".txt", "S5\n08:02\n"},
+		{"colors", true, ".ans", "\x1b[0;1mS5\x1b[0m    \x1b[0m\n\x1b[0;1m08:02\x1b[0m \x1b[0m\n\x1b[0m      \x1b[0m\n\x1b[0m      \x1b[0m\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{screen: screen}
+			a.dumpScreen(tt.ansi)
+			files, _ := filepath.Glob("berrrr-screen-*" + tt.ext)
+			if len(files) != 1 {
+				t.Fatalf("got files %v, status %q", files, a.statusMsg)
+			}
+			defer os.Remove(files[0])
+			data, err := os.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %q, want %q", data, tt.want)
+			}
+			if a.statusMsg != "Saved "+files[0] {
+				t.Errorf("status %q", a.statusMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// searchDebounce is how long typing has to pause before stations are looked up
+const searchDebounce = 250 * time.Millisecond
+
+// searchLimit is how many stations the search list shows
+const searchLimit = 10
+
+// queueSearch matches stations while typing. The cached stop list answers
+// instantly; the API is only asked, debounced, when the list has no match
+// or hasn't been downloaded in full. Each keystroke cancels the pending
+// timer and any request still in flight.
+func (a *App) queueSearch(text string) {
+	if a.searchTimer != nil {
+		a.searchTimer.Stop()
+	}
+	if a.searchCancel != nil {
+		a.searchCancel()
+		a.searchCancel = nil
+	}
+	if len(text) < 2 {
+		return
+	}
+
+	local := a.stops.Search(text, searchLimit)
+	if len(local) > 0 {
+		a.showSearchResults(local, text)
+		if !a.stops.Stale() {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.searchCancel = cancel
+	a.searchTimer = time.AfterFunc(searchDebounce, func() {
+		defer a.recoverPanic()
+		stations, err := a.client.SearchStations(ctx, text)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			slog.Warn("station search failed", "query", text, "err", err)
+			return
+		}
+		if a.stops.Learn(stations) {
+			a.goWrite(func() { a.stops.Save() })
+		}
+		a.app.QueueUpdateDraw(func() {
+			// A newer keystroke may have landed while the request was running
+			if ctx.Err() != nil || a.searchInput.GetText() != text {
+				return
+			}
+			a.showSearchResults(mergeStations(local, stations), text)
+		})
+	})
+}
+
+func (a *App) showSearchResults(stations []model.Station, text string) {
+	a.searchResults = stations
+	a.searchList.Clear()
+	a.addLocationItem()
+	a.addAliasItems(text)
+	for _, s := range stations {
+		station := s
+		a.searchList.AddItem(s.Name, "", 0, func() {
+			a.selectStation(station)
+		})
+	}
+}
+
+// mergeStations puts the API's answer first and keeps the local matches it
+// didn't return
+func mergeStations(local, remote []model.Station) []model.Station {
+	seen := make(map[string]bool, len(remote))
+	merged := append([]model.Station(nil), remote...)
+	for _, s := range remote {
+		seen[s.ID] = true
+	}
+	for _, s := range local {
+		if !seen[s.ID] && len(merged) < searchLimit {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// syncStops downloads the provider's stop list once it is missing or old
+func (a *App) syncStops() {
+	lister, ok := a.client.(vbb.StopLister)
+	if !ok || !a.stops.Stale() {
+		return
+	}
+	a.goSafe(func() {
+		stations, err := lister.AllStations(a.ctx)
+		if err != nil {
+			slog.Info("stop list unavailable, searching online", "err", err)
+			return
+		}
+		slog.Debug("stop list downloaded", "stops", len(stations))
+		a.stops.Replace(stations)
+		a.goWrite(func() { a.stops.Save() })
+	})
+}
+
+// addLocationItem offers the configured location as an origin, walking to
+// whichever stop suits each journey
+func (a *App) addLocationItem() {
+	loc := a.config.Location
+	if loc == nil || a.searchTarget != "origin" {
+		return
+	}
+	a.searchList.AddItem("📍 My location", "", 0, func() {
+		a.selectStation(model.AddressAt("My location", *loc))
+	})
+}
+
+// addAliasItems lists the config's aliases matching what was typed above
+// the API's stations
+func (a *App) addAliasItems(text string) {
+	for _, alias := range a.config.MatchAliases(text) {
+		alias, target := alias, a.config.Aliases[alias]
+		a.searchList.AddItem(fmt.Sprintf("⌂ %s", alias), "", 0, func() {
+			a.selectAlias(alias, target)
+		})
+	}
+}
+
+// selectAlias picks the station an alias stands for, looked up like --from
+// does so it keeps the station's own name rather than the alias
+func (a *App) selectAlias(alias, target string) {
+	a.goSafe(func() {
+		station, err := vbb.Resolve(a.ctx, a.client, target)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.statusMsg = fmt.Sprintf("⚠ Alias %q: %v", alias, err)
+				a.statusMsgFrame = 50
+				return
+			}
+			a.selectStation(station)
+		})
+	})
+}
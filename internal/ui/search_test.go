@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+	"go-commute/internal/stops"
+	"go-commute/internal/vbb"
+)
+
+// searchClient records the queries that reach the API
+type searchClient struct {
+	vbb.TransitClient
+	mu      sync.Mutex
+	queries []string
+}
+
+func (c *searchClient) SearchStations(ctx context.Context, query string) ([]model.Station, error) {
+	c.mu.Lock()
+	c.queries = append(c.queries, query)
+	c.mu.Unlock()
+	return nil, nil
+}
+
+func (c *searchClient) sent() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.queries)
+}
+
+func TestQueueSearch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name  string
+		typed []string
+		want  []string
+	}{
+		{"only the last keystroke", []string{"al", "ale", "alex"}, []string{"alex"}},
+		{"too short", []string{"a"}, nil},
+		{"deleted back to one letter", []string{"al", "a"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			client := &searchClient{}
+			a := &App{ctx: ctx, client: client, app: tview.NewApplication(), stops: stops.Load("test")}
+
+			for _, text := range tt.typed {
+				a.queueSearch(text)
+			}
+			time.Sleep(2 * searchDebounce)
+			if got := client.sent(); !slices.Equal(got, tt.want) {
+				t.Errorf("searched for %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeStations(t *testing.T) {
+	station := func(ids ...string) []model.Station {
+		var stations []model.Station
+		for _, id := range ids {
+			stations = append(stations, model.Station{ID: id})
+		}
+		return stations
+	}
+	many := make([]string, searchLimit)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+
+	tests := []struct {
+		name          string
+		local, remote []model.Station
+		want          []model.Station
+	}{
+		{"remote first", station("1", "2"), station("3"), station("3", "1", "2")},
+		{"no duplicates", station("1", "2"), station("2", "3"), station("2", "3", "1")},
+		{"offline", station("1"), nil, station("1")},
+		{"capped", station("1"), station(many...), station(many...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeStations(tt.local, tt.remote); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
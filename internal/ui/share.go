@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+	"go-commute/internal/qr"
+	"go-commute/internal/share"
+	"go-commute/internal/vbb"
+)
+
+// yankJourney copies the itinerary of the selected journey, or of the
+// tagged ones, to the clipboard
+func (a *App) yankJourney() {
+	journeys := a.batch()
+	if len(journeys) == 0 {
+		return
+	}
+	var texts []string
+	for _, j := range journeys {
+		texts = append(texts, share.Itinerary(j, a.config.LastOrigin, a.config.LastDest))
+	}
+	what := "journey"
+	if len(journeys) > 1 {
+		what = fmt.Sprintf("%d journeys", len(journeys))
+	}
+	if via, err := a.copyToClipboard(strings.Join(texts, "\n")); err != nil {
+		a.statusMsg = "Copy failed: " + err.Error()
+	} else {
+		a.statusMsg = "Copied " + what + " (" + via + ")"
+	}
+	a.statusMsgFrame = 30
+}
+
+// copyToClipboard copies text, writing the OSC 52 fallback while the app is
+// suspended so the sequence can't end up in the middle of a redraw
+func (a *App) copyToClipboard(text string) (string, error) {
+	return share.CopyToClipboard(text, func(seq string) error {
+		var err error
+		a.app.Suspend(func() {
+			_, err = os.Stdout.WriteString(seq)
+		})
+		return err
+	})
+}
+
+// quitWithJourney leaves the TUI and has the selected journey printed to
+// stdout, so the plan stays in the scrollback
+func (a *App) quitWithJourney() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	a.farewell = share.Itinerary(a.journeys[a.selectedIdx], a.config.LastOrigin, a.config.LastDest)
+	a.shutdown()
+}
+
+// Farewell is what to print once the TUI is gone, if anything
+func (a *App) Farewell() string {
+	return a.farewell
+}
+
+// exportICS writes the selected journey, or the tagged ones, to an .ics
+// file in the working directory
+func (a *App) exportICS() {
+	journeys := a.batch()
+	if len(journeys) == 0 {
+		return
+	}
+	name := batchName(journeys, "ics")
+	if err := os.WriteFile(name, []byte(share.Calendar(journeys, a.config.LastOrigin, a.config.LastDest)), 0644); err != nil {
+		a.statusMsg = "Export failed: " + err.Error()
+	} else {
+		a.statusMsg = "Saved " + name
+	}
+	a.statusMsgFrame = 30
+}
+
+// exportGPX writes the selected journey's route to a .gpx file in the
+// working directory, following the lines' shapes where the API has them
+func (a *App) exportGPX() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	origin, dest := a.config.LastOrigin, a.config.LastDest
+	finder, _ := a.client.(vbb.PathFinder)
+
+	a.statusMsg = "Fetching the route shapes…"
+	a.statusMsgFrame = 30
+	a.goSafe(func() {
+		paths := make([][]model.Coordinates, len(j.Legs))
+		var wg sync.WaitGroup
+		for i, leg := range j.Legs {
+			if finder == nil || leg.TripID == "" {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, leg model.Leg) {
+				defer wg.Done()
+				defer a.recoverPanic()
+				path, err := finder.TripPath(a.ctx, leg.TripID)
+				if err != nil {
+					slog.Debug("no route shape, drawing a straight line", "trip", leg.TripID, "err", err)
+					return
+				}
+				paths[i] = model.LegPath(path, leg.FromID, leg.ToID)
+			}(i, leg)
+		}
+		wg.Wait()
+
+		name := fmt.Sprintf("berrrr-%s.gpx", j.LeaveAt.In(model.DisplayZone).Format("20060102-1504"))
+		err := os.WriteFile(name, []byte(share.GPX(j, origin, dest, paths)), 0644)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.statusMsg = "Export failed: " + err.Error()
+			} else {
+				a.statusMsg = "Saved " + name
+			}
+			a.statusMsgFrame = 30
+		})
+	})
+}
+
+// showQR renders the selected journey's itinerary as a scannable QR code
+func (a *App) showQR() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	text := share.Itinerary(j, a.config.LastOrigin, a.config.LastDest)
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Scan to take this journey along ")
+
+	code, err := qr.Encode([]byte(text))
+	if err != nil {
+		view.SetText(fmt.Sprintf("\n[red]%s[-]\n\n%s", err, tview.Escape(text)))
+	} else {
+		view.SetText(code.HalfBlocks() + "\n[dim]Press ESC or 'b' to go back[-]")
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("qr")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("qr", view, true, false)
+	a.pages.SwitchToPage("qr")
+	a.app.SetFocus(view)
+}
+
+// openWalkingMap shows the walk to the first stop on OpenStreetMap
+func (a *App) openWalkingMap() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	w := a.journeys[a.selectedIdx].Walk
+	if w == nil {
+		a.statusMsg = "This journey starts at a stop, nothing to walk"
+		a.statusMsgFrame = 30
+		return
+	}
+	a.openLink(share.WalkingMapURL(w.FromCoords, w.ToCoords), "walking map")
+}
+
+// openJourneyMap shows the selected journey in BVG Fahrinfo or on Google
+// Maps, for when the graphical map is needed after all
+func (a *App) openJourneyMap() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	if a.config.JourneyLinks == "bvg" {
+		a.openLink(share.FahrinfoURL(j), "journey in Fahrinfo")
+	} else {
+		a.openLink(share.GoogleTransitURL(j), "journey in Google Maps")
+	}
+}
+
+// openLink opens a link in the browser. Over SSH the browser would open
+// on the wrong machine, so the link is copied instead.
+func (a *App) openLink(url, what string) {
+	a.statusMsgFrame = 30
+	if os.Getenv("SSH_CONNECTION") == "" {
+		if err := share.OpenURL(url); err == nil {
+			a.statusMsg = "Opened the " + what
+			return
+		}
+	}
+	if via, err := a.copyToClipboard(url); err != nil {
+		a.statusMsg = "Couldn't open the " + what + ": " + err.Error()
+	} else {
+		a.statusMsg = "Copied the link to the " + what + " (" + via + ")"
+	}
+}
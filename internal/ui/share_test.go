@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+	"go-commute/internal/share"
+)
+
+func TestQuitWithJourney(t *testing.T) {
+	leave := time.Date(2026, 10, 16, 8, 2, 0, 0, model.DisplayZone)
+	journeys := []model.Journey{{LeaveAt: leave, ArriveAt: leave.Add(19 * time.Minute), Legs: []model.Leg{{
+		Line: "S5", From: "S+U Warschauer Str. (Berlin)", To: "S+U Zoologischer Garten (Berlin)",
+		Departure: leave, Arrival: leave.Add(19 * time.Minute),
+	}}}}
+
+	tests := []struct {
+		name     string
+		journeys []model.Journey
+		quits    bool
+	}{
+		{"selected journey", journeys, true},
+		{"nothing listed", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{app: tview.NewApplication(), stopChan: make(chan struct{}), journeys: tt.journeys}
+			a.ctx, a.cancel = context.WithCancel(context.Background())
+			a.config.LastOrigin = model.Station{Name: "S+U Warschauer Str. (Berlin)"}
+			a.config.LastDest = model.Station{Name: "S+U Zoologischer Garten (Berlin)"}
+
+			a.quitWithJourney()
+			if a.stopping != tt.quits {
+				t.Errorf("quit: %v, want %v", a.stopping, tt.quits)
+			}
+			want := ""
+			if tt.quits {
+				want = share.Itinerary(tt.journeys[0], a.config.LastOrigin, a.config.LastDest)
+			}
+			if got := a.Farewell(); got != want {
+				t.Errorf("prints %q, want %q", got, want)
+			}
+		})
+	}
+}
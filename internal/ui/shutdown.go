@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go-commute/internal/config"
+)
+
+// handleSignals shuts down cleanly on Ctrl-C from outside the TUI, a kill,
+// or the terminal going away
+func (a *App) handleSignals() func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	a.goSafe(func() {
+		select {
+		case sig := <-sigs:
+			slog.Info("shutting down", "signal", sig.String())
+			a.app.QueueUpdate(a.shutdown)
+		case <-a.stopChan:
+		}
+	})
+	return func() { signal.Stop(sigs) }
+}
+
+// shutdown stops the tickers, cancels requests still in flight and stops
+// tview, which restores the terminal. It must run on the event loop and may
+// be called more than once.
+func (a *App) shutdown() {
+	if a.stopping {
+		return
+	}
+	a.stopping = true
+	close(a.stopChan)
+	a.cancel()
+	if a.searchTimer != nil {
+		a.searchTimer.Stop()
+	}
+	a.app.Stop()
+}
+
+// goWrite runs a history or diary write in the background; flush waits
+// for it before exiting
+func (a *App) goWrite(f func()) {
+	a.writes.Add(1)
+	a.goSafe(func() {
+		defer a.writes.Done()
+		f()
+	})
+}
+
+// flush waits for pending writes and saves everything once more, so
+// nothing recorded during the session is lost on the way out
+func (a *App) flush() {
+	a.writes.Wait()
+	config.Save(a.config)
+	if err := a.history.Save(); err != nil {
+		slog.Warn("saving history failed", "err", err)
+	}
+	if err := a.diary.Save(); err != nil {
+		slog.Warn("saving diary failed", "err", err)
+	}
+}
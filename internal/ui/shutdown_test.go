@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"go-commute/internal/config"
+)
+
+func TestShutdown(t *testing.T) {
+	a := &App{app: tview.NewApplication(), stopChan: make(chan struct{})}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+	fired := false
+	a.searchTimer = time.AfterFunc(time.Hour, func() { fired = true })
+
+	a.shutdown()
+	a.shutdown() // a signal and a key press at once
+	select {
+	case <-a.stopChan:
+	default:
+		t.Error("the tickers weren't stopped")
+	}
+	if a.ctx.Err() == nil {
+		t.Error("requests in flight weren't cancelled")
+	}
+	if a.searchTimer.Stop() || fired {
+		t.Error("the pending search is still queued")
+	}
+}
+
+func TestFlush(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	a := NewApp(config.Config{}, nil)
+	defer a.cancel()
+
+	var written atomic.Bool
+	a.goWrite(func() {
+		time.Sleep(50 * time.Millisecond)
+		written.Store(true)
+	})
+	a.flush()
+	if !written.Load() {
+		t.Error("flush didn't wait for the pending write")
+	}
+	if _, err := os.Stat(config.Path()); err != nil {
+		t.Errorf("config not saved: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"go-commute/internal/model"
+)
+
+func reliabilityBadge(p float64) string {
+	color := "green"
+	if p < 0.75 {
+		color = "red"
+	} else if p < 0.9 {
+		color = "yellow"
+	}
+	return fmt.Sprintf("[%s]%d%%[-]", color, int(p*100+0.5))
+}
+
+// missDot is a change's risk as a colored dot with the chance of missing it
+func missDot(p float64) string {
+	color := "green"
+	if p >= 0.25 {
+		color = "red"
+	} else if p >= 0.1 {
+		color = "yellow"
+	}
+	return fmt.Sprintf("[%s]●[-] [dim]%d%% miss[-]", color, int(p*100+0.5))
+}
+
+// Sort modes for the journey list
+var sortModes = []string{"departure", "reliability", "price"}
+
+// sortJourneys orders journeys by the given mode, stable on departure time.
+// Cancelled journeys always go last.
+func sortJourneys(journeys []model.Journey, mode string) {
+	sort.SliceStable(journeys, func(i, j int) bool {
+		a, b := journeys[i], journeys[j]
+		if ac, bc := a.Cancelled(), b.Cancelled(); ac != bc {
+			return bc
+		}
+		switch mode {
+		case "reliability":
+			if a.Reliability != b.Reliability {
+				return a.Reliability > b.Reliability
+			}
+		case "price":
+			// Unpriced journeys go after priced ones
+			if (a.Price == nil) != (b.Price == nil) {
+				return b.Price == nil
+			}
+			if a.Price != nil && a.Price.Amount != b.Price.Amount {
+				return a.Price.Amount < b.Price.Amount
+			}
+		}
+		if a.LeaveAt.Equal(b.LeaveAt) {
+			return a.TotalWait < b.TotalWait
+		}
+		return a.LeaveAt.Before(b.LeaveAt)
+	})
+}
+
+// cycleSort switches to the next sort mode and re-sorts the list
+func (a *App) cycleSort() {
+	next := sortModes[0]
+	for i, m := range sortModes {
+		if m == a.sortMode {
+			next = sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	a.setSort(next)
+	a.rememberView()
+}
+
+// setSort re-sorts the list by the given mode
+func (a *App) setSort(mode string) {
+	a.sortMode = mode
+	sortJourneys(a.journeys, a.sortMode)
+	a.selectedIdx = 0
+	a.statusMsg = "Sorted by " + a.sortMode
+	a.statusMsgFrame = 30
+}
+
+// cheapestID is the journey with the lowest price, if prices differ at all
+func cheapestID(journeys []model.Journey) string {
+	var cheapest *model.Journey
+	varied := false
+	for i := range journeys {
+		j := &journeys[i]
+		if j.Price == nil || j.Cancelled() {
+			continue
+		}
+		if cheapest != nil && j.Price.Amount != cheapest.Price.Amount {
+			varied = true
+		}
+		if cheapest == nil || j.Price.Amount < cheapest.Price.Amount {
+			cheapest = j
+		}
+	}
+	if cheapest == nil || !varied {
+		return ""
+	}
+	return model.JourneyID(*cheapest)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+func TestSortJourneys(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	// The third to seventh S5, the fifth of them cancelled
+	fixture, err := vbb.NewFake(now).S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture = fixture[2:7]
+	reliability := []float64{0.7, 0.99, 1, 0.9, 0.99}
+	prices := []*model.Price{nil, {Amount: 3.8}, nil, {Amount: 3.8}, {Amount: 2.4}}
+	for i := range fixture {
+		fixture[i].Reliability, fixture[i].Price = reliability[i], prices[i]
+	}
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{"departure", []string{vbb.S5ID(2), vbb.S5ID(3), vbb.S5ID(5), vbb.S5ID(6), vbb.S5ID(4)}},
+		{"reliability", []string{vbb.S5ID(3), vbb.S5ID(6), vbb.S5ID(5), vbb.S5ID(2), vbb.S5ID(4)}},
+		{"price", []string{vbb.S5ID(6), vbb.S5ID(3), vbb.S5ID(5), vbb.S5ID(2), vbb.S5ID(4)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			journeys := slices.Clone(fixture)
+			slices.Reverse(journeys)
+			sortJourneys(journeys, tt.mode)
+			if got := journeyIDs(journeys); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func journeyIDs(journeys []model.Journey) []string {
+	ids := make([]string, len(journeys))
+	for i, j := range journeys {
+		ids[i] = model.JourneyID(j)
+	}
+	return ids
+}
+
+func TestReliabilityBadge(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want string
+	}{
+		{1, "[green]100%[-]"},
+		{0.9, "[green]90%[-]"},
+		{0.895, "[yellow]90%[-]"},
+		{0.75, "[yellow]75%[-]"},
+		{0.5, "[red]50%[-]"},
+	}
+	for _, tt := range tests {
+		if got := reliabilityBadge(tt.p); got != tt.want {
+			t.Errorf("reliabilityBadge(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCycleSort(t *testing.T) {
+	a := &App{sortMode: sortModes[0], selectedIdx: 2}
+	for _, want := range append(sortModes[1:], sortModes[0]) {
+		a.cycleSort()
+		if a.sortMode != want {
+			t.Fatalf("sorted by %q, want %q", a.sortMode, want)
+		}
+		if a.selectedIdx != 0 || a.statusMsg != "Sorted by "+want {
+			t.Errorf("selection %d, status %q", a.selectedIdx, a.statusMsg)
+		}
+	}
+}
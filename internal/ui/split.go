@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// splitMaxAge is how often both boards are fetched again while shown
+const splitMaxAge = 30 * time.Second
+
+// splitView is the origin's departures next to the destination's
+// arrivals, for timing a pickup at the other end
+type splitView struct {
+	origin, dest model.Station
+	left, right  *tview.TextView
+
+	departures, arrivals []model.Departure
+	depErr, arrErr       error
+	fetched              time.Time
+	fetching             bool
+}
+
+// showSplit opens the two boards side by side
+func (a *App) showSplit() {
+	if a.config.LastOrigin.IsAddress() {
+		a.statusMsg = "An address has no departure board"
+		a.statusMsgFrame = 30
+		return
+	}
+	s := &splitView{
+		origin: a.config.LastOrigin,
+		dest:   a.config.LastDest,
+		left:   tview.NewTextView().SetDynamicColors(true),
+		right:  tview.NewTextView().SetDynamicColors(true),
+	}
+	s.left.SetBorder(true).SetTitle(" Departures · " + model.CleanStation(s.origin.Name) + " ")
+	s.right.SetBorder(true).SetTitle(" Arrivals · " + model.CleanStation(s.dest.Name) + " ")
+	a.split = s
+
+	hint := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	hint.SetText("[dim]r Refresh   ESC or 'b' to go back[-]")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(s.left, 0, 1, true).
+			AddItem(s.right, 0, 1, false), 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	s.left.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q':
+			a.split = nil
+			a.pages.RemovePage("split")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		case event.Rune() == 'r':
+			s.fetched = time.Time{}
+			a.pollSplit()
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("split", layout, true, false)
+	a.pages.SwitchToPage("split")
+	a.app.SetFocus(s.left)
+	a.renderSplit()
+	a.pollSplit()
+}
+
+// pollSplit fetches both boards together when they're stale
+func (a *App) pollSplit() {
+	s := a.split
+	if s == nil || s.fetching || time.Since(s.fetched) < splitMaxAge {
+		return
+	}
+	s.fetching = true
+	a.goSafe(func() {
+		var deps, arrs []model.Departure
+		var depErr, arrErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer a.recoverPanic()
+			deps, depErr = a.client.Departures(a.ctx, s.origin.ID)
+		}()
+		if lister, ok := a.client.(vbb.ArrivalLister); ok {
+			arrs, arrErr = lister.Arrivals(a.ctx, s.dest.ID)
+		} else {
+			arrErr = errors.New("arrivals aren't available from this provider")
+		}
+		wg.Wait()
+		a.app.QueueUpdateDraw(func() {
+			s.fetching = false
+			s.fetched = time.Now()
+			s.departures, s.depErr = deps, depErr
+			s.arrivals, s.arrErr = arrs, arrErr
+			if depErr != nil || arrErr != nil {
+				slog.Warn("split boards failed", "departures", depErr, "arrivals", arrErr)
+			}
+			if a.split == s {
+				a.renderSplit()
+			}
+		})
+	})
+}
+
+// renderSplit redraws both boards, the countdowns moving on between fetches
+func (a *App) renderSplit() {
+	s := a.split
+	if s == nil {
+		return
+	}
+	s.left.SetText(a.boardText(s.departures, s.depErr, s.fetched, "to"))
+	s.right.SetText(a.boardText(s.arrivals, s.arrErr, s.fetched, "from"))
+}
+
+func (a *App) boardText(deps []model.Departure, err error, fetched time.Time, towards string) string {
+	switch {
+	case err != nil:
+		return fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error()))
+	case fetched.IsZero():
+		return "[dim]Loading…[-]"
+	}
+
+	var sb strings.Builder
+	now := time.Now()
+	for _, d := range deps {
+		if d.When.Before(now.Add(-time.Minute)) {
+			continue
+		}
+		color := a.productColor(d.Product)
+		line := fmt.Sprintf("%s [%s::b]%-5s[-:-:-] %s [dim]%s[-] %s",
+			model.FormatTime(d.When), color, tview.Escape(d.Line), formatCountdown(d.When.Sub(now)),
+			towards, tview.Escape(model.CleanStation(d.Direction)))
+		switch {
+		case d.Cancelled:
+			line = fmt.Sprintf("[red::s]%s %s %s[-:-:-] [red::b]✗[-:-:-]",
+				model.FormatTime(d.Planned), tview.Escape(d.Line), tview.Escape(model.CleanStation(d.Direction)))
+		case d.Delay >= 60:
+			line += fmt.Sprintf(" [yellow]+%d[-]", d.Delay/60)
+		}
+		if d.Platform != "" && !d.Cancelled {
+			line += fmt.Sprintf(" [dim]Pl. %s[-]", tview.Escape(d.Platform))
+		}
+		sb.WriteString(line + "\n")
+	}
+	if sb.Len() == 0 {
+		return "[dim]Nothing in the next 30 minutes[-]"
+	}
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// staleAfter is how many auto-refreshes may be missed before the screen
+// says the journeys are old
+const staleAfter = 4
+
+// refreshEvery is how often the journeys refresh on their own, slower
+// during quiet hours
+func (a *App) refreshEvery() time.Duration {
+	quiet := a.config.Notify.QuietHours
+	if quiet.Active(time.Now()) {
+		return max(quiet.Interval(), refreshInterval)
+	}
+	return refreshInterval
+}
+
+// staleFor is how old the listed journeys are once that's too old to
+// trust their countdowns, zero while they're fresh
+func (a *App) staleFor() time.Duration {
+	if a.lastUpdate.IsZero() || len(a.journeys) == 0 {
+		return 0
+	}
+	if age := time.Since(a.lastUpdate); age > staleAfter*a.refreshEvery() {
+		return age
+	}
+	return 0
+}
+
+// staleBanner warns above the journeys that they're old, and why if known
+func (a *App) staleBanner() string {
+	age := a.staleFor()
+	if age == 0 {
+		return ""
+	}
+	why := "refreshes aren't coming through"
+	if a.refreshErr != nil {
+		why = "no connection to the API"
+	}
+	return fmt.Sprintf(" [white:magenta:b] ⚠ Data is %d minutes old [-:-:-] [magenta]%s, countdowns may be wrong[-]\n",
+		int(age.Minutes()), why)
+}
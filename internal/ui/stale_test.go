@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+)
+
+func TestStaleBanner(t *testing.T) {
+	allDay := config.QuietHours{Ranges: []string{"00:00-12:00", "12:00-00:00"}, RefreshInterval: 15}
+	journeys := []model.Journey{{}}
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		journeys []model.Journey
+		err      error
+		quiet    config.QuietHours
+		want     string
+	}{
+		{name: "fresh", age: 30 * time.Second, journeys: journeys},
+		{name: "three refreshes missed", age: 110 * time.Second, journeys: journeys},
+		{name: "old", age: 5*time.Minute + 10*time.Second, journeys: journeys,
+			want: " [white:magenta:b] ⚠ Data is 5 minutes old [-:-:-] [magenta]refreshes aren't coming through, countdowns may be wrong[-]\n"},
+		{name: "old and offline", age: 5 * time.Minute, journeys: journeys, err: errors.New("dial tcp: no route to host"),
+			want: " [white:magenta:b] ⚠ Data is 5 minutes old [-:-:-] [magenta]no connection to the API, countdowns may be wrong[-]\n"},
+		{name: "nothing listed", age: 5 * time.Minute},
+		{name: "never refreshed", journeys: journeys},
+		{name: "quiet hours refresh slower", age: 30 * time.Minute, journeys: journeys, quiet: allDay},
+		{name: "even in quiet hours", age: 61 * time.Minute, journeys: journeys, quiet: allDay,
+			want: " [white:magenta:b] ⚠ Data is 61 minutes old [-:-:-] [magenta]refreshes aren't coming through, countdowns may be wrong[-]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{journeys: tt.journeys, refreshErr: tt.err}
+			a.config.Notify.QuietHours = tt.quiet
+			if tt.age > 0 {
+				a.lastUpdate = time.Now().Add(-tt.age)
+			}
+			if got := a.staleBanner(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
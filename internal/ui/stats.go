@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/history"
+	"go-commute/internal/model"
+)
+
+// showStats renders per-line delay statistics from the persisted history
+func (a *App) showStats() {
+	now := time.Now().In(model.DisplayZone)
+	samples := a.history.SamplesBetween(now.Add(-history.Retention), now)
+	threshold := a.config.Notify.Threshold()
+	stats := history.ComputeLineStats(samples, threshold)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[::b]%-8s %7s %7s %7s %7s %7s %7s  %-9s[-:-:-]\n",
+		"Line", "Samples", "Mean", "Median", "P90", "Worst", "Late", "7 days"))
+	sb.WriteString("[dim]" + strings.Repeat("─", 70) + "[-]\n")
+
+	if len(stats) == 0 {
+		sb.WriteString("\n [dim]No delay history recorded yet. It builds up as you use berrrr or run 'berrrr daemon'.[-]\n")
+	}
+	for _, st := range stats {
+		means := history.DailyMeans(samples, st.Line, 7, now)
+		trend := make([]int, len(means))
+		for i, m := range means {
+			trend[i] = max(m, 0)
+		}
+		sb.WriteString(fmt.Sprintf("[%s]%-8s[-] %7d %6.1fm %6.1fm %6.1fm %6dm %6d%%  [dim]%s[-] %s\n",
+			a.productColor(st.Product), st.Line, st.Count, st.Mean, st.Median, st.P90, st.Worst,
+			st.Late*100/st.Count, sparkline(trend, 7), trendArrow(history.DelayTrend(means))))
+	}
+	sb.WriteString(fmt.Sprintf("\n[dim]Late = delayed by %d+ min. Press ESC or 'b' to go back[-]", threshold))
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Delay Statistics ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("stats")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("stats", view, true, false)
+	a.pages.SwitchToPage("stats")
+	a.app.SetFocus(view)
+}
+
+// trendArrow shows which way a line's delays head, red when they get worse
+func trendArrow(t history.Trend) string {
+	switch t {
+	case history.TrendUp:
+		return "[red]↑[-]"
+	case history.TrendDown:
+		return "[green]↓[-]"
+	case history.TrendFlat:
+		return "[dim]→[-]"
+	}
+	return "[dim]·[-]"
+}
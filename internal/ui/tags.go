@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+	"go-commute/internal/share"
+)
+
+// toggleTag marks the selected journey for the batch actions, or unmarks
+// it, and moves on to the next one
+func (a *App) toggleTag() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	id := model.JourneyID(a.journeys[a.selectedIdx])
+	if a.tagged[id] {
+		delete(a.tagged, id)
+	} else {
+		a.tagged[id] = true
+	}
+	a.selectNext()
+	if n := len(a.taggedJourneys()); n > 0 {
+		a.statusMsg = fmt.Sprintf("✓ %d tagged: i iCal, e CSV, y Copy, C Compare, Esc to clear", n)
+	} else {
+		a.statusMsg = "Nothing tagged"
+	}
+	a.statusMsgFrame = 50
+}
+
+// clearTags unmarks every journey
+func (a *App) clearTags() {
+	if len(a.tagged) == 0 {
+		return
+	}
+	a.tagged = map[string]bool{}
+	a.statusMsg = "Tags cleared"
+	a.statusMsgFrame = 30
+}
+
+// taggedJourneys are the tagged journeys still listed, in list order
+func (a *App) taggedJourneys() []model.Journey {
+	var tagged []model.Journey
+	for _, j := range a.journeys {
+		if a.tagged[model.JourneyID(j)] {
+			tagged = append(tagged, j)
+		}
+	}
+	return tagged
+}
+
+// batch is what the batch actions work on: the tagged journeys, or the
+// selected one when nothing is tagged
+func (a *App) batch() []model.Journey {
+	if tagged := a.taggedJourneys(); len(tagged) > 0 {
+		return tagged
+	}
+	if a.selectedIdx < len(a.journeys) {
+		return []model.Journey{a.journeys[a.selectedIdx]}
+	}
+	return nil
+}
+
+// exportCSV writes the batch to a .csv file in the working directory
+func (a *App) exportCSV() {
+	journeys := a.batch()
+	if len(journeys) == 0 {
+		return
+	}
+	name := batchName(journeys, "csv")
+	if err := os.WriteFile(name, []byte(share.CSV(journeys, a.config.LastOrigin, a.config.LastDest)), 0644); err != nil {
+		a.statusMsg = "Export failed: " + err.Error()
+	} else {
+		a.statusMsg = "Saved " + name
+	}
+	a.statusMsgFrame = 30
+}
+
+// showCompare puts the tagged journeys side by side, a column each
+func (a *App) showCompare() {
+	journeys := a.taggedJourneys()
+	if len(journeys) < 2 {
+		a.statusMsg = "Tag two or more journeys with Space to compare them"
+		a.statusMsgFrame = 30
+		return
+	}
+
+	table := tview.NewTable().SetBorders(false)
+	table.SetBorder(true).SetTitle(" Compare (ESC or 'b' to go back) ")
+	rows := []struct {
+		label string
+		cell  func(j model.Journey) string
+	}{
+		{"Leave", func(j model.Journey) string { return model.FormatTime(j.LeaveAt) }},
+		{"Arrive", func(j model.Journey) string { return model.FormatTime(j.ArriveAt) }},
+		{"Duration", func(j model.Journey) string { return fmt.Sprintf("%d min", int(j.Duration.Minutes())) }},
+		{"Changes", func(j model.Journey) string { return fmt.Sprintf("%d", max(len(j.Legs)-1, 0)) }},
+		{"Waiting", func(j model.Journey) string { return fmt.Sprintf("%d min", int(j.TotalWait.Minutes())) }},
+		{"Walking", func(j model.Journey) string { return fmt.Sprintf("%d min", int(j.Walking.Minutes())) }},
+		{"Connections", func(j model.Journey) string {
+			if len(j.Legs) < 2 {
+				return "-"
+			}
+			return reliabilityBadge(j.Reliability)
+		}},
+		{"Delay", func(j model.Journey) string {
+			if len(j.Legs) == 0 || j.Legs[len(j.Legs)-1].ArrDelay < 60 {
+				return "[green]on time[-]"
+			}
+			return fmt.Sprintf("[yellow]+%d min[-]", j.Legs[len(j.Legs)-1].ArrDelay/60)
+		}},
+		{"Price", func(j model.Journey) string {
+			if j.Price == nil {
+				return "-"
+			}
+			return j.Price.String()
+		}},
+		{"Lines", func(j model.Journey) string {
+			var lines []string
+			for _, leg := range j.Legs {
+				lines = append(lines, fmt.Sprintf("[%s]%s[-]", a.productColor(leg.Product), tview.Escape(leg.Line)))
+			}
+			return strings.Join(lines, " → ")
+		}},
+	}
+	for r, row := range rows {
+		table.SetCell(r, 0, tview.NewTableCell("[::b]"+row.label+"[-:-:-]").SetExpansion(0))
+		for c, j := range journeys {
+			text := row.cell(j)
+			if j.Cancelled() && r == 0 {
+				text = "[red::s]" + text + "[-:-:-] [red]✗[-]"
+			}
+			table.SetCell(r, c+1, tview.NewTableCell("  "+text).SetExpansion(1))
+		}
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("compare")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("compare", table, true, false)
+	a.pages.SwitchToPage("compare")
+	a.app.SetFocus(table)
+}
+
+// batchName is a file name for the batch, by when the first one leaves
+func batchName(journeys []model.Journey, ext string) string {
+	stamp := journeys[0].LeaveAt.In(model.DisplayZone).Format("20060102-1504")
+	if len(journeys) > 1 {
+		return fmt.Sprintf("berrrr-%s-%d.%s", stamp, len(journeys), ext)
+	}
+	return fmt.Sprintf("berrrr-%s.%s", stamp, ext)
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"fmt"
+
+	"go-commute/internal/vbb"
+)
+
+// setTransfers limits how often the journeys may change for this session
+// and fetches them again. Out of range values are clamped.
+func (a *App) setTransfers(n int) {
+	n = max(0, min(n, vbb.DefaultTransfers))
+	if n == a.transfers {
+		return
+	}
+	a.transfers = n
+	a.statusMsg = "Max transfers: " + transfersLabel(n)
+	a.statusMsgFrame = 30
+	a.refresh()
+}
+
+func transfersLabel(n int) string {
+	if n == 0 {
+		return "direct only"
+	}
+	return fmt.Sprintf("%d", n)
+}
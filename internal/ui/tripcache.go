@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// tripCacheSize is how many trips are kept, least recently used out first
+const tripCacheSize = 32
+
+// tripCacheMaxAge is how long a cached trip's realtime data is trusted
+const tripCacheMaxAge = time.Minute
+
+// tripCache keeps recently fetched trips so the stops view opens without
+// waiting. Background prefetches, the views and the riding checks share it.
+type tripCache struct {
+	mu      sync.Mutex
+	order   *list.List // of *cachedTrip, most recently used first
+	byID    map[string]*list.Element
+	pending map[string]bool
+	failed  map[string]time.Time // when a trip's last fetch failed
+}
+
+type cachedTrip struct {
+	id      string
+	trip    model.Trip
+	fetched time.Time
+}
+
+func newTripCache() *tripCache {
+	return &tripCache{
+		order:   list.New(),
+		byID:    make(map[string]*list.Element),
+		pending: make(map[string]bool),
+		failed:  make(map[string]time.Time),
+	}
+}
+
+// peek is the cached trip however old, and when it was fetched
+func (c *tripCache) peek(id string) (model.Trip, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.byID[id]
+	if !ok {
+		return model.Trip{}, time.Time{}, false
+	}
+	c.order.MoveToFront(e)
+	t := e.Value.(*cachedTrip)
+	return t.trip, t.fetched, true
+}
+
+// get is the cached trip if it's fresh enough
+func (c *tripCache) get(id string) (model.Trip, bool) {
+	trip, fetched, ok := c.peek(id)
+	if !ok || time.Since(fetched) > tripCacheMaxAge {
+		return model.Trip{}, false
+	}
+	return trip, true
+}
+
+func (c *tripCache) put(id string, trip model.Trip) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.failed, id)
+	if e, ok := c.byID[id]; ok {
+		e.Value = &cachedTrip{id: id, trip: trip, fetched: time.Now()}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.byID[id] = c.order.PushFront(&cachedTrip{id: id, trip: trip, fetched: time.Now()})
+	for c.order.Len() > tripCacheSize {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.byID, last.Value.(*cachedTrip).id)
+	}
+}
+
+// claim marks a trip as being fetched, false if it was fetched within
+// maxAge, its last fetch failed within maxAge or one is already on its way
+func (c *tripCache) claim(id string, maxAge time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.byID[id]; ok && time.Since(e.Value.(*cachedTrip).fetched) <= maxAge {
+		return false
+	}
+	if failed, ok := c.failed[id]; ok && time.Since(failed) <= maxAge {
+		return false
+	}
+	if c.pending[id] {
+		return false
+	}
+	c.pending[id] = true
+	return true
+}
+
+// release ends a claim, remembering a failure so it isn't retried at once
+func (c *tripCache) release(id string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, id)
+	if err == nil {
+		return
+	}
+	for other, at := range c.failed {
+		if time.Since(at) > tripCacheMaxAge {
+			delete(c.failed, other)
+		}
+	}
+	c.failed[id] = time.Now()
+}
+
+// trip is the client's trip lookup through the cache
+func (a *App) trip(ctx context.Context, id string) (model.Trip, error) {
+	if trip, ok := a.trips.get(id); ok {
+		return trip, nil
+	}
+	trip, err := a.client.Trip(ctx, id)
+	if err == nil {
+		a.trips.put(id, trip)
+	}
+	return trip, err
+}
+
+// cachedTrip is the cached trip without waiting for it, and fetches it in
+// the background once it's older than maxAge. ok is false until the trip
+// first arrives.
+func (a *App) cachedTrip(id string, maxAge time.Duration) (model.Trip, bool) {
+	if a.trips.claim(id, maxAge) {
+		a.goSafe(func() {
+			trip, err := a.client.Trip(a.ctx, id)
+			if err != nil {
+				slog.Warn("trip lookup failed", "trip", id, "err", err)
+			} else {
+				a.trips.put(id, trip)
+			}
+			a.trips.release(id, err)
+		})
+	}
+	trip, _, ok := a.trips.peek(id)
+	return trip, ok
+}
+
+// prefetchSelected fetches the selected journey's trips in the background
+// once the selection settles on it, so its stops show right away. That
+// happens once per selection; the views fetch again when the trips age
+// out of the cache.
+func (a *App) prefetchSelected() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+	id := model.JourneyID(j)
+	if id != a.selectedFor {
+		a.selectedFor = id
+		return // wait a tick, the selection may still be moving
+	}
+	if id == a.prefetchedFor {
+		return
+	}
+	a.prefetchedFor = id
+	for _, leg := range j.Legs {
+		if leg.TripID != "" {
+			a.cachedTrip(leg.TripID, tripCacheMaxAge)
+		}
+	}
+}
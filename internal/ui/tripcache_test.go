@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestTripCacheEviction(t *testing.T) {
+	c := newTripCache()
+	for i := 0; i < tripCacheSize; i++ {
+		c.put(fmt.Sprint(i), model.Trip{Line: fmt.Sprint(i)})
+	}
+	if _, ok := c.get("0"); !ok {
+		t.Fatal("the first trip is already gone")
+	}
+	c.put("new", model.Trip{Line: "S5"})
+
+	if _, ok := c.get("1"); ok {
+		t.Error("the least recently used trip is still there")
+	}
+	for _, id := range []string{"0", "2", "new"} {
+		if _, ok := c.get(id); !ok {
+			t.Errorf("trip %s went out", id)
+		}
+	}
+	if n := c.order.Len(); n != tripCacheSize || len(c.byID) != tripCacheSize {
+		t.Errorf("keeps %d trips, %d by id, want %d", n, len(c.byID), tripCacheSize)
+	}
+}
+
+func TestTripCacheClaim(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(c *tripCache)
+		maxAge time.Duration
+		want   bool
+	}{
+		{"never fetched", func(c *tripCache) {}, time.Minute, true},
+		{"fresh", func(c *tripCache) { c.put("t", model.Trip{}) }, time.Minute, false},
+		{"older than asked for", func(c *tripCache) {
+			c.put("t", model.Trip{})
+			c.byID["t"].Value.(*cachedTrip).fetched = time.Now().Add(-2 * time.Minute)
+		}, time.Minute, true},
+		{"on its way", func(c *tripCache) { c.claim("t", time.Minute) }, time.Minute, false},
+		{"released", func(c *tripCache) {
+			c.claim("t", time.Minute)
+			c.release("t", nil)
+		}, time.Minute, true},
+		{"just failed", func(c *tripCache) {
+			c.claim("t", time.Minute)
+			c.release("t", errors.New("timeout"))
+		}, time.Minute, false},
+		{"failed a while ago", func(c *tripCache) {
+			c.claim("t", time.Minute)
+			c.release("t", errors.New("timeout"))
+			c.failed["t"] = time.Now().Add(-2 * time.Minute)
+		}, time.Minute, true},
+		{"failed, then fetched", func(c *tripCache) {
+			c.claim("t", time.Minute)
+			c.release("t", errors.New("timeout"))
+			c.put("t", model.Trip{})
+			c.byID["t"].Value.(*cachedTrip).fetched = time.Now().Add(-2 * time.Minute)
+		}, time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTripCache()
+			tt.setup(c)
+			if got := c.claim("t", tt.maxAge); got != tt.want {
+				t.Errorf("claim = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTripCacheGet(t *testing.T) {
+	c := newTripCache()
+	c.put("t", model.Trip{Line: "S5"})
+	if trip, ok := c.get("t"); !ok || trip.Line != "S5" {
+		t.Fatalf("get = %+v, %v", trip, ok)
+	}
+	c.byID["t"].Value.(*cachedTrip).fetched = time.Now().Add(-tripCacheMaxAge - time.Second)
+	if _, ok := c.get("t"); ok {
+		t.Error("get hands out a stale trip")
+	}
+	if trip, _, ok := c.peek("t"); !ok || trip.Line != "S5" {
+		t.Errorf("peek = %+v, %v, want the stale trip", trip, ok)
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/model"
+)
+
+// showTrips lists the stops of every leg of the selected journey with how
+// full the vehicle is at each, as far as the API knows. The stops after
+// getting off stay in view, dimmed, to see where it empties out.
+func (a *App) showTrips() {
+	if a.selectedIdx >= len(a.journeys) {
+		return
+	}
+	j := a.journeys[a.selectedIdx]
+
+	a.statusMsg = "Loading stops…"
+	a.statusMsgFrame = 30
+	a.goSafe(func() {
+		trips := make([]model.Trip, len(j.Legs))
+		errs := make([]error, len(j.Legs))
+		var wg sync.WaitGroup
+		for i, leg := range j.Legs {
+			if leg.TripID == "" {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				defer a.recoverPanic()
+				trips[i], errs[i] = a.trip(a.ctx, id)
+			}(i, leg.TripID)
+		}
+		wg.Wait()
+		a.app.QueueUpdateDraw(func() {
+			a.showTripView(j, trips, errs)
+		})
+	})
+}
+
+func (a *App) showTripView(j model.Journey, trips []model.Trip, errs []error) {
+	var sb strings.Builder
+	for i, leg := range j.Legs {
+		color := a.productColor(leg.Product)
+		sb.WriteString(fmt.Sprintf("[%s::b]%s %s[-:-:-]", color, a.productIcon(leg.Product), leg.Line))
+		if leg.Direction != "" {
+			sb.WriteString(fmt.Sprintf(" [%s]▸ %s[-]", color, tview.Escape(model.CleanStation(leg.Direction))))
+		}
+		sb.WriteString("\n")
+
+		switch {
+		case leg.TripID == "":
+			sb.WriteString(fmt.Sprintf("  [dim]%s → %s[-]\n\n",
+				model.CleanStation(leg.From), model.CleanStation(leg.To)))
+			continue
+		case errs[i] != nil:
+			sb.WriteString(fmt.Sprintf("  [red]%s[-]\n\n", tview.Escape(errs[i].Error())))
+			continue
+		}
+
+		known := false
+		on := false
+		for _, s := range trips[i].Stopovers {
+			board, alight := s.Station.ID == leg.FromID, s.Station.ID == leg.ToID
+			if board {
+				on = true
+			}
+			if !on && !alight {
+				continue
+			}
+			if s.Occupancy != "" {
+				known = true
+			}
+
+			when := s.Departure
+			if alight || when.IsZero() {
+				when = s.Arrival
+			}
+			mark, style := "  ", ""
+			switch {
+			case board:
+				mark, style = "▶ ", "::b"
+			case alight:
+				mark, style = "◀ ", "::b"
+			case !on:
+				style = "::d"
+			}
+			name := tview.Escape(model.CleanStation(s.Station.Name))
+			if s.Cancelled {
+				name = "[red]" + name + " (cancelled)[-]"
+			}
+			sb.WriteString(fmt.Sprintf("  %s[%s]%s  %-24s[-:-:-] %s\n",
+				mark, style, model.FormatTime(when), name, occupancyBar(s.Occupancy, 0)))
+			if alight {
+				on = false
+			}
+		}
+		if !known {
+			sb.WriteString("  [dim]No occupancy data for this trip[-]\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("[dim]▶ board  ◀ get off     Press ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Stops & Occupancy ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("trips")
+			a.pages.SwitchToPage("detail")
+			a.app.SetFocus(a.detail)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("trips", view, true, false)
+	a.pages.SwitchToPage("trips")
+	a.app.SetFocus(view)
+}
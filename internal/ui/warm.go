@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"time"
+
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// warmMaxAge is how long a warm-start fetch can stand in for a refresh
+const warmMaxAge = time.Minute
+
+// warmFetch is a route fetched at launch, ahead of anyone asking for it
+type warmFetch struct {
+	done     chan struct{} // closed once the fields are set
+	started  time.Time
+	journeys []model.Journey
+	laterRef string
+	err      error
+}
+
+// warmStart fetches the last route and the first few favorites while the
+// splash shows, so the list and a quick switch to a favorite have
+// journeys right away. Favorites are fetched with their saved view, the
+// way loading them will refresh.
+func (a *App) warmStart() {
+	routes := []model.FavoriteRoute{{Origin: a.config.LastOrigin, Dest: a.config.LastDest}}
+	for i := 0; i < a.config.WarmStart && i < len(a.config.Routes); i++ {
+		routes = append(routes, a.config.Routes[i])
+	}
+	a.warm = make(map[string]*warmFetch)
+	for _, r := range routes {
+		products := a.viewProducts(r.Origin, r.Dest)
+		key := a.routeKey(r.Origin, r.Dest, products)
+		if a.warm[key] != nil {
+			continue
+		}
+		w := &warmFetch{done: make(chan struct{}), started: time.Now()}
+		a.warm[key] = w
+		origin, destID := r.Origin, r.Dest.ID
+		transfers := a.transfers
+		opts := vbb.JourneyOptions{Products: products, Transfers: &transfers}.From(origin)
+		a.goSafe(func() {
+			defer close(w.done)
+			w.journeys, w.laterRef, w.err = fetchJourneys(a.ctx, a.client, origin.ID, destID, opts, 0)
+		})
+	}
+}
+
+// takeWarm hands a refresh the warm-start fetch for its route, once
+func (a *App) takeWarm(key string) *warmFetch {
+	w := a.warm[key]
+	delete(a.warm, key)
+	if w == nil || time.Since(w.started) > warmMaxAge {
+		return nil
+	}
+	return w
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestTakeWarm(t *testing.T) {
+	tests := []struct {
+		name    string
+		started time.Duration // ago, none for no fetch
+		key     string
+		want    bool
+	}{
+		{"fresh", 10 * time.Second, "route", true},
+		{"too old", 2 * time.Minute, "route", false},
+		{"another route", 10 * time.Second, "other", false},
+		{"none", 0, "route", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{warm: map[string]*warmFetch{}}
+			if tt.started != 0 {
+				a.warm["route"] = &warmFetch{done: make(chan struct{}), started: time.Now().Add(-tt.started)}
+			}
+			if got := a.takeWarm(tt.key); (got != nil) != tt.want {
+				t.Fatalf("takeWarm = %v, want a fetch %v", got, tt.want)
+			}
+			if tt.key == "route" && a.warm["route"] != nil {
+				t.Error("the fetch is still there to take")
+			}
+			if a.takeWarm(tt.key) != nil {
+				t.Error("handed out twice")
+			}
+		})
+	}
+}
+
+func TestRouteKey(t *testing.T) {
+	a := &App{transfers: 2, filters: map[string]bool{"bus": true}}
+	a.config.LastOrigin = model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)"}
+	a.config.LastDest = model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)"}
+
+	if got, want := a.routeKey(a.config.LastOrigin, a.config.LastDest, a.products()), a.refreshKey(); got != want {
+		t.Errorf("the last route's key %q isn't what its refresh looks for, %q", got, want)
+	}
+	back := a.routeKey(a.config.LastDest, a.config.LastOrigin, a.products())
+	if back == a.refreshKey() {
+		t.Error("the way back has the same key")
+	}
+	if a.routeKey(a.config.LastOrigin, a.config.LastDest, map[string]bool{"bus": false}) == a.refreshKey() {
+		t.Error("other products have the same key")
+	}
+}
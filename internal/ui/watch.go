@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"go-commute/internal/alert"
+	"go-commute/internal/vbb"
+)
+
+// pollWatchList refreshes the status of all watched lines in the background
+func (a *App) pollWatchList() {
+	lines, notify := a.config.WatchLines, a.config.Notify
+	warner, ok := a.client.(vbb.LineWarner)
+	if len(lines) == 0 || !ok {
+		return
+	}
+
+	a.goSafe(func() {
+		status := make(map[string][]string)
+		var alerts []alert.Alert
+		for _, line := range lines {
+			warnings, err := warner.LineWarnings(a.ctx, line)
+			if err != nil {
+				slog.Warn("line status failed", "line", line, "err", err)
+				continue
+			}
+			status[line] = warnings
+			alerts = append(alerts, a.alerts.CheckLine(line, warnings)...)
+		}
+		a.app.QueueUpdateDraw(func() {
+			for line, warnings := range status {
+				a.lineStatus[line] = warnings
+			}
+			a.dirty = true
+			if len(alerts) > 0 {
+				a.statusMsg = fmt.Sprintf("⚠ %s: new disruption", alerts[0].Line)
+				a.statusMsgFrame = 50
+			}
+		})
+		if len(alerts) > 0 {
+			alert.Dispatch(notify, alerts)
+		}
+	})
+}
+
+// showDisruptions lists warnings for watched lines and lines on the current route
+func (a *App) showDisruptions() {
+	byLine := make(map[string][]string)
+	for line, warnings := range a.lineStatus {
+		byLine[line] = append(byLine[line], warnings...)
+	}
+	for _, j := range a.journeys {
+		for _, leg := range j.Legs {
+			for _, w := range leg.ServiceStatus {
+				if !slices.Contains(byLine[leg.Line], w) {
+					byLine[leg.Line] = append(byLine[leg.Line], w)
+				}
+			}
+		}
+	}
+
+	var lines []string
+	for line := range byLine {
+		lines = append(lines, line)
+	}
+	for _, line := range a.config.WatchLines {
+		if _, ok := byLine[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+
+	var sb strings.Builder
+	if len(lines) == 0 {
+		sb.WriteString("\n [dim]No disruptions. Add lines to \"watch_lines\" in the config to monitor them.[-]\n")
+	}
+	for _, line := range lines {
+		watched := ""
+		if slices.Contains(a.config.WatchLines, line) {
+			watched = " [dim](watched)[-]"
+		}
+		warnings := byLine[line]
+		if len(warnings) == 0 {
+			sb.WriteString(fmt.Sprintf("[green::b]%s[-:-:-]%s  [green]✓ no disruptions[-]\n\n", line, watched))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[red::b]%s[-:-:-]%s\n", line, watched))
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("    [red]⚠[-] %s\n", tview.Escape(w)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("[dim]Press ESC or 'b' to go back[-]")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWordWrap(true)
+	view.SetBorder(true).SetTitle(" Disruptions ")
+	view.SetText(sb.String())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'b' || event.Rune() == 'q' {
+			a.pages.RemovePage("disruptions")
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("disruptions", view, true, false)
+	a.pages.SwitchToPage("disruptions")
+	a.app.SetFocus(view)
+}
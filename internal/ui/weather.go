@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"go-commute/internal/weather"
+)
+
+// weatherMaxAge is how long conditions are shown before they're fetched again
+const weatherMaxAge = 10 * time.Minute
+
+// updateWeather fetches the weather at the origin when it is missing or
+// stale. Stations carry no coordinates, so they're taken from where the
+// first journey starts.
+func (a *App) updateWeather() {
+	if a.config.NoWeather || len(a.journeys) == 0 {
+		return
+	}
+	originID := a.config.LastOrigin.ID
+	if a.weather != nil && a.weatherFor == originID && time.Since(a.weather.Fetched) < weatherMaxAge {
+		return
+	}
+	at := a.journeys[0].Legs[0].FromCoords
+	if at.Latitude == 0 && at.Longitude == 0 {
+		return
+	}
+
+	a.goSafe(func() {
+		c, err := weather.Fetch(a.ctx, at)
+		if err != nil {
+			slog.Debug("weather failed", "err", err)
+			return
+		}
+		a.app.QueueUpdateDraw(func() {
+			a.weather = &c
+			a.weatherFor = originID
+			a.dirty = true
+		})
+	})
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// defaultPlanHour is when a day picked without a time starts, the usual
+// morning commute
+const defaultPlanHour = 8
+
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+	"sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// promptDeparture asks when to leave, so Monday morning can be planned on
+// Sunday evening. An empty answer or "now" goes back to leaving now.
+func (a *App) promptDeparture() {
+	initial := ""
+	if !a.departAt.IsZero() {
+		initial = a.departAt.In(model.DisplayZone).Format("2006-01-02 15:04")
+	}
+	a.prompt("Leave at", "When (08:00, tomorrow 8:00, next mon): ", initial, func(text string) {
+		at, err := parseWhen(text, time.Now())
+		if err != nil {
+			a.statusMsg = "⚠ " + err.Error()
+			a.statusMsgFrame = 50
+			return
+		}
+		a.setDeparture(at)
+	})
+}
+
+// setDeparture plans leaving at the given time, or now for the zero time
+func (a *App) setDeparture(at time.Time) {
+	a.departAt = at
+	if at.IsZero() {
+		a.statusMsg = "Leaving now"
+	} else {
+		a.statusMsg = "🕗 Leaving " + formatWhen(at)
+	}
+	a.statusMsgFrame = 30
+	a.selectedIdx = 0
+	a.refresh()
+}
+
+// parseWhen reads a departure like "08:00", "tomorrow 8:00", "next monday",
+// "sat 10:30", "2026-12-24 14:00" or "24.12. 14:00". A bare time that has
+// passed today means tomorrow; a day without a time means defaultPlanHour.
+// "now" and "" are the zero time.
+func parseWhen(text string, now time.Time) (time.Time, error) {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) == 0 || (len(fields) == 1 && fields[0] == "now") {
+		return time.Time{}, nil
+	}
+	invalid := fmt.Errorf("%q isn't a time like 08:00, tomorrow 8:00 or next mon", text)
+
+	local := now.In(model.DisplayZone)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, model.DisplayZone)
+	var day time.Time
+	hour, minute := defaultPlanHour, 0
+	timeGiven := false
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if h, m, ok := parseClock(f); ok && !timeGiven {
+			hour, minute, timeGiven = h, m, true
+			continue
+		}
+		if !day.IsZero() {
+			return time.Time{}, invalid
+		}
+		switch {
+		case f == "today":
+			day = today
+		case f == "tomorrow":
+			day = today.AddDate(0, 0, 1)
+		case f == "next" && i+1 < len(fields):
+			wd, ok := weekdays[fields[i+1]]
+			if !ok {
+				return time.Time{}, invalid
+			}
+			day = nextWeekday(today, wd)
+			i++
+		default:
+			if wd, ok := weekdays[f]; ok {
+				day = nextWeekday(today, wd)
+			} else if d, ok := parseDate(f, today); ok {
+				day = d
+			} else {
+				return time.Time{}, invalid
+			}
+		}
+	}
+
+	if day.IsZero() {
+		at := time.Date(today.Year(), today.Month(), today.Day(), hour, minute, 0, 0, model.DisplayZone)
+		if at.Before(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, model.DisplayZone)
+	if at.Before(now) {
+		return time.Time{}, fmt.Errorf("%s is in the past", formatWhen(at))
+	}
+	return at, nil
+}
+
+// parseClock reads "8:00", "08:00", "8h", "8pm" or "8:30pm"
+func parseClock(s string) (hour, minute int, ok bool) {
+	for _, layout := range []string{"15:04", "15h", "3pm", "3:04pm"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour(), t.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
+
+// parseDate reads "2026-12-24" or "24.12.", the latter this year or, once
+// it has passed, next year
+func parseDate(s string, today time.Time) (time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02", s, model.DisplayZone); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation("2.1.", s, model.DisplayZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	d := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, model.DisplayZone)
+	if d.Before(today) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, true
+}
+
+// nextWeekday is the first such weekday after today
+func nextWeekday(today time.Time, wd time.Weekday) time.Time {
+	days := (int(wd) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// formatWhen names a departure day the way people say it
+func formatWhen(t time.Time) string {
+	local := t.In(model.DisplayZone)
+	now := time.Now().In(model.DisplayZone)
+	switch {
+	case local.YearDay() == now.YearDay() && local.Year() == now.Year():
+		return "today " + model.FormatTime(t)
+	case local.YearDay() == now.AddDate(0, 0, 1).YearDay() && local.Year() == now.AddDate(0, 0, 1).Year():
+		return "tomorrow " + model.FormatTime(t)
+	}
+	return local.Format("Mon 2 Jan ") + model.FormatTime(t)
+}
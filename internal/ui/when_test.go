@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestParseWhen(t *testing.T) {
+	// A Friday evening
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, model.DisplayZone)
+	at := func(month time.Month, day, hour, minute int) string {
+		return time.Date(2026, month, day, hour, minute, 0, 0, model.DisplayZone).Format(time.DateTime)
+	}
+
+	tests := []struct {
+		text string
+		want string // empty for leaving now
+		ok   bool
+	}{
+		{"", "", true},
+		{"now", "", true},
+		{"19:30", at(10, 16, 19, 30), true},
+		{"08:00", at(10, 17, 8, 0), true},
+		{"8pm", at(10, 16, 20, 0), true},
+		{"tomorrow", at(10, 17, 8, 0), true},
+		{"Tomorrow 7:15", at(10, 17, 7, 15), true},
+		{"9:30 tomorrow", at(10, 17, 9, 30), true},
+		{"next mon", at(10, 19, 8, 0), true},
+		{"fri 10h", at(10, 23, 10, 0), true},
+		{"2026-12-24 14:00", at(12, 24, 14, 0), true},
+		{"24.12. 14:00", at(12, 24, 14, 0), true},
+		{"today 9:00", "", false},
+		{"next", "", false},
+		{"next week", "", false},
+		{"tomorrow mon", "", false},
+		{"soon", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parseWhen(tt.text, now)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if !tt.ok {
+				return
+			}
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Errorf("got %s, want now", got)
+				}
+				return
+			}
+			if s := got.In(model.DisplayZone).Format(time.DateTime); s != tt.want {
+				t.Errorf("got %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
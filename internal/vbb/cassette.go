@@ -0,0 +1,105 @@
+package vbb
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// A cassette is a directory holding one recorded response per request URL,
+// so a session can be replayed offline, e.g. to reproduce a rendering bug
+// or take screenshots.
+type cassetteEntry struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Body   string `json:"body"`
+}
+
+func cassetteFile(dir, url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+type recorder struct {
+	dir  string
+	base http.RoundTripper
+}
+
+// Record returns a transport that passes requests on to base and saves
+// every response into the cassette dir
+func Record(dir string, base http.RoundTripper) (http.RoundTripper, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &recorder{dir: dir, base: base}, nil
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	url := req.URL.String()
+	entry := cassetteEntry{URL: url, Status: resp.StatusCode, Body: string(body)}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(cassetteFile(r.dir, url), data, 0644); err != nil {
+		return nil, fmt.Errorf("recording %s: %w", url, err)
+	}
+	return resp, nil
+}
+
+type replayer struct {
+	dir string
+}
+
+// Replay returns a transport that answers requests from the cassette dir
+// and never touches the network
+func Replay(dir string) (http.RoundTripper, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &replayer{dir: dir}, nil
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	data, err := os.ReadFile(cassetteFile(r.dir, url))
+	if err != nil {
+		return nil, fmt.Errorf("no recorded response for %s", url)
+	}
+	var entry cassetteEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("cassette entry for %s: %w", url, err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package vbb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/missing" {
+			http.Error(w, `{"msg":"not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"path":%q,"query":%q}`, r.URL.Path, r.URL.RawQuery)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	get := func(rt http.RoundTripper, url string) (int, string, error) {
+		resp, err := (&http.Client{Transport: rt}).Get(url)
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body), err
+	}
+	urls := []string{
+		srv.URL + "/journeys?from=900120004&to=900023201",
+		srv.URL + "/journeys?from=900120004&to=900100003",
+		srv.URL + "/missing",
+	}
+
+	rec, err := Record(dir, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type response struct {
+		status int
+		body   string
+	}
+	recorded := map[string]response{}
+	for _, url := range urls {
+		status, body, err := get(rec, url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded[url] = response{status, body}
+	}
+
+	play, err := Replay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := calls
+	for _, url := range urls {
+		status, body, err := get(play, url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := (response{status, body}); got != recorded[url] {
+			t.Errorf("%s replayed as %+v, recorded %+v", url, got, recorded[url])
+		}
+	}
+	if calls != before {
+		t.Errorf("replaying made %d requests", calls-before)
+	}
+	if _, _, err := get(play, srv.URL+"/journeys?from=900120004&to=900003201"); err == nil {
+		t.Error("replayed a request that wasn't recorded")
+	}
+	if _, err := Replay(dir + "/nothing"); err == nil {
+		t.Error("replaying from a missing directory")
+	}
+}
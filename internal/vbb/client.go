@@ -0,0 +1,158 @@
+package vbb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// TransitClient is everything the planner and the TUI need from a transit
+// API. HTTPClient talks to hafas-rest; Fake serves canned fixtures.
+type TransitClient interface {
+	SearchStations(ctx context.Context, query string) ([]model.Station, error)
+	Journeys(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, error)
+	Departures(ctx context.Context, stationID string) ([]model.Departure, error)
+	Trip(ctx context.Context, tripID string) (model.Trip, error)
+	Nearby(ctx context.Context, at model.Coordinates, radius int) ([]model.NearbyStop, error)
+}
+
+// JourneyOptions narrows a journey search. The zero value plans from now
+// with every product.
+type JourneyOptions struct {
+	Products  map[string]bool // products set to false are left out
+	Departure time.Time       // leave at or after this time
+	Arrival   time.Time       // arrive by this time; wins over Departure
+	Transfers *int            // at most this many changes, DefaultTransfers when nil
+	LaterThan string          // continue after an earlier page, from its reference; wins over both times
+
+	FromAddress *model.Station // start at this address instead of the origin ID
+}
+
+// DefaultTransfers is how many changes a journey may have unless asked otherwise
+const DefaultTransfers = 3
+
+// From starts the journeys at origin when it is an address
+func (o JourneyOptions) From(origin model.Station) JourneyOptions {
+	if origin.IsAddress() {
+		o.FromAddress = &origin
+	}
+	return o
+}
+
+// LineWarner is implemented by clients that can report disruptions on a
+// whole line, not just on planned journeys
+type LineWarner interface {
+	LineWarnings(ctx context.Context, line string) ([]string, error)
+}
+
+// StopLister is implemented by clients that can list every stop of the
+// network at once, for searching locally
+type StopLister interface {
+	AllStations(ctx context.Context) ([]model.Station, error)
+}
+
+// Geocoder is implemented by clients that can place addresses and points
+// of interest, not just stops
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (model.Coordinates, error)
+}
+
+// ArrivalLister is implemented by clients that know what arrives at a
+// stop, for meeting someone at the other end
+type ArrivalLister interface {
+	Arrivals(ctx context.Context, stationID string) ([]model.Departure, error)
+}
+
+// PathFinder is implemented by clients that know the shape of a trip's
+// route on the map
+type PathFinder interface {
+	TripPath(ctx context.Context, tripID string) ([]model.PathPoint, error)
+}
+
+// Pager is implemented by clients that can continue a journey search
+// where the last page ended
+type Pager interface {
+	JourneysPage(ctx context.Context, originID, destID string, opts JourneyOptions) (journeys []model.Journey, laterRef string, err error)
+}
+
+// Refresher is implemented by clients that can fetch one journey again
+// by its refresh token, for its latest delays
+type Refresher interface {
+	RefreshJourney(ctx context.Context, token string) (model.Journey, error)
+}
+
+// StationLooker is implemented by clients that can look a stop up by its
+// ID, for its proper name
+type StationLooker interface {
+	Station(ctx context.Context, id string) (model.Station, error)
+}
+
+// HTTPClient implements TransitClient against a hafas-rest instance
+type HTTPClient struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+var (
+	_ TransitClient = (*HTTPClient)(nil)
+	_ TransitClient = (*Fake)(nil)
+	_ LineWarner    = (*HTTPClient)(nil)
+	_ LineWarner    = (*Fake)(nil)
+	_ StopLister    = (*HTTPClient)(nil)
+	_ StopLister    = (*Fake)(nil)
+	_ Geocoder      = (*HTTPClient)(nil)
+	_ Geocoder      = (*Fake)(nil)
+	_ ArrivalLister = (*HTTPClient)(nil)
+	_ ArrivalLister = (*Fake)(nil)
+	_ Refresher     = (*HTTPClient)(nil)
+	_ StationLooker = (*HTTPClient)(nil)
+	_ PathFinder    = (*HTTPClient)(nil)
+	_ PathFinder    = (*Fake)(nil)
+	_ Pager         = (*HTTPClient)(nil)
+	_ Pager         = (*Fake)(nil)
+)
+
+// Default is the client the subcommands and the TUI use
+var Default = NewHTTPClient()
+
+// NewHTTPClient returns a client for the VBB API
+func NewHTTPClient() *HTTPClient {
+	return &HTTPClient{
+		BaseURL: BaseURL,
+		HTTP:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// getJSON requests path with params and decodes the JSON response into v
+func (c *HTTPClient) getJSON(ctx context.Context, path string, params url.Values, v any) error {
+	u := c.BaseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		slog.Warn("request failed", "url", u, "err", err)
+		return err
+	}
+	defer resp.Body.Close()
+	slog.Debug("request", "url", u, "status", resp.StatusCode, "took", time.Since(start))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		slog.Warn("decoding response failed", "url", u, "err", err)
+		return err
+	}
+	return nil
+}
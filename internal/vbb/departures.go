@@ -0,0 +1,99 @@
+package vbb
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sort"
+
+	"go-commute/internal/model"
+)
+
+type Departure struct {
+	TripID          string    `json:"tripId"`
+	Stop            *Location `json:"stop"`
+	When            string    `json:"when"`
+	PlannedWhen     string    `json:"plannedWhen"`
+	Delay           *int      `json:"delay"`
+	Platform        string    `json:"platform"`
+	PlannedPlatform string    `json:"plannedPlatform"`
+	Direction       string    `json:"direction"`
+	Provenance      string    `json:"provenance"` // where an arrival comes from
+	Line            *Line     `json:"line"`
+	Cancelled       bool      `json:"cancelled"`
+}
+
+type DeparturesResponse struct {
+	Departures []Departure `json:"departures"`
+}
+
+type ArrivalsResponse struct {
+	Arrivals []Departure `json:"arrivals"`
+}
+
+// Departures fetches the next departures from a stop, soonest first
+func (c *HTTPClient) Departures(ctx context.Context, stationID string) ([]model.Departure, error) {
+	params := url.Values{}
+	params.Set("duration", "30")
+	params.Set("remarks", "false")
+
+	var apiResp DeparturesResponse
+	if err := c.getJSON(ctx, "/stops/"+url.PathEscape(stationID)+"/departures", params, &apiResp); err != nil {
+		return nil, err
+	}
+
+	return parseDepartures(apiResp.Departures), nil
+}
+
+// Arrivals fetches the next arrivals at a stop, soonest first. Direction
+// holds where they come from.
+func (c *HTTPClient) Arrivals(ctx context.Context, stationID string) ([]model.Departure, error) {
+	params := url.Values{}
+	params.Set("duration", "30")
+	params.Set("remarks", "false")
+
+	var apiResp ArrivalsResponse
+	if err := c.getJSON(ctx, "/stops/"+url.PathEscape(stationID)+"/arrivals", params, &apiResp); err != nil {
+		return nil, err
+	}
+	for i := range apiResp.Arrivals {
+		apiResp.Arrivals[i].Direction = apiResp.Arrivals[i].Provenance
+	}
+	return parseDepartures(apiResp.Arrivals), nil
+}
+
+func parseDepartures(apiDeps []Departure) []model.Departure {
+	var departures []model.Departure
+	for _, ad := range apiDeps {
+		if ad.Line == nil {
+			continue
+		}
+		planned, err := parseTime(ad.PlannedWhen)
+		if err != nil {
+			slog.Debug("skipping departure", "trip", ad.TripID, "err", err)
+			continue
+		}
+		// Cancelled departures have no realtime "when"
+		when, err := parseTime(ad.When)
+		if err != nil {
+			when = planned
+		}
+		d := model.Departure{
+			TripID:    ad.TripID,
+			Line:      ad.Line.Name,
+			Product:   ad.Line.Product,
+			Direction: ad.Direction,
+			When:      when,
+			Planned:   planned,
+			Delay:     derefInt(ad.Delay),
+			Platform:  firstNonEmpty(ad.Platform, ad.PlannedPlatform),
+			Cancelled: ad.Cancelled,
+		}
+		departures = append(departures, d)
+	}
+
+	sort.Slice(departures, func(i, j int) bool {
+		return departures[i].When.Before(departures[j].When)
+	})
+	return departures
+}
@@ -0,0 +1,56 @@
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestArrivals(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stops/900023201/arrivals" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"arrivals":[
+			{"tripId":"2","when":"2026-10-16T08:21:00+02:00","plannedWhen":"2026-10-16T08:19:00+02:00","delay":120,
+			 "platform":"2","provenance":"S Ostkreuz (Berlin)","line":{"name":"S5","product":"suburban"}},
+			{"tripId":"1","when":null,"plannedWhen":"2026-10-16T08:12:00+02:00","delay":null,
+			 "plannedPlatform":"1","provenance":"S Ostkreuz (Berlin)","line":{"name":"S5","product":"suburban"},"cancelled":true},
+			{"tripId":"3","when":"2026-10-16T08:14:00+02:00","plannedWhen":"2026-10-16T08:14:00+02:00","delay":0,
+			 "provenance":"U Pankow (Berlin)","line":null},
+			{"tripId":"4","when":"2026-10-16T08:15:00+02:00","plannedWhen":"2026-10-16T08:15:00+02:00","delay":0,
+			 "provenance":"U Pankow (Berlin)","line":{"name":"U2","product":"subway"}}
+		]}`)
+	}))
+	defer srv.Close()
+	c := &HTTPClient{BaseURL: srv.URL, HTTP: srv.Client()}
+
+	arrivals, err := c.Arrivals(context.Background(), "900023201")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		trip, from, platform string
+		at                   string
+		delay                int
+		cancelled            bool
+	}{
+		{"1", "S Ostkreuz (Berlin)", "1", "08:12", 0, true},
+		{"4", "U Pankow (Berlin)", "", "08:15", 0, false},
+		{"2", "S Ostkreuz (Berlin)", "2", "08:21", 120, false},
+	}
+	if len(arrivals) != len(tests) {
+		t.Fatalf("got %d arrivals, want %d", len(arrivals), len(tests))
+	}
+	for i, tt := range tests {
+		a := arrivals[i]
+		if a.TripID != tt.trip || a.Direction != tt.from || a.Platform != tt.platform ||
+			a.When.In(time.FixedZone("", 2*60*60)).Format("15:04") != tt.at || a.Delay != tt.delay || a.Cancelled != tt.cancelled {
+			t.Errorf("arrival %d = %+v, want trip %s from %s at %s", i, a, tt.trip, tt.from, tt.at)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// Fake is an in-memory TransitClient serving canned fixtures: a small
+// network of stops and trips from which departures and journeys are
+// derived. It never touches the network.
+type Fake struct {
+	Stations []model.Station
+	Coords   map[string]model.Coordinates // by station ID
+	Trips    map[string]model.Trip
+	Warnings map[string][]string
+
+	// Err, when set, is returned by every call
+	Err error
+}
+
+var (
+	fakeWarschauer = model.Station{ID: "900120004", Name: "S+U Warschauer Str. (Berlin)", Type: "stop"}
+	fakeAlex       = model.Station{ID: "900100003", Name: "S+U Alexanderplatz (Berlin)", Type: "stop"}
+	fakeHbf        = model.Station{ID: "900003201", Name: "S+U Berlin Hauptbahnhof", Type: "stop"}
+	fakeZoo        = model.Station{ID: "900023201", Name: "S+U Zoologischer Garten (Berlin)", Type: "stop"}
+)
+
+// NewFake returns a Fake whose timetable covers the two hours after now:
+// an S5 every 10 minutes from Warschauer Str. to Zoo via Alexanderplatz and
+// Hauptbahnhof, where every third run is late, and a U2 every 5 minutes
+// from Alexanderplatz to Zoo.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{
+		Stations: []model.Station{fakeWarschauer, fakeAlex, fakeHbf, fakeZoo},
+		Coords: map[string]model.Coordinates{
+			fakeWarschauer.ID: {Latitude: 52.505772, Longitude: 13.449482},
+			fakeAlex.ID:       {Latitude: 52.521508, Longitude: 13.411267},
+			fakeHbf.ID:        {Latitude: 52.525592, Longitude: 13.369545},
+			fakeZoo.ID:        {Latitude: 52.506921, Longitude: 13.332711},
+		},
+		Trips: make(map[string]model.Trip),
+		Warnings: map[string][]string{
+			"U2": {"Construction work between Alexanderplatz and Zoologischer Garten: expect delays"},
+		},
+	}
+
+	start := now.Truncate(time.Minute)
+	for i := 0; i < 12; i++ {
+		delay := 0
+		if i%3 == 2 {
+			delay = 120
+		}
+		f.addTrip(S5ID(i), "S5", "suburban", "S Westkreuz",
+			start.Add(time.Duration(i*10+2)*time.Minute), delay,
+			[]model.Station{fakeWarschauer, fakeAlex, fakeHbf, fakeZoo},
+			[]time.Duration{6 * time.Minute, 5 * time.Minute, 8 * time.Minute})
+	}
+	for i := 0; i < 24; i++ {
+		f.addTrip(fmt.Sprintf("fake|U2|%d", i), "U2", "subway", "S+U Ruhleben",
+			start.Add(time.Duration(i*5+4)*time.Minute), 0,
+			[]model.Station{fakeAlex, fakeZoo},
+			[]time.Duration{19 * time.Minute})
+	}
+	f.cancel("fake|S5|4")
+	return f
+}
+
+// S5ID is the journey ID of the fixture's nth S5, counting from 0
+func S5ID(n int) string {
+	return fmt.Sprintf("fake|S5|%d", n)
+}
+
+// S5Journeys are the fixture's S5 from Warschauer Str. to Zoo, in the
+// order they leave: every ten minutes, every third late, the fifth
+// cancelled
+func (f *Fake) S5Journeys() ([]model.Journey, error) {
+	return f.Journeys(context.Background(), fakeWarschauer.ID, fakeZoo.ID, JourneyOptions{})
+}
+
+// Transfer is the fixture's nth S5 to Alexanderplatz, changing into the
+// first U2 to Zoo that leaves at least margin after the S5 arrives
+func (f *Fake) Transfer(n int, margin time.Duration) (model.Journey, error) {
+	ctx := context.Background()
+	feeders, err := f.Journeys(ctx, fakeWarschauer.ID, fakeAlex.ID, JourneyOptions{})
+	if err != nil {
+		return model.Journey{}, err
+	}
+	if n >= len(feeders) {
+		return model.Journey{}, fmt.Errorf("no S5 number %d", n)
+	}
+	feeder := feeders[n]
+	onwards, err := f.Journeys(ctx, fakeAlex.ID, fakeZoo.ID, JourneyOptions{Departure: feeder.ArriveAt.Add(margin)})
+	if err != nil {
+		return model.Journey{}, err
+	}
+	if len(onwards) == 0 {
+		return model.Journey{}, fmt.Errorf("no U2 after %s", feeder.ArriveAt)
+	}
+	onward := onwards[0].Legs[0]
+	onward.WaitBefore = onward.Departure.Sub(feeder.ArriveAt)
+	return model.Journey{
+		LeaveAt:   feeder.LeaveAt,
+		ArriveAt:  onward.Arrival,
+		Duration:  onward.Arrival.Sub(feeder.LeaveAt),
+		Legs:      []model.Leg{feeder.Legs[0], onward},
+		TotalWait: onward.WaitBefore,
+	}, nil
+}
+
+// cancel calls a fixture trip off at every stop. Like the API's, its
+// stops are left with only their planned times.
+func (f *Fake) cancel(id string) {
+	trip := f.Trips[id]
+	for i := range trip.Stopovers {
+		s := &trip.Stopovers[i]
+		s.Arrival = s.Arrival.Add(-time.Duration(s.ArrDelay) * time.Second)
+		s.Departure = s.Departure.Add(-time.Duration(s.DepDelay) * time.Second)
+		s.ArrDelay, s.DepDelay = 0, 0
+		s.Cancelled = true
+	}
+}
+
+// fakeLoad is how full every trip is at its first stops; it stays at the
+// last level from there on
+var fakeLoad = []string{"medium", "high", "low"}
+
+// addTrip adds a trip leaving its first stop at dep, hops apart, running
+// delay seconds late throughout
+func (f *Fake) addTrip(id, line, product, direction string, dep time.Time, delay int, stops []model.Station, hops []time.Duration) {
+	trip := model.Trip{ID: id, Line: line, Product: product, Direction: direction}
+	at := dep
+	for i, station := range stops {
+		if i > 0 {
+			at = at.Add(hops[i-1])
+		}
+		late := at.Add(time.Duration(delay) * time.Second)
+		trip.Stopovers = append(trip.Stopovers, model.Stopover{
+			Station:   station,
+			Arrival:   late,
+			Departure: late,
+			ArrDelay:  delay,
+			DepDelay:  delay,
+			Platform:  fmt.Sprint(i%2 + 1),
+			Occupancy: fakeLoad[min(i, len(fakeLoad)-1)],
+		})
+	}
+	f.Trips[id] = trip
+}
+
+// SearchStations matches query against station names and IDs
+func (f *Fake) SearchStations(ctx context.Context, query string) ([]model.Station, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	query = strings.ToLower(query)
+	var stations []model.Station
+	for _, s := range f.Stations {
+		if s.ID == query || strings.Contains(strings.ToLower(s.Name), query) {
+			stations = append(stations, s)
+		}
+	}
+	return stations, nil
+}
+
+// AllStations lists every fixture station
+func (f *Fake) AllStations(ctx context.Context) ([]model.Station, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return append([]model.Station(nil), f.Stations...), nil
+}
+
+// Journeys returns every direct trip that calls at origin before dest and
+// fits opts. They're put together the way the API sends them and go
+// through the same parsing, down to cancelled legs without realtime times.
+func (f *Fake) Journeys(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if opts.LaterThan != "" {
+		after, err := time.Parse(time.RFC3339, opts.LaterThan)
+		if err != nil {
+			return nil, fmt.Errorf("bad laterThan %q", opts.LaterThan)
+		}
+		opts.Departure, opts.Arrival = after, time.Time{}
+	}
+	// Addresses walk to the closest fixture stop at 80 m a minute
+	var walk *Leg
+	var walkTime time.Duration
+	if addr := opts.FromAddress; addr != nil {
+		stops, _ := f.Nearby(ctx, *addr.Location, 5000)
+		if len(stops) == 0 {
+			return nil, nil
+		}
+		originID = stops[0].ID
+		walkTime = time.Duration(stops[0].Distance) * time.Minute / 80
+		walk = &Leg{
+			Origin:      &Location{Type: "location", Address: addr.Name, Latitude: addr.Location.Latitude, Longitude: addr.Location.Longitude},
+			Destination: f.location(stops[0].Station),
+			Walking:     true,
+			Distance:    &stops[0].Distance,
+		}
+	}
+
+	var journeys []model.Journey
+	for _, trip := range f.Trips {
+		from, to := stopIndex(trip, originID), stopIndex(trip, destID)
+		if from < 0 || to <= from {
+			continue
+		}
+		dep, arr := trip.Stopovers[from], trip.Stopovers[to]
+		if !opts.Arrival.IsZero() {
+			if arr.Arrival.After(opts.Arrival) {
+				continue
+			}
+		} else if !opts.Departure.IsZero() && dep.Departure.Before(opts.Departure) {
+			continue
+		}
+
+		var aj Journey
+		if walk != nil {
+			w := *walk
+			w.Departure = formatAPITime(dep.Departure.Add(-walkTime))
+			w.Arrival = formatAPITime(dep.Departure)
+			aj.Legs = append(aj.Legs, w)
+		}
+		leg := Leg{
+			Origin:                   f.location(dep.Station),
+			Destination:              f.location(arr.Station),
+			Line:                     &Line{Name: trip.Line, Product: trip.Product},
+			Direction:                trip.Direction,
+			DeparturePlatform:        dep.Platform,
+			PlannedDeparturePlatform: dep.Platform,
+			ArrivalPlatform:          arr.Platform,
+			PlannedArrivalPlatform:   arr.Platform,
+			TripId:                   trip.ID,
+			Cancelled:                dep.Cancelled || arr.Cancelled,
+		}
+		leg.Departure, leg.PlannedDeparture, leg.DepartureDelay = apiTimes(dep.Departure, dep.DepDelay, leg.Cancelled)
+		leg.Arrival, leg.PlannedArrival, leg.ArrivalDelay = apiTimes(arr.Arrival, arr.ArrDelay, leg.Cancelled)
+		aj.Legs = append(aj.Legs, leg)
+
+		j, ok := parseJourney(aj)
+		if !ok || usesDisabledProduct(j.Legs, opts.Products) {
+			continue
+		}
+		journeys = append(journeys, j)
+	}
+	sort.Slice(journeys, func(i, j int) bool {
+		return journeys[i].LeaveAt.Before(journeys[j].LeaveAt)
+	})
+	return journeys, nil
+}
+
+// location is a fixture station as the API describes it
+func (f *Fake) location(s model.Station) *Location {
+	loc := &Location{ID: s.ID, Name: s.Name, Type: "stop"}
+	if at, ok := f.Coords[s.ID]; ok {
+		loc.Location = &struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		}{at.Latitude, at.Longitude}
+	}
+	return loc
+}
+
+// apiTimes is a stop's time as the API sends it: realtime, planned and the
+// delay, where a cancelled stop only has the planned time
+func apiTimes(at time.Time, delay int, cancelled bool) (string, string, *int) {
+	planned := formatAPITime(at.Add(-time.Duration(delay) * time.Second))
+	if cancelled {
+		return "", planned, nil
+	}
+	return formatAPITime(at), planned, &delay
+}
+
+func formatAPITime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// JourneysPage is Journeys, the reference pointing past the last one
+func (f *Fake) JourneysPage(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, string, error) {
+	journeys, err := f.Journeys(ctx, originID, destID, opts)
+	if err != nil || len(journeys) == 0 {
+		return journeys, "", err
+	}
+	last := journeys[len(journeys)-1].Legs[0].Departure
+	return journeys, last.Add(time.Second).Format(time.RFC3339), nil
+}
+
+// Departures lists the trips calling at a stop, soonest first
+func (f *Fake) Departures(ctx context.Context, stationID string) ([]model.Departure, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var departures []model.Departure
+	for _, trip := range f.Trips {
+		i := stopIndex(trip, stationID)
+		if i < 0 || i == len(trip.Stopovers)-1 {
+			continue
+		}
+		s := trip.Stopovers[i]
+		departures = append(departures, model.Departure{
+			TripID:    trip.ID,
+			Line:      trip.Line,
+			Product:   trip.Product,
+			Direction: trip.Direction,
+			When:      s.Departure,
+			Planned:   s.Departure.Add(-time.Duration(s.DepDelay) * time.Second),
+			Delay:     s.DepDelay,
+			Platform:  s.Platform,
+			Cancelled: s.Cancelled,
+		})
+	}
+	sort.Slice(departures, func(i, j int) bool {
+		return departures[i].When.Before(departures[j].When)
+	})
+	return departures, nil
+}
+
+// Arrivals lists the trips arriving at a stop, soonest first, coming
+// from where they started
+func (f *Fake) Arrivals(ctx context.Context, stationID string) ([]model.Departure, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var arrivals []model.Departure
+	for _, trip := range f.Trips {
+		i := stopIndex(trip, stationID)
+		if i <= 0 {
+			continue
+		}
+		s := trip.Stopovers[i]
+		arrivals = append(arrivals, model.Departure{
+			TripID:    trip.ID,
+			Line:      trip.Line,
+			Product:   trip.Product,
+			Direction: trip.Stopovers[0].Station.Name,
+			When:      s.Arrival,
+			Planned:   s.Arrival.Add(-time.Duration(s.ArrDelay) * time.Second),
+			Delay:     s.ArrDelay,
+			Platform:  s.Platform,
+			Cancelled: s.Cancelled,
+		})
+	}
+	sort.Slice(arrivals, func(i, j int) bool {
+		return arrivals[i].When.Before(arrivals[j].When)
+	})
+	return arrivals, nil
+}
+
+// TripPath runs a fixture trip straight from stop to stop
+func (f *Fake) TripPath(ctx context.Context, tripID string) ([]model.PathPoint, error) {
+	trip, err := f.Trip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	var path []model.PathPoint
+	for _, s := range trip.Stopovers {
+		if at, ok := f.Coords[s.Station.ID]; ok {
+			path = append(path, model.PathPoint{At: at, StopID: s.Station.ID})
+		}
+	}
+	return path, nil
+}
+
+// Trip returns a fixture trip by ID
+func (f *Fake) Trip(ctx context.Context, tripID string) (model.Trip, error) {
+	if f.Err != nil {
+		return model.Trip{}, f.Err
+	}
+	trip, ok := f.Trips[tripID]
+	if !ok {
+		return model.Trip{}, fmt.Errorf("unknown trip %s", tripID)
+	}
+	return trip, nil
+}
+
+// Nearby returns the fixture stations within radius meters of at
+func (f *Fake) Nearby(ctx context.Context, at model.Coordinates, radius int) ([]model.NearbyStop, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var stops []model.NearbyStop
+	for _, s := range f.Stations {
+		c, ok := f.Coords[s.ID]
+		if !ok {
+			continue
+		}
+		if d := model.Distance(at, c); d <= radius {
+			stops = append(stops, model.NearbyStop{Station: s, Distance: d})
+		}
+	}
+	sort.Slice(stops, func(i, j int) bool { return stops[i].Distance < stops[j].Distance })
+	return stops, nil
+}
+
+// Geocode places the first station whose name contains the query
+func (f *Fake) Geocode(ctx context.Context, query string) (model.Coordinates, error) {
+	if f.Err != nil {
+		return model.Coordinates{}, f.Err
+	}
+	query = strings.ToLower(query)
+	for _, s := range f.Stations {
+		if c, ok := f.Coords[s.ID]; ok && strings.Contains(strings.ToLower(s.Name), query) {
+			return c, nil
+		}
+	}
+	return model.Coordinates{}, fmt.Errorf("can't place %q", query)
+}
+
+// LineWarnings returns the canned warnings for a line
+func (f *Fake) LineWarnings(ctx context.Context, line string) ([]string, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Warnings[line], nil
+}
+
+func stopIndex(trip model.Trip, stationID string) int {
+	for i, s := range trip.Stopovers {
+		if s.Station.ID == stationID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,87 @@
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go-commute/internal/model"
+)
+
+// maxConcurrentFetches bounds how many requests run at once, so a long
+// favorites list or a busy neighbourhood doesn't hammer the API
+const maxConcurrentFetches = 4
+
+// RouteResult is the outcome of fetching one route
+type RouteResult struct {
+	Route    model.FavoriteRoute
+	Journeys []model.Journey
+	Err      error
+}
+
+// BoardResult is the outcome of fetching one stop's departures
+type BoardResult struct {
+	Station    model.Station
+	Departures []model.Departure
+	Err        error
+}
+
+// FetchRoutes fetches all routes through a bounded worker pool. Results keep
+// the order of routes, and a failing or panicking route only sets its own Err.
+func FetchRoutes(ctx context.Context, c TransitClient, routes []model.FavoriteRoute) []RouteResult {
+	results := make([]RouteResult, len(routes))
+	forEachBounded(len(routes), func(i int) {
+		results[i].Route = routes[i]
+		results[i].Err = guard(func() (err error) {
+			results[i].Journeys, err = c.Journeys(ctx, routes[i].Origin.ID, routes[i].Dest.ID, JourneyOptions{}.From(routes[i].Origin))
+			return err
+		})
+	})
+	return results
+}
+
+// FetchBoards fetches the departures of all stations the same way
+func FetchBoards(ctx context.Context, c TransitClient, stations []model.Station) []BoardResult {
+	results := make([]BoardResult, len(stations))
+	forEachBounded(len(stations), func(i int) {
+		results[i].Station = stations[i]
+		results[i].Err = guard(func() (err error) {
+			results[i].Departures, err = c.Departures(ctx, stations[i].ID)
+			return err
+		})
+	})
+	return results
+}
+
+// forEachBounded calls f for 0..n-1 on at most maxConcurrentFetches
+// goroutines and waits for all of them
+func forEachBounded(n int, f func(i int)) {
+	jobs := make(chan int)
+	workers := min(maxConcurrentFetches, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// guard turns a panic in f into an error
+func guard(f func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("fetch panicked: %v", p)
+		}
+	}()
+	return f()
+}
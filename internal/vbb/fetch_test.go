@@ -0,0 +1,82 @@
+package vbb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// countingClient answers journeys after a pause, keeping count of how
+// many are asked for at once. Destinations "fail" and "panic" do that.
+type countingClient struct {
+	TransitClient
+	mu            sync.Mutex
+	running, most int
+}
+
+func (c *countingClient) Journeys(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, error) {
+	c.mu.Lock()
+	c.running++
+	c.most = max(c.most, c.running)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.running--
+		c.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	switch destID {
+	case "fail":
+		return nil, errors.New("no route")
+	case "panic":
+		panic("bad response")
+	}
+	return []model.Journey{{Legs: []model.Leg{{Line: destID}}}}, nil
+}
+
+func TestFetchRoutes(t *testing.T) {
+	var routes []model.FavoriteRoute
+	for i := 0; i < 10; i++ {
+		dest := fmt.Sprint(i)
+		switch i {
+		case 3:
+			dest = "fail"
+		case 7:
+			dest = "panic"
+		}
+		routes = append(routes, model.FavoriteRoute{Dest: model.Station{ID: dest}})
+	}
+	c := &countingClient{}
+	results := FetchRoutes(context.Background(), c, routes)
+
+	if len(results) != len(routes) {
+		t.Fatalf("got %d results for %d routes", len(results), len(routes))
+	}
+	for i, res := range results {
+		if res.Route.Dest.ID != routes[i].Dest.ID {
+			t.Errorf("result %d is for %s", i, res.Route.Dest.ID)
+		}
+		switch i {
+		case 3, 7:
+			if res.Err == nil {
+				t.Errorf("route %d has no error", i)
+			}
+		default:
+			if res.Err != nil || len(res.Journeys) != 1 || res.Journeys[0].Legs[0].Line != routes[i].Dest.ID {
+				t.Errorf("route %d: %v, %v", i, res.Journeys, res.Err)
+			}
+		}
+	}
+	if c.most > maxConcurrentFetches {
+		t.Errorf("%d fetches at once, want at most %d", c.most, maxConcurrentFetches)
+	}
+	if len(FetchRoutes(context.Background(), c, nil)) != 0 {
+		t.Error("results without routes")
+	}
+}
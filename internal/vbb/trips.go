@@ -0,0 +1,183 @@
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-commute/internal/model"
+)
+
+// Trip is a trip as returned by the /trips endpoints
+type Trip struct {
+	ID        string     `json:"id"`
+	Direction string     `json:"direction"`
+	Line      *Line      `json:"line"`
+	Remarks   []Remark   `json:"remarks"`
+	Stopovers []Stopover `json:"stopovers"`
+
+	CurrentLocation *struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"currentLocation"`
+}
+
+type Stopover struct {
+	Stop              *Location `json:"stop"`
+	Arrival           string    `json:"arrival"`
+	PlannedArrival    string    `json:"plannedArrival"`
+	ArrivalDelay      *int      `json:"arrivalDelay"`
+	ArrivalPlatform   string    `json:"arrivalPlatform"`
+	Departure         string    `json:"departure"`
+	PlannedDeparture  string    `json:"plannedDeparture"`
+	DepartureDelay    *int      `json:"departureDelay"`
+	DeparturePlatform string    `json:"departurePlatform"`
+	Cancelled         bool      `json:"cancelled"`
+	LoadFactor        string    `json:"loadFactor"`
+}
+
+type TripsResponse struct {
+	Trips []Trip `json:"trips"`
+}
+
+type TripResponse struct {
+	Trip Trip `json:"trip"`
+}
+
+// Trip fetches a trip with all its stopovers
+func (c *HTTPClient) Trip(ctx context.Context, tripID string) (model.Trip, error) {
+	params := url.Values{}
+	params.Set("stopovers", "true")
+	params.Set("remarks", "false")
+
+	var apiResp TripResponse
+	if err := c.getJSON(ctx, "/trips/"+url.PathEscape(tripID), params, &apiResp); err != nil {
+		return model.Trip{}, err
+	}
+	at := apiResp.Trip
+
+	trip := model.Trip{ID: at.ID, Direction: at.Direction}
+	if at.Line != nil {
+		trip.Line = at.Line.Name
+		trip.Product = at.Line.Product
+	}
+	if at.CurrentLocation != nil {
+		trip.Position = &model.Coordinates{Latitude: at.CurrentLocation.Latitude, Longitude: at.CurrentLocation.Longitude}
+	}
+	for _, as := range at.Stopovers {
+		if as.Stop == nil {
+			continue
+		}
+		s := model.Stopover{
+			Station:   model.Station{ID: as.Stop.ID, Name: as.Stop.Name, Type: as.Stop.Type},
+			ArrDelay:  derefInt(as.ArrivalDelay),
+			DepDelay:  derefInt(as.DepartureDelay),
+			Platform:  as.DeparturePlatform,
+			Cancelled: as.Cancelled,
+			Occupancy: parseLoadFactor(as.LoadFactor),
+		}
+		if at := as.Stop.Coords(); at != (model.Coordinates{}) {
+			s.Station.Location = &at
+		}
+		if s.Platform == "" {
+			s.Platform = as.ArrivalPlatform
+		}
+		s.Arrival, _ = parseTime(firstNonEmpty(as.Arrival, as.PlannedArrival))
+		s.Departure, _ = parseTime(firstNonEmpty(as.Departure, as.PlannedDeparture))
+		trip.Stopovers = append(trip.Stopovers, s)
+	}
+	return trip, nil
+}
+
+// Polyline is the GeoJSON a trip's route comes as: a point per bend in the
+// line, with the stop in the properties of points at a stop
+type Polyline struct {
+	Features []struct {
+		Properties struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"properties"`
+		Geometry struct {
+			Coordinates []float64 `json:"coordinates"` // longitude, latitude
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// TripPath fetches the route a trip takes on the map
+func (c *HTTPClient) TripPath(ctx context.Context, tripID string) ([]model.PathPoint, error) {
+	params := url.Values{}
+	params.Set("stopovers", "false")
+	params.Set("remarks", "false")
+	params.Set("polyline", "true")
+
+	var apiResp struct {
+		Trip struct {
+			Polyline *Polyline `json:"polyline"`
+		} `json:"trip"`
+	}
+	if err := c.getJSON(ctx, "/trips/"+url.PathEscape(tripID), params, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Trip.Polyline == nil {
+		return nil, fmt.Errorf("no route shape for trip %s", tripID)
+	}
+	var path []model.PathPoint
+	for _, f := range apiResp.Trip.Polyline.Features {
+		if len(f.Geometry.Coordinates) < 2 {
+			continue
+		}
+		p := model.PathPoint{At: model.Coordinates{Latitude: f.Geometry.Coordinates[1], Longitude: f.Geometry.Coordinates[0]}}
+		if f.Properties.Type == "stop" || f.Properties.Type == "station" {
+			p.StopID = f.Properties.ID
+		}
+		path = append(path, p)
+	}
+	return path, nil
+}
+
+// LineWarnings collects the warning remarks of all currently running trips
+// of a line
+func (c *HTTPClient) LineWarnings(ctx context.Context, line string) ([]string, error) {
+	params := url.Values{}
+	params.Set("query", line)
+	params.Set("onlyCurrentlyRunning", "true")
+	params.Set("stopovers", "false")
+	params.Set("remarks", "true")
+
+	var apiResp TripsResponse
+	if err := c.getJSON(ctx, "/trips", params, &apiResp); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var warnings []string
+	for _, trip := range apiResp.Trips {
+		if trip.Line == nil || !strings.EqualFold(trip.Line.Name, line) {
+			continue
+		}
+		for _, w := range parseServiceStatus(trip.Remarks) {
+			if !seen[w] {
+				seen[w] = true
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func derefInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
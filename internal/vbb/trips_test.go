@@ -0,0 +1,51 @@
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLineWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/trips" || q.Get("onlyCurrentlyRunning") != "true" || q.Get("remarks") != "true" {
+			t.Errorf("asked for %s", r.URL)
+		}
+		fmt.Fprint(w, `{"trips":[
+			{"id":"1","line":{"name":"S5"},"remarks":[
+				{"type":"warning","text":"Construction work at Ostkreuz"},
+				{"type":"hint","text":"Bicycles allowed"}]},
+			{"id":"2","line":{"name":"S5"},"remarks":[
+				{"type":"warning","text":"Construction work at Ostkreuz"},
+				{"type":"status","text":"Replacement buses to Erkner"}]},
+			{"id":"3","line":{"name":"S75"},"remarks":[{"type":"warning","text":"Signal failure"}]},
+			{"id":"4","remarks":[{"type":"warning","text":"No line"}]}]}`)
+	}))
+	defer srv.Close()
+	c := &HTTPClient{BaseURL: srv.URL, HTTP: srv.Client()}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"S5", []string{"Construction work at Ostkreuz", "Replacement buses to Erkner"}},
+		{"s5", []string{"Construction work at Ostkreuz", "Replacement buses to Erkner"}},
+		{"S75", []string{"Signal failure"}},
+		{"U2", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := c.LineWarnings(context.Background(), tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
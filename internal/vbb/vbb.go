@@ -0,0 +1,630 @@
+// Package vbb talks to the VBB hafas-rest API and converts its responses
+// into model types.
+package vbb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+// BaseURL is the VBB hafas-rest API the client talks to
+const BaseURL = "https://v6.vbb.transport.rest"
+
+// API Response types
+type Location struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Distance int    `json:"distance"`
+	Location *struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"location"`
+
+	// Addresses and points of interest carry their position themselves
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Coords is where the location is, or the zero value if the API left it out
+func (l *Location) Coords() model.Coordinates {
+	if l == nil {
+		return model.Coordinates{}
+	}
+	if l.Location == nil {
+		return model.Coordinates{Latitude: l.Latitude, Longitude: l.Longitude}
+	}
+	return model.Coordinates{Latitude: l.Location.Latitude, Longitude: l.Location.Longitude}
+}
+
+type Line struct {
+	Name    string `json:"name"`
+	Product string `json:"product"`
+	Color   struct {
+		FG string `json:"fg"`
+		BG string `json:"bg"`
+	} `json:"color"`
+}
+
+type Remark struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Text    string `json:"text"`
+	Summary string `json:"summary"`
+}
+
+type Leg struct {
+	Origin                   *Location `json:"origin"`
+	Destination              *Location `json:"destination"`
+	Departure                string    `json:"departure"`
+	Arrival                  string    `json:"arrival"`
+	Line                     *Line     `json:"line"`
+	Direction                string    `json:"direction"`
+	DepartureDelay           *int      `json:"departureDelay"`
+	ArrivalDelay             *int      `json:"arrivalDelay"`
+	DeparturePlatform        string    `json:"departurePlatform"`
+	PlannedDeparturePlatform string    `json:"plannedDeparturePlatform"`
+	ArrivalPlatform          string    `json:"arrivalPlatform"`
+	PlannedArrivalPlatform   string    `json:"plannedArrivalPlatform"`
+	Remarks                  []Remark  `json:"remarks"`
+	TripId                   string    `json:"tripId"`
+	PlannedDeparture         string    `json:"plannedDeparture"`
+	PlannedArrival           string    `json:"plannedArrival"`
+	Cancelled                bool      `json:"cancelled"`
+	LoadFactor               string    `json:"loadFactor"`
+	Walking                  bool      `json:"walking"`
+	Distance                 *int      `json:"distance"`
+	Cycle                    *struct {
+		Min int `json:"min"`
+	} `json:"cycle"`
+}
+
+type Journey struct {
+	Legs         []Leg  `json:"legs"`
+	RefreshToken string `json:"refreshToken"`
+	Price        *struct {
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	} `json:"price"`
+}
+
+type JourneysResponse struct {
+	Journeys []Journey `json:"journeys"`
+	LaterRef string    `json:"laterRef"`
+}
+
+func parseTime(isoString string) (time.Time, error) {
+	if isoString == "" {
+		return time.Time{}, fmt.Errorf("empty time string")
+	}
+	isoString = strings.ReplaceAll(isoString, "Z", "+00:00")
+	return time.Parse(time.RFC3339, isoString)
+}
+
+// SearchStations looks up stops matching a free-text query
+func (c *HTTPClient) SearchStations(ctx context.Context, query string) ([]model.Station, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("results", "10")
+
+	var locations []Location
+	if err := c.getJSON(ctx, "/locations", params, &locations); err != nil {
+		return nil, err
+	}
+
+	var stations []model.Station
+	for _, loc := range locations {
+		if loc.Type == "stop" {
+			stations = append(stations, model.Station{
+				ID:   loc.ID,
+				Name: loc.Name,
+				Type: loc.Type,
+			})
+		}
+	}
+	return stations, nil
+}
+
+// AllStations downloads the network's whole stop list. vbb-rest serves it
+// as an object keyed by stop ID; instances without /stations fail here and
+// search stays online.
+func (c *HTTPClient) AllStations(ctx context.Context) ([]model.Station, error) {
+	var byID map[string]Location
+	if err := c.getJSON(ctx, "/stations", nil, &byID); err != nil {
+		return nil, fmt.Errorf("listing stops: %w", err)
+	}
+	stations := make([]model.Station, 0, len(byID))
+	for id, loc := range byID {
+		if loc.ID == "" {
+			loc.ID = id
+		}
+		stations = append(stations, model.Station{ID: loc.ID, Name: loc.Name, Type: "stop"})
+	}
+	return stations, nil
+}
+
+// Station looks up a stop by its ID
+func (c *HTTPClient) Station(ctx context.Context, id string) (model.Station, error) {
+	var loc Location
+	if err := c.getJSON(ctx, "/stops/"+url.PathEscape(id), nil, &loc); err != nil {
+		return model.Station{}, fmt.Errorf("unknown station %s: %w", id, err)
+	}
+	return model.Station{ID: loc.ID, Name: loc.Name, Type: loc.Type}, nil
+}
+
+// Nearby finds the stops within radius meters of at, closest first
+func (c *HTTPClient) Nearby(ctx context.Context, at model.Coordinates, radius int) ([]model.NearbyStop, error) {
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(at.Latitude, 'f', 6, 64))
+	params.Set("longitude", strconv.FormatFloat(at.Longitude, 'f', 6, 64))
+	params.Set("distance", strconv.Itoa(radius))
+	params.Set("results", "20")
+
+	var locations []Location
+	if err := c.getJSON(ctx, "/locations/nearby", params, &locations); err != nil {
+		return nil, err
+	}
+
+	var stops []model.NearbyStop
+	for _, loc := range locations {
+		if loc.Type == "stop" {
+			stops = append(stops, model.NearbyStop{
+				Station:  model.Station{ID: loc.ID, Name: loc.Name, Type: loc.Type},
+				Distance: loc.Distance,
+			})
+		}
+	}
+	sort.Slice(stops, func(i, j int) bool { return stops[i].Distance < stops[j].Distance })
+	return stops, nil
+}
+
+// Geocode finds where an address, point of interest or stop is
+func (c *HTTPClient) Geocode(ctx context.Context, query string) (model.Coordinates, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("results", "1")
+	params.Set("addresses", "true")
+	params.Set("poi", "true")
+	params.Set("stops", "true")
+
+	var locations []Location
+	if err := c.getJSON(ctx, "/locations", params, &locations); err != nil {
+		return model.Coordinates{}, err
+	}
+	for _, loc := range locations {
+		if at := loc.Coords(); at != (model.Coordinates{}) {
+			return at, nil
+		}
+	}
+	return model.Coordinates{}, fmt.Errorf("can't place %q", query)
+}
+
+// ResolveStation turns a station ID or a free-text query into a Station
+func (c *HTTPClient) ResolveStation(ctx context.Context, query string) (model.Station, error) {
+	return Resolve(ctx, c, query)
+}
+
+// Resolve turns a station ID or a free-text query into a Station with any
+// client. IDs are looked up where the client can, so the station keeps its
+// proper name; names take the best match.
+func Resolve(ctx context.Context, c TransitClient, query string) (model.Station, error) {
+	if looker, ok := c.(StationLooker); ok && model.IsStationID(query) {
+		return looker.Station(ctx, query)
+	}
+	stations, err := c.SearchStations(ctx, query)
+	if err != nil {
+		return model.Station{}, err
+	}
+	if len(stations) == 0 {
+		return model.Station{}, fmt.Errorf("no station matches %q", query)
+	}
+	for _, s := range stations {
+		if s.ID == query {
+			return s, nil
+		}
+	}
+	return stations[0], nil
+}
+
+func parseOccupancy(remarks []Remark) string {
+	for _, r := range remarks {
+		code := strings.ToLower(r.Code)
+		text := strings.ToLower(r.Text)
+		if strings.Contains(code, "occup") || strings.Contains(text, "occupancy") {
+			if strings.Contains(text, "low") {
+				return "low"
+			} else if strings.Contains(text, "medium") || strings.Contains(text, "moderate") {
+				return "medium"
+			} else if strings.Contains(text, "high") {
+				return "high"
+			}
+		}
+	}
+	return ""
+}
+
+// parseLoadFactor maps hafas' loadFactor onto the occupancy levels the
+// remarks give
+func parseLoadFactor(factor string) string {
+	switch factor {
+	case "low-to-medium":
+		return "low"
+	case "high":
+		return "medium"
+	case "very-high", "exceptionally-high":
+		return "high"
+	}
+	return ""
+}
+
+var (
+	accessWords = []string{"aufzug", "fahrstuhl", "fahrtreppe", "rolltreppe", "barrierefrei", "stufenfrei",
+		"elevator", "lift", "escalator", "step-free", "wheelchair", "rollstuhl"}
+	outageWords = []string{"außer betrieb", "defekt", "gestört", "nicht nutzbar", "nicht verfügbar",
+		"out of service", "out of order", "not in operation", "not available", "broken"}
+)
+
+// parseAccessibility picks the remarks about elevators, escalators and
+// step-free access, and whether any of them reports an outage. hafas-rest
+// has no live elevator status, so remarks are all there is.
+func parseAccessibility(remarks []Remark) (notes []string, outage bool) {
+	seen := make(map[string]bool)
+	for _, r := range remarks {
+		text := r.Text
+		if text == "" {
+			text = r.Summary
+		}
+		lower := strings.ToLower(r.Summary + " " + text)
+		if text == "" || seen[text] || !containsAny(lower, accessWords) {
+			continue
+		}
+		seen[text] = true
+		notes = append(notes, text)
+		if containsAny(lower, outageWords) {
+			outage = true
+		}
+	}
+	return notes, outage
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseServiceStatus(remarks []Remark) []string {
+	var statuses []string
+	for _, r := range remarks {
+		if r.Type == "warning" || r.Type == "status" {
+			if r.Text != "" {
+				statuses = append(statuses, r.Text)
+			}
+		}
+	}
+	return statuses
+}
+
+// parseWalk reads the walk from an address to the first stop
+func parseWalk(al Leg) *model.Walk {
+	dep, err := parseTime(al.Departure)
+	if err != nil {
+		return nil
+	}
+	arr, err := parseTime(al.Arrival)
+	if err != nil {
+		return nil
+	}
+	walk := &model.Walk{
+		Departure:  dep,
+		Duration:   arr.Sub(dep),
+		Distance:   derefInt(al.Distance),
+		FromCoords: al.Origin.Coords(),
+		ToCoords:   al.Destination.Coords(),
+	}
+	if al.Origin != nil {
+		walk.From = firstNonEmpty(al.Origin.Name, al.Origin.Address)
+	}
+	if al.Destination != nil {
+		walk.To = al.Destination.Name
+	}
+	// Some instances leave the distance out; estimate it
+	if walk.Distance == 0 {
+		walk.Distance = model.Distance(walk.FromCoords, walk.ToCoords)
+	}
+	return walk
+}
+
+// parseRemarks keeps every remark with text, typed "hint" when the API
+// leaves the type out
+func parseRemarks(remarks []Remark) []model.Remark {
+	var out []model.Remark
+	for _, r := range remarks {
+		if r.Text == "" && r.Summary == "" {
+			continue
+		}
+		typ := r.Type
+		if typ != "warning" && typ != "status" {
+			typ = "hint"
+		}
+		out = append(out, model.Remark{Type: typ, Summary: r.Summary, Text: r.Text})
+	}
+	return out
+}
+
+// usesDisabledProduct reports whether any leg rides a product switched off
+// in products
+func usesDisabledProduct(legs []model.Leg, products map[string]bool) bool {
+	for _, leg := range legs {
+		if enabled, exists := products[leg.Product]; exists && !enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// Journeys plans journeys between two stops, dropping those that use a
+// product disabled in opts, sorted by departure
+func (c *HTTPClient) Journeys(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, error) {
+	journeys, _, err := c.JourneysPage(ctx, originID, destID, opts)
+	return journeys, err
+}
+
+// JourneysPage is Journeys that also returns the reference for the
+// journeys after these, empty when the API has none
+func (c *HTTPClient) JourneysPage(ctx context.Context, originID, destID string, opts JourneyOptions) ([]model.Journey, string, error) {
+	params := url.Values{}
+	if addr := opts.FromAddress; addr != nil {
+		params.Set("from.address", addr.Name)
+		params.Set("from.latitude", strconv.FormatFloat(addr.Location.Latitude, 'f', 6, 64))
+		params.Set("from.longitude", strconv.FormatFloat(addr.Location.Longitude, 'f', 6, 64))
+	} else {
+		params.Set("from", originID)
+	}
+	params.Set("to", destID)
+	transfers := DefaultTransfers
+	if opts.Transfers != nil {
+		transfers = *opts.Transfers
+	}
+	params.Set("transfers", strconv.Itoa(transfers))
+	for product, enabled := range opts.Products {
+		if !enabled {
+			params.Set(product, "false")
+		}
+	}
+	params.Set("results", "25")
+	params.Set("remarks", "true")
+	switch {
+	case opts.LaterThan != "":
+		params.Set("laterThan", opts.LaterThan)
+	case !opts.Arrival.IsZero():
+		params.Set("arrival", opts.Arrival.Format(time.RFC3339))
+	case !opts.Departure.IsZero():
+		params.Set("departure", opts.Departure.Format(time.RFC3339))
+	}
+
+	var apiResp JourneysResponse
+	if err := c.getJSON(ctx, "/journeys", params, &apiResp); err != nil {
+		return nil, "", err
+	}
+
+	var journeys []model.Journey
+
+	for _, aj := range apiResp.Journeys {
+		journey, ok := parseJourney(aj)
+		if !ok || usesDisabledProduct(journey.Legs, opts.Products) {
+			continue
+		}
+		journeys = append(journeys, journey)
+	}
+
+	sort.Slice(journeys, func(i, j int) bool {
+		if journeys[i].LeaveAt.Equal(journeys[j].LeaveAt) {
+			return journeys[i].TotalWait < journeys[j].TotalWait
+		}
+		return journeys[i].LeaveAt.Before(journeys[j].LeaveAt)
+	})
+
+	return journeys, apiResp.LaterRef, nil
+}
+
+// RefreshJourney fetches a journey again by its refresh token
+func (c *HTTPClient) RefreshJourney(ctx context.Context, token string) (model.Journey, error) {
+	params := url.Values{}
+	params.Set("stopovers", "false")
+	params.Set("remarks", "true")
+
+	var apiResp struct {
+		Journey Journey `json:"journey"`
+	}
+	if err := c.getJSON(ctx, "/journeys/"+url.PathEscape(token), params, &apiResp); err != nil {
+		return model.Journey{}, err
+	}
+	j, ok := parseJourney(apiResp.Journey)
+	if !ok {
+		return model.Journey{}, fmt.Errorf("journey %s has no usable legs", token)
+	}
+	j.IsNew = false
+	return j, nil
+}
+
+// parseJourney turns an API journey into the model's, walks folded into
+// the legs around them. ok is false for journeys without a usable leg.
+func parseJourney(aj Journey) (model.Journey, bool) {
+	if len(aj.Legs) == 0 {
+		return model.Journey{}, false
+	}
+
+	var legs []model.Leg
+	var walk *model.Walk
+	var walking time.Duration
+	var walkingDistance int
+	var totalWait time.Duration
+	var prevArrival time.Time
+
+	for _, al := range aj.Legs {
+		if al.Line == nil {
+			if arr, err := parseTime(al.Arrival); err == nil {
+				prevArrival = arr
+			}
+			if al.Walking {
+				w := parseWalk(al)
+				if w != nil {
+					walking += w.Duration
+					walkingDistance += w.Distance
+				}
+				if len(legs) == 0 {
+					walk = w
+				}
+			}
+			continue
+		}
+
+		// Cancelled legs only carry planned times
+		depStr, arrStr := al.Departure, al.Arrival
+		if al.Cancelled {
+			if depStr == "" {
+				depStr = al.PlannedDeparture
+			}
+			if arrStr == "" {
+				arrStr = al.PlannedArrival
+			}
+		}
+
+		dep, err := parseTime(depStr)
+		if err != nil {
+			slog.Debug("skipping leg", "line", al.Line.Name, "trip", al.TripId, "err", err)
+			continue
+		}
+		arr, err := parseTime(arrStr)
+		if err != nil {
+			slog.Debug("skipping leg", "line", al.Line.Name, "trip", al.TripId, "err", err)
+			continue
+		}
+
+		var wait time.Duration
+		if !prevArrival.IsZero() && dep.After(prevArrival) {
+			wait = dep.Sub(prevArrival)
+			totalWait += wait
+		}
+
+		var originName, originID, destName, destID string
+		if al.Origin != nil {
+			originName, originID = al.Origin.Name, al.Origin.ID
+		}
+		if al.Destination != nil {
+			destName, destID = al.Destination.Name, al.Destination.ID
+		}
+
+		depDelay := 0
+		if al.DepartureDelay != nil {
+			depDelay = *al.DepartureDelay
+		}
+		arrDelay := 0
+		if al.ArrivalDelay != nil {
+			arrDelay = *al.ArrivalDelay
+		}
+
+		depPlatform := al.DeparturePlatform
+		if depPlatform == "" {
+			depPlatform = al.PlannedDeparturePlatform
+		}
+		arrPlatform := al.ArrivalPlatform
+		if arrPlatform == "" {
+			arrPlatform = al.PlannedArrivalPlatform
+		}
+
+		cycle := 0
+		if al.Cycle != nil {
+			cycle = al.Cycle.Min / 60
+		}
+
+		lineColor := ""
+		if al.Line.Color.BG != "" {
+			lineColor = al.Line.Color.BG
+		}
+
+		access, outage := parseAccessibility(al.Remarks)
+		leg := model.Leg{
+			Line:          al.Line.Name,
+			Product:       al.Line.Product,
+			Direction:     al.Direction,
+			From:          originName,
+			To:            destName,
+			Departure:     dep,
+			Arrival:       arr,
+			WaitBefore:    wait,
+			DepDelay:      depDelay,
+			ArrDelay:      arrDelay,
+			Occupancy:     firstNonEmpty(parseOccupancy(al.Remarks), parseLoadFactor(al.LoadFactor)),
+			ServiceStatus: parseServiceStatus(al.Remarks),
+			Remarks:       parseRemarks(al.Remarks),
+			DepPlatform:   depPlatform,
+			ArrPlatform:   arrPlatform,
+			Cycle:         cycle,
+			LineColor:     lineColor,
+			TripID:        al.TripId,
+
+			PlannedDepPlatform: al.PlannedDeparturePlatform,
+			Cancelled:          al.Cancelled,
+
+			FromID:     originID,
+			ToID:       destID,
+			FromCoords: al.Origin.Coords(),
+			ToCoords:   al.Destination.Coords(),
+
+			Accessibility: access,
+			AccessOutage:  outage,
+		}
+
+		legs = append(legs, leg)
+		prevArrival = arr
+	}
+
+	if len(legs) == 0 {
+		return model.Journey{}, false
+	}
+
+	// A cancelled first leg only has its planned departure
+	first := aj.Legs[0]
+	journeyStart, err := parseTime(firstNonEmpty(first.Departure, first.PlannedDeparture))
+	if err != nil {
+		slog.Debug("skipping journey", "refreshToken", aj.RefreshToken, "err", err)
+		return model.Journey{}, false
+	}
+	lastArr := legs[len(legs)-1].Arrival
+	if journeyStart.IsZero() || lastArr.IsZero() {
+		return model.Journey{}, false
+	}
+
+	journey := model.Journey{
+		LeaveAt:   journeyStart,
+		ArriveAt:  lastArr,
+		Duration:  lastArr.Sub(journeyStart),
+		TotalWait: totalWait,
+		Legs:      legs,
+		IsNew:     true,
+
+		RefreshToken: aj.RefreshToken,
+		Walk:         walk,
+
+		Walking:         walking,
+		WalkingDistance: walkingDistance,
+	}
+	if aj.Price != nil && aj.Price.Amount > 0 {
+		journey.Price = &model.Price{Amount: aj.Price.Amount, Currency: aj.Price.Currency}
+	}
+	return journey, true
+}
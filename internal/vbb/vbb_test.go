@@ -0,0 +1,352 @@
+package vbb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestResolveStation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/stops/900100003":
+			fmt.Fprint(w, `{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"}`)
+		case r.URL.Path == "/locations" && r.URL.Query().Get("query") == "alex":
+			fmt.Fprint(w, `[{"type":"location","name":"Alexanderstr. 1"},
+				{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"},
+				{"type":"stop","id":"900100026","name":"S+U Alexanderplatz/Dircksenstr. (Berlin)"}]`)
+		case r.URL.Path == "/locations" && r.URL.Query().Get("query") == "12345":
+			fmt.Fprint(w, `[{"type":"stop","id":"900000001","name":"Other"},{"type":"stop","id":"12345","name":"Bus stop"}]`)
+		case r.URL.Path == "/locations":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := &HTTPClient{BaseURL: srv.URL, HTTP: srv.Client()}
+
+	tests := []struct {
+		query string
+		want  string // station ID, empty for an error
+	}{
+		{"900100003", "900100003"},
+		{"alex", "900100003"},
+		{"Nowhere", ""},
+		{"900999999", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := c.ResolveStation(context.Background(), tt.query)
+			if (err == nil) != (tt.want != "") {
+				t.Fatalf("err = %v, want a station %v", err, tt.want != "")
+			}
+			if got.ID != tt.want {
+				t.Errorf("got %s %q, want %s", got.ID, got.Name, tt.want)
+			}
+		})
+	}
+
+	// Clients without a lookup by ID search for it and take the exact match
+	got, err := Resolve(context.Background(), searchOnly{c}, "12345")
+	if err != nil || got.ID != "12345" {
+		t.Errorf("got %s, %v, want the exact match", got.ID, err)
+	}
+}
+
+// searchOnly hides everything of a client but search
+type searchOnly struct{ TransitClient }
+
+func TestFakeJourneys(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		from, to      string
+		opts          JourneyOptions
+		count, late   int
+		first, second string // lines of the first two journeys
+	}{
+		{"S5 only", fakeWarschauer.ID, fakeZoo.ID, JourneyOptions{}, 12, 4, "S5", "S5"},
+		{"S5 and U2", fakeAlex.ID, fakeZoo.ID, JourneyOptions{}, 36, 4, "U2", "S5"},
+		{"U2 filtered out", fakeAlex.ID, fakeZoo.ID, JourneyOptions{Products: map[string]bool{"subway": false}}, 12, 4, "S5", "S5"},
+		{"leaving after nine", fakeWarschauer.ID, fakeZoo.ID, JourneyOptions{Departure: now.Add(time.Hour)}, 6, 2, "S5", "S5"},
+		{"wrong way", fakeZoo.ID, fakeWarschauer.ID, JourneyOptions{}, 0, 0, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journeys, err := NewFake(now).Journeys(context.Background(), tt.from, tt.to, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(journeys) != tt.count {
+				t.Fatalf("got %d journeys, want %d", len(journeys), tt.count)
+			}
+			var late int
+			for i, j := range journeys {
+				if i > 0 && j.LeaveAt.Before(journeys[i-1].LeaveAt) {
+					t.Errorf("journey %d leaves before the one ahead of it", i)
+				}
+				if j.Legs[0].DepDelay > 0 {
+					late++
+				}
+			}
+			if late != tt.late {
+				t.Errorf("%d late, want %d", late, tt.late)
+			}
+			if tt.count >= 2 && (journeys[0].Legs[0].Line != tt.first || journeys[1].Legs[0].Line != tt.second) {
+				t.Errorf("starts with %s and %s, want %s and %s", journeys[0].Legs[0].Line, journeys[1].Legs[0].Line, tt.first, tt.second)
+			}
+		})
+	}
+
+	f := NewFake(now)
+	f.Err = errors.New("offline")
+	if _, err := f.Journeys(context.Background(), fakeAlex.ID, fakeZoo.ID, JourneyOptions{}); err == nil {
+		t.Error("no error from a failing fake")
+	}
+}
+
+func TestParseJourney(t *testing.T) {
+	delay := 120
+	alex := &Location{ID: "900100003", Name: "S+U Alexanderplatz", Type: "stop"}
+	zoo := &Location{ID: "900023201", Name: "S+U Zoologischer Garten", Type: "stop"}
+	ride := func(dep, arr, plannedDep, plannedArr string, cancelled bool) Leg {
+		return Leg{
+			Origin: alex, Destination: zoo,
+			Line:      &Line{Name: "S5", Product: "suburban"},
+			Departure: dep, PlannedDeparture: plannedDep,
+			Arrival: arr, PlannedArrival: plannedArr,
+			Cancelled: cancelled,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		legs         []Leg
+		ok           bool
+		leave, reach string
+		cancelled    bool
+	}{
+		{
+			name:  "on time",
+			legs:  []Leg{ride("2026-10-16T08:02:00+02:00", "2026-10-16T08:21:00+02:00", "2026-10-16T08:02:00+02:00", "2026-10-16T08:21:00+02:00", false)},
+			ok:    true,
+			leave: "08:02", reach: "08:21",
+		},
+		{
+			name: "delayed",
+			legs: []Leg{func() Leg {
+				l := ride("2026-10-16T08:04:00+02:00", "2026-10-16T08:23:00+02:00", "2026-10-16T08:02:00+02:00", "2026-10-16T08:21:00+02:00", false)
+				l.DepartureDelay, l.ArrivalDelay = &delay, &delay
+				return l
+			}()},
+			ok:    true,
+			leave: "08:04", reach: "08:23",
+		},
+		{
+			name:  "cancelled leg keeps its planned times",
+			legs:  []Leg{ride("", "", "2026-10-16T08:02:00+02:00", "2026-10-16T08:21:00+02:00", true)},
+			ok:    true,
+			leave: "08:02", reach: "08:21",
+			cancelled: true,
+		},
+		{
+			name: "no times at all",
+			legs: []Leg{ride("", "", "", "", false)},
+		},
+		{
+			name: "no legs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, ok := parseJourney(Journey{Legs: tt.legs})
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got := j.LeaveAt.Format("15:04"); got != tt.leave {
+				t.Errorf("LeaveAt = %s, want %s", got, tt.leave)
+			}
+			if got := j.ArriveAt.Format("15:04"); got != tt.reach {
+				t.Errorf("ArriveAt = %s, want %s", got, tt.reach)
+			}
+			if j.Cancelled() != tt.cancelled {
+				t.Errorf("Cancelled() = %v, want %v", j.Cancelled(), tt.cancelled)
+			}
+		})
+	}
+}
+
+func TestFakeJourneysKeepCancelled(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	f := NewFake(now)
+	journeys, err := f.S5Journeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cancelled int
+	for _, j := range journeys {
+		if !j.Cancelled() {
+			continue
+		}
+		cancelled++
+		// The fixture's fifth S5 leaves Warschauer 42 minutes in
+		if want := now.Add(42 * time.Minute); !j.LeaveAt.Equal(want) {
+			t.Errorf("cancelled journey leaves at %s, want %s", j.LeaveAt, want)
+		}
+	}
+	if cancelled != 1 {
+		t.Errorf("got %d cancelled journeys, want 1", cancelled)
+	}
+}
+
+func TestParseAccessibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		remarks []Remark
+		notes   int
+		outage  bool
+	}{
+		{"none", nil, 0, false},
+		{"unrelated", []Remark{{Type: "hint", Text: "Bicycle conveyance"}}, 0, false},
+		{"elevator working", []Remark{{Type: "hint", Text: "Aufzug zum Bahnsteig vorhanden"}}, 1, false},
+		{"elevator broken", []Remark{{Type: "warning", Summary: "Aufzug", Text: "Der Aufzug zu Gleis 2 ist außer Betrieb"}}, 1, true},
+		{"escalator in English", []Remark{{Type: "warning", Text: "Escalator to platform 1 out of service"}}, 1, true},
+		{"summary only", []Remark{{Type: "warning", Summary: "Elevator broken"}}, 1, true},
+		{"said twice", []Remark{{Text: "Lift out of order"}, {Text: "Lift out of order"}}, 1, true},
+		{"outage elsewhere", []Remark{{Text: "Aufzug vorhanden"}, {Text: "Signalstörung, Zug defekt"}}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, outage := parseAccessibility(tt.remarks)
+			if len(notes) != tt.notes || outage != tt.outage {
+				t.Errorf("got %q, outage %v, want %d notes, outage %v", notes, outage, tt.notes, tt.outage)
+			}
+		})
+	}
+}
+
+func TestParseLoadFactor(t *testing.T) {
+	tests := []struct{ factor, want string }{
+		{"low-to-medium", "low"},
+		{"high", "medium"},
+		{"very-high", "high"},
+		{"exceptionally-high", "high"},
+		{"", ""},
+		{"unknown", ""},
+	}
+	for _, tt := range tests {
+		if got := parseLoadFactor(tt.factor); got != tt.want {
+			t.Errorf("parseLoadFactor(%q) = %q, want %q", tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestParseRemarks(t *testing.T) {
+	tests := []struct {
+		name    string
+		remarks []Remark
+		want    []model.Remark
+	}{
+		{"none", nil, nil},
+		{"types kept", []Remark{{Type: "warning", Text: "Signal failure"}, {Type: "status", Summary: "Replacement service"}},
+			[]model.Remark{{Type: "warning", Text: "Signal failure"}, {Type: "status", Summary: "Replacement service"}}},
+		{"other types are hints", []Remark{{Type: "", Text: "Bicycle conveyance"}, {Type: "foreign-id", Text: "Tickets on board"}},
+			[]model.Remark{{Type: "hint", Text: "Bicycle conveyance"}, {Type: "hint", Text: "Tickets on board"}}},
+		{"empty ones dropped", []Remark{{Type: "warning", Code: "text.realtime"}, {Type: "hint", Text: "Wifi on board"}},
+			[]model.Remark{{Type: "hint", Text: "Wifi on board"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRemarks(tt.remarks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJourneysQuery(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"journeys":[]}`)
+	}))
+	defer srv.Close()
+	c := &HTTPClient{BaseURL: srv.URL, HTTP: srv.Client()}
+	limit := func(n int) *int { return &n }
+
+	tests := []struct {
+		name string
+		opts JourneyOptions
+		want map[string]string // "" for a parameter left out
+	}{
+		{"default transfers", JourneyOptions{}, map[string]string{"transfers": "3"}},
+		{"direct only", JourneyOptions{Transfers: limit(0)}, map[string]string{"transfers": "0"}},
+		{"one change", JourneyOptions{Transfers: limit(1)}, map[string]string{"transfers": "1"}},
+		{"products left out", JourneyOptions{Products: map[string]bool{"express": false, "regional": false, "suburban": true}},
+			map[string]string{"express": "false", "regional": "false", "suburban": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Journeys(context.Background(), "900120004", "900023201", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			for param, want := range tt.want {
+				if got := query.Get(param); got != want {
+					t.Errorf("%s = %q, want %q", param, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJourneysWalking(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"journeys":[{"legs":[
+			{"origin":{"type":"location","address":"Torstraße 1"},"destination":{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"},
+			 "departure":"2026-10-16T08:00:00+02:00","arrival":"2026-10-16T08:06:00+02:00","walking":true,"distance":450},
+			{"origin":{"type":"stop","id":"900100003","name":"S+U Alexanderplatz (Berlin)"},"destination":{"type":"stop","id":"900003201","name":"S+U Berlin Hauptbahnhof"},
+			 "departure":"2026-10-16T08:08:00+02:00","plannedDeparture":"2026-10-16T08:08:00+02:00","departureDelay":0,
+			 "arrival":"2026-10-16T08:13:00+02:00","plannedArrival":"2026-10-16T08:13:00+02:00","arrivalDelay":0,
+			 "tripId":"1","line":{"name":"S5","product":"suburban"}},
+			{"origin":{"type":"stop","id":"900003201","name":"S+U Berlin Hauptbahnhof"},"destination":{"type":"stop","id":"900003201","name":"S+U Berlin Hauptbahnhof"},
+			 "departure":"2026-10-16T08:13:00+02:00","arrival":"2026-10-16T08:17:00+02:00","walking":true,"distance":200},
+			{"origin":{"type":"stop","id":"900003201","name":"S+U Berlin Hauptbahnhof"},"destination":{"type":"stop","id":"900023201","name":"S+U Zoologischer Garten (Berlin)"},
+			 "departure":"2026-10-16T08:20:00+02:00","plannedDeparture":"2026-10-16T08:20:00+02:00","departureDelay":0,
+			 "arrival":"2026-10-16T08:28:00+02:00","plannedArrival":"2026-10-16T08:28:00+02:00","arrivalDelay":0,
+			 "tripId":"2","line":{"name":"S7","product":"suburban"}}
+		]}]}`)
+	}))
+	defer srv.Close()
+	c := &HTTPClient{BaseURL: srv.URL, HTTP: srv.Client()}
+
+	journeys, err := c.Journeys(context.Background(), "900100003", "900023201", JourneyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journeys) != 1 {
+		t.Fatalf("got %d journeys, want 1", len(journeys))
+	}
+	j := journeys[0]
+	if j.Walking != 10*time.Minute || j.WalkingDistance != 650 {
+		t.Errorf("walks %s and %d m, want 10m0s and 650 m", j.Walking, j.WalkingDistance)
+	}
+	if j.Walk == nil || j.Walk.From != "Torstraße 1" || j.Walk.Duration != 6*time.Minute {
+		t.Errorf("walk to the first stop is %+v", j.Walk)
+	}
+	if len(j.Legs) != 2 {
+		t.Errorf("got %d legs, want the two rides", len(j.Legs))
+	}
+}
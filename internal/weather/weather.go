@@ -0,0 +1,126 @@
+// Package weather fetches current conditions and the next hour's rain from
+// Open-Meteo, which needs no API key.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+const baseURL = "https://api.open-meteo.com/v1/forecast"
+
+// rainThreshold is the precipitation per quarter hour, in mm, that counts
+// as rain worth mentioning
+const rainThreshold = 0.1
+
+// Conditions is the weather at one place right now
+type Conditions struct {
+	Temperature float64
+	Code        int           // WMO weather code
+	RainIn      time.Duration // when rain starts within the next hour; -1 if it doesn't
+	Fetched     time.Time
+}
+
+type forecastResponse struct {
+	Current struct {
+		Temperature float64 `json:"temperature_2m"`
+		WeatherCode int     `json:"weather_code"`
+	} `json:"current"`
+	Minutely15 struct {
+		Time          []int64   `json:"time"`
+		Precipitation []float64 `json:"precipitation"`
+	} `json:"minutely_15"`
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Fetch gets the conditions at a place
+func Fetch(ctx context.Context, at model.Coordinates) (Conditions, error) {
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(at.Latitude, 'f', 4, 64))
+	params.Set("longitude", strconv.FormatFloat(at.Longitude, 'f', 4, 64))
+	params.Set("current", "temperature_2m,weather_code")
+	params.Set("minutely_15", "precipitation")
+	params.Set("forecast_minutely_15", "4")
+	params.Set("timeformat", "unixtime")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return Conditions{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Conditions{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Conditions{}, fmt.Errorf("weather: %s", resp.Status)
+	}
+
+	var fr forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&fr); err != nil {
+		return Conditions{}, err
+	}
+
+	now := time.Now()
+	c := Conditions{
+		Temperature: fr.Current.Temperature,
+		Code:        fr.Current.WeatherCode,
+		RainIn:      -1,
+		Fetched:     now,
+	}
+	for i, t := range fr.Minutely15.Time {
+		if i >= len(fr.Minutely15.Precipitation) {
+			break
+		}
+		if fr.Minutely15.Precipitation[i] >= rainThreshold {
+			c.RainIn = max(time.Unix(t, 0).Sub(now), 0)
+			break
+		}
+	}
+	return c, nil
+}
+
+// Icon is a symbol for the WMO weather code
+func (c Conditions) Icon() string {
+	switch {
+	case c.Code == 0:
+		return "☀"
+	case c.Code <= 3:
+		return "⛅"
+	case c.Code == 45 || c.Code == 48:
+		return "🌫"
+	case c.Code >= 71 && c.Code <= 77, c.Code == 85 || c.Code == 86:
+		return "❄"
+	case c.Code >= 95:
+		return "⛈"
+	case c.Code >= 51:
+		return "🌧"
+	}
+	return "?"
+}
+
+// Precipitating reports whether rain or snow is falling now
+func (c Conditions) Precipitating() bool {
+	return c.Code >= 51
+}
+
+// Summary is a short line like "☀ 18°C, rain in 30 min"
+func (c Conditions) Summary() string {
+	s := fmt.Sprintf("%s %.0f°C", c.Icon(), c.Temperature)
+	switch {
+	case c.Precipitating():
+	case c.RainIn == 0:
+		s += ", rain starting"
+	case c.RainIn > 0:
+		s += fmt.Sprintf(", rain in %d min", int(c.RainIn.Minutes()))
+	}
+	return s
+}
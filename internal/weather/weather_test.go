@@ -0,0 +1,30 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Conditions
+		want string
+	}{
+		{"clear and dry", Conditions{Temperature: 18.4, Code: 0, RainIn: -1}, "☀ 18°C"},
+		{"cloudy, rain coming", Conditions{Temperature: 11.6, Code: 3, RainIn: 30 * time.Minute}, "⛅ 12°C, rain in 30 min"},
+		{"rain starting", Conditions{Temperature: 9, Code: 2, RainIn: 0}, "⛅ 9°C, rain starting"},
+		{"already raining", Conditions{Temperature: 9, Code: 61, RainIn: 0}, "🌧 9°C"},
+		{"fog", Conditions{Temperature: 4, Code: 45, RainIn: -1}, "🌫 4°C"},
+		{"snow", Conditions{Temperature: -2.2, Code: 73, RainIn: -1}, "❄ -2°C"},
+		{"thunderstorm", Conditions{Temperature: 24, Code: 95, RainIn: -1}, "⛈ 24°C"},
+		{"unknown code", Conditions{Temperature: 15, Code: 20, RainIn: -1}, "? 15°C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Summary(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// meetCandidates is how many stops around the midpoint are tried when no
+// destination is given
+const meetCandidates = 3
+
+// meetRadius is how far from the midpoint those stops may be
+const meetRadius = 1500
+
+// meetPair is one journey from each origin to the same place, arriving
+// close together
+type meetPair struct {
+	mine, theirs model.Journey
+}
+
+// At is when both have arrived
+func (p meetPair) At() time.Time {
+	if p.mine.ArriveAt.After(p.theirs.ArriveAt) {
+		return p.mine.ArriveAt
+	}
+	return p.theirs.ArriveAt
+}
+
+// Gap is how long the first to arrive waits for the other
+func (p meetPair) Gap() time.Duration {
+	d := p.mine.ArriveAt.Sub(p.theirs.ArriveAt)
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+// unfairness is how much longer one side travels than the other
+func (p meetPair) unfairness() time.Duration {
+	d := p.mine.Duration - p.theirs.Duration
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+// runMeet plans journeys from two origins, one's own and a friend's, arriving
+// at the same place around the same time. Without a destination it tries
+// the stops around the midpoint and picks the one both reach soonest.
+func runMeet(args []string) int {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("meet", flag.ExitOnError)
+	from := fs.String("from", "", "my origin: station ID, name, alias or LAT,LON (default: last origin)")
+	with := fs.String("with", "", "my friend's origin: station ID, name, alias or LAT,LON")
+	within := fs.Duration("within", 10*time.Minute, "arrive at most this far apart")
+	cassette := cassetteFlags(fs)
+	fs.Parse(args)
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *with == "" || fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: berrrr meet --with STATION [--from STATION] [--within 10m] [DESTINATION]")
+		return 2
+	}
+
+	ctx := context.Background()
+	mine := cfg.LastOrigin
+	var err error
+	if *from != "" {
+		if mine, err = resolveStation(ctx, cfg, *from); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --from: %v\n", err)
+			return 2
+		}
+	}
+	theirs, err := resolveStation(ctx, cfg, *with)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --with: %v\n", err)
+		return 2
+	}
+
+	var candidates []model.Station
+	if fs.NArg() == 1 {
+		dest, err := resolveDestination(ctx, cfg, fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", fs.Arg(0), err)
+			return 2
+		}
+		candidates = []model.Station{dest}
+	} else if candidates, err = middleStops(ctx, mine, theirs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: finding a place in the middle: %v\n", err)
+		return 1
+	}
+
+	var routes []model.FavoriteRoute
+	for _, dest := range candidates {
+		routes = append(routes,
+			model.FavoriteRoute{Origin: mine, Dest: dest},
+			model.FavoriteRoute{Origin: theirs, Dest: dest})
+	}
+	results := vbb.FetchRoutes(ctx, vbb.Default, routes)
+
+	best, bestDest := []meetPair(nil), model.Station{}
+	for i, dest := range candidates {
+		a, b := results[2*i], results[2*i+1]
+		if a.Err != nil || b.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", model.CleanStation(dest.Name), firstErr(a.Err, b.Err))
+			continue
+		}
+		pairs := meetPairs(a.Journeys, b.Journeys, *within)
+		if len(pairs) > 0 && (best == nil || betterMeet(pairs[0], best[0])) {
+			best, bestDest = pairs, dest
+		}
+	}
+	if best == nil {
+		fmt.Printf("No journeys arriving within %s of each other\n", *within)
+		return 1
+	}
+
+	fmt.Printf("Meet at %s\n", model.CleanStation(bestDest.Name))
+	fmt.Printf("   %-24s %-24s %s\n", "Me from "+model.CleanStation(mine.Name), "Them from "+model.CleanStation(theirs.Name), "Both there")
+	for i, p := range best {
+		if i == 5 {
+			break
+		}
+		fmt.Printf("%2d %-24s %-24s %s (%d min apart)\n", i+1,
+			fmt.Sprintf("%s → %s", model.FormatTime(p.mine.LeaveAt), model.FormatTime(p.mine.ArriveAt)),
+			fmt.Sprintf("%s → %s", model.FormatTime(p.theirs.LeaveAt), model.FormatTime(p.theirs.ArriveAt)),
+			model.FormatTime(p.At()), int(p.Gap().Minutes()))
+	}
+	return 0
+}
+
+// meetPairs matches each upcoming journey in mine with the one in theirs
+// arriving closest to it, keeping those within the gap, soonest first
+func meetPairs(mine, theirs []model.Journey, within time.Duration) []meetPair {
+	now := time.Now()
+	var pairs []meetPair
+	for _, m := range mine {
+		if m.Cancelled() || m.LeaveAt.Before(now) {
+			continue
+		}
+		var match *meetPair
+		for _, t := range theirs {
+			if t.Cancelled() || t.LeaveAt.Before(now) {
+				continue
+			}
+			p := meetPair{mine: m, theirs: t}
+			if p.Gap() <= within && (match == nil || p.Gap() < match.Gap()) {
+				match = &p
+			}
+		}
+		if match != nil {
+			pairs = append(pairs, *match)
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return betterMeet(pairs[i], pairs[j]) })
+	return pairs
+}
+
+// betterMeet prefers meeting sooner, then travelling about as long
+func betterMeet(a, b meetPair) bool {
+	if !a.At().Equal(b.At()) {
+		return a.At().Before(b.At())
+	}
+	return a.unfairness() < b.unfairness()
+}
+
+// middleStops are the stops closest to halfway between two origins
+func middleStops(ctx context.Context, a, b model.Station) ([]model.Station, error) {
+	var at [2]model.Coordinates
+	for i, s := range []model.Station{a, b} {
+		if s.Location != nil {
+			at[i] = *s.Location
+			continue
+		}
+		c, err := vbb.Default.Geocode(ctx, s.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", model.CleanStation(s.Name), err)
+		}
+		at[i] = c
+	}
+	mid := model.Coordinates{
+		Latitude:  (at[0].Latitude + at[1].Latitude) / 2,
+		Longitude: (at[0].Longitude + at[1].Longitude) / 2,
+	}
+	stops, err := vbb.Default.Nearby(ctx, mid, meetRadius)
+	if err != nil {
+		return nil, err
+	}
+	if len(stops) == 0 {
+		return nil, fmt.Errorf("no stops within %d m of the midpoint", meetRadius)
+	}
+	var stations []model.Station
+	for i, s := range stops {
+		if i == meetCandidates {
+			break
+		}
+		stations = append(stations, s.Station)
+	}
+	return stations, nil
+}
+
+// firstErr is the first non-nil error
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestMeetPairs(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	// journey leaves in leave minutes and arrives in arrive
+	journey := func(leave, arrive int) model.Journey {
+		return model.Journey{
+			LeaveAt:  now.Add(time.Duration(leave) * time.Minute),
+			ArriveAt: now.Add(time.Duration(arrive) * time.Minute),
+			Duration: time.Duration(arrive-leave) * time.Minute,
+		}
+	}
+	cancelled := func(j model.Journey) model.Journey {
+		j.Legs = []model.Leg{{Cancelled: true}}
+		return j
+	}
+
+	tests := []struct {
+		name         string
+		mine, theirs []model.Journey
+		want         []string // my leaving-arriving/their arriving, in minutes
+	}{
+		{"closest match", []model.Journey{journey(5, 30)}, []model.Journey{journey(0, 20), journey(10, 28), journey(15, 33)}, []string{"5-30/28"}},
+		{"too far apart", []model.Journey{journey(5, 30)}, []model.Journey{journey(0, 20), journey(30, 50)}, nil},
+		{"soonest first", []model.Journey{journey(20, 45), journey(5, 30)}, []model.Journey{journey(10, 31), journey(20, 44)}, []string{"5-30/31", "20-45/44"}},
+		{"same time, fairer first", []model.Journey{journey(5, 30), journey(15, 30)}, []model.Journey{journey(15, 30)}, []string{"15-30/30", "5-30/30"}},
+		{"gone", []model.Journey{journey(-5, 30)}, []model.Journey{journey(5, 30), journey(-2, 30)}, nil},
+		{"cancelled", []model.Journey{journey(5, 30)}, []model.Journey{cancelled(journey(5, 30)), journey(5, 33)}, []string{"5-30/33"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range meetPairs(tt.mine, tt.theirs, 5*time.Minute) {
+				got = append(got, fmt.Sprintf("%d-%d/%d", int(p.mine.LeaveAt.Sub(now).Minutes()), int(p.mine.ArriveAt.Sub(now).Minutes()), int(p.theirs.ArriveAt.Sub(now).Minutes())))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go-commute/internal/config"
+	"go-commute/internal/model"
+	"go-commute/internal/vbb"
+)
+
+// catchable is a departure that can still be reached on foot
+type catchable struct {
+	dep     model.Departure
+	stop    model.NearbyStop
+	leaveAt time.Time // when to start walking
+}
+
+// runNearby prints the departures from all stops around a position that can
+// still be caught, ordered by when to leave
+func runNearby(args []string) int {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("nearby", flag.ExitOnError)
+	radius := fs.Int("radius", 500, "search stops within this many meters")
+	walk := fs.Int("walk", 80, "walking speed in meters per minute")
+	cassette := cassetteFlags(fs)
+	fs.Parse(args)
+	if err := cassette(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	var at model.Coordinates
+	switch {
+	case fs.NArg() == 1:
+		var err error
+		if at, err = model.ParseCoordinates(fs.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	case fs.NArg() == 0 && cfg.Location != nil:
+		at = *cfg.Location
+	default:
+		fmt.Fprintln(os.Stderr, "Usage: berrrr nearby [--radius 500] [--walk 80] [LAT,LON]")
+		fmt.Fprintln(os.Stderr, "Without LAT,LON the \"location\" from the config is used.")
+		return 2
+	}
+	if *walk <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --walk must be positive")
+		return 2
+	}
+
+	ctx := context.Background()
+	stops, err := vbb.Default.Nearby(ctx, at, *radius)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(stops) == 0 {
+		fmt.Printf("No stops within %d m\n", *radius)
+		return 0
+	}
+
+	stations := make([]model.Station, len(stops))
+	for i, s := range stops {
+		stations[i] = s.Station
+	}
+	now := time.Now()
+	var board []catchable
+	for i, res := range vbb.FetchBoards(ctx, vbb.Default, stations) {
+		if res.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", model.CleanStation(res.Station.Name), res.Err)
+			continue
+		}
+		walkTime := time.Duration(stops[i].Distance) * time.Minute / time.Duration(*walk)
+		for _, d := range res.Departures {
+			if d.Cancelled {
+				continue
+			}
+			leaveAt := d.When.Add(-walkTime)
+			if leaveAt.Before(now) {
+				continue
+			}
+			board = append(board, catchable{dep: d, stop: stops[i], leaveAt: leaveAt})
+		}
+	}
+	board = latestPerTrip(board)
+	sort.Slice(board, func(i, j int) bool { return board[i].leaveAt.Before(board[j].leaveAt) })
+
+	if len(board) == 0 {
+		fmt.Println("Nothing left to catch nearby")
+		return 0
+	}
+	fmt.Printf("%-8s %-9s %-6s %-28s %s\n", "Leave", "Dep", "Line", "Direction", "From")
+	for _, c := range board {
+		delay := ""
+		if c.dep.Delay >= 60 {
+			delay = fmt.Sprintf(" +%d", c.dep.Delay/60)
+		}
+		fmt.Printf("%-8s %-9s %-6s %-28.28s %s (%d m)\n",
+			fmt.Sprintf("%d min", int(c.leaveAt.Sub(now).Minutes())),
+			model.FormatTime(c.dep.When)+delay, c.dep.Line,
+			model.CleanStation(c.dep.Direction),
+			model.CleanStation(c.stop.Name), c.stop.Distance)
+	}
+	return 0
+}
+
+// latestPerTrip keeps one entry per trip: a bus passing several nearby stops
+// is best caught where there is the most time to spare
+func latestPerTrip(board []catchable) []catchable {
+	best := make(map[string]int)
+	var kept []catchable
+	for _, c := range board {
+		if c.dep.TripID == "" {
+			kept = append(kept, c)
+			continue
+		}
+		if i, ok := best[c.dep.TripID]; ok {
+			if c.leaveAt.After(kept[i].leaveAt) {
+				kept[i] = c
+			}
+			continue
+		}
+		best[c.dep.TripID] = len(kept)
+		kept = append(kept, c)
+	}
+	return kept
+}
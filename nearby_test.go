@@ -0,0 +1,43 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-commute/internal/model"
+)
+
+func TestLatestPerTrip(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, model.DisplayZone)
+	at := func(trip, stop string, leaveIn int) catchable {
+		return catchable{
+			dep:     model.Departure{TripID: trip, Line: "M13"},
+			stop:    model.NearbyStop{Station: model.Station{Name: stop}},
+			leaveAt: now.Add(time.Duration(leaveIn) * time.Minute),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		board []catchable
+		want  []string // trip@stop
+	}{
+		{"one stop each", []catchable{at("a", "Warschauer", 2), at("b", "Warschauer", 5)}, []string{"a@Warschauer", "b@Warschauer"}},
+		{"more time at the next stop", []catchable{at("a", "Warschauer", 2), at("a", "Revaler", 4)}, []string{"a@Revaler"}},
+		{"less time at the next stop", []catchable{at("a", "Revaler", 4), at("a", "Warschauer", 2)}, []string{"a@Revaler"}},
+		{"keeps the order", []catchable{at("a", "Warschauer", 2), at("b", "Warschauer", 3), at("a", "Revaler", 4)}, []string{"a@Revaler", "b@Warschauer"}},
+		{"without trip IDs", []catchable{at("", "Warschauer", 2), at("", "Revaler", 4)}, []string{"@Warschauer", "@Revaler"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range latestPerTrip(tt.board) {
+				got = append(got, c.dep.TripID+"@"+c.stop.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}